@@ -1,25 +1,56 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/katana/fortuna/backend-go/internal/config"
 	"github.com/katana/fortuna/backend-go/internal/config/logger"
 
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	hand_admin "github.com/katana/fortuna/backend-go/internal/handler/admin"
+	hand_agenda "github.com/katana/fortuna/backend-go/internal/handler/agenda"
+	hand_aposta "github.com/katana/fortuna/backend-go/internal/handler/aposta"
+	hand_bolao "github.com/katana/fortuna/backend-go/internal/handler/bolao"
+	hand_carteira "github.com/katana/fortuna/backend-go/internal/handler/carteira"
 	hand_cliente "github.com/katana/fortuna/backend-go/internal/handler/cliente"
+	hand_conciliacao "github.com/katana/fortuna/backend-go/internal/handler/conciliacao"
+	hand_meiopag "github.com/katana/fortuna/backend-go/internal/handler/meiopag"
+	hand_pix "github.com/katana/fortuna/backend-go/internal/handler/pix"
+	hand_premiacao "github.com/katana/fortuna/backend-go/internal/handler/premiacao"
+	hand_regra "github.com/katana/fortuna/backend-go/internal/handler/regra"
+	hand_rifa "github.com/katana/fortuna/backend-go/internal/handler/rifa"
+	hand_saque "github.com/katana/fortuna/backend-go/internal/handler/saque"
 
 	hand_sorteio "github.com/katana/fortuna/backend-go/internal/handler/sorteio"
 	hand_usr "github.com/katana/fortuna/backend-go/internal/handler/user"
 
 	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/psp"
 	"github.com/katana/fortuna/backend-go/pkg/adapter/rabbitmq"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/redisdb"
 
 	"github.com/katana/fortuna/backend-go/pkg/server"
 
+	service_aovivo "github.com/katana/fortuna/backend-go/pkg/service/aovivo"
+
 	service_usr "github.com/katana/fortuna/backend-go/pkg/service/user"
 
+	service_agenda "github.com/katana/fortuna/backend-go/pkg/service/agenda"
+	service_aposta "github.com/katana/fortuna/backend-go/pkg/service/aposta"
+	service_bolao "github.com/katana/fortuna/backend-go/pkg/service/bolao"
+	service_carteira "github.com/katana/fortuna/backend-go/pkg/service/carteira"
 	service_cliente "github.com/katana/fortuna/backend-go/pkg/service/cliente"
+	service_conciliacao "github.com/katana/fortuna/backend-go/pkg/service/conciliacao"
+	service_meiopag "github.com/katana/fortuna/backend-go/pkg/service/meiopag"
+	service_pix "github.com/katana/fortuna/backend-go/pkg/service/pix"
+	service_premiacao "github.com/katana/fortuna/backend-go/pkg/service/premiacao"
+	service_regra "github.com/katana/fortuna/backend-go/pkg/service/regra"
+	service_rifa "github.com/katana/fortuna/backend-go/pkg/service/rifa"
+	service_saque "github.com/katana/fortuna/backend-go/pkg/service/saque"
 	service_sorteio "github.com/katana/fortuna/backend-go/pkg/service/sorteio"
 
 	"github.com/go-chi/chi/v5"
@@ -33,23 +64,60 @@ var (
 )
 
 func main() {
-	fila := []rabbitmq.Fila{
-		{
-			Name:    "QUEUE_PRDS_PARA_COTACAOQUEUE_PROCESSAR_APOSTA",
-			Durable: true,
-		},
-	}
 	logger.Info("start Application Fortuna fast API")
 	conf := config.NewConfig()
+	if err := conf.Validar(); err != nil {
+		log.Fatalf("Config: %v", err)
+	}
+
+	fila := []rabbitmq.Fila{
+		rabbitmq.FilaConsumo(conf.ConsumerConfig),
+	}
 
 	mogDbConn := mongodb.New(conf)
 	rbtMQConn := rabbitmq.NewRabbitMQ(fila, conf)
-	//rdisConn := redisdb.NewRedisClient(conf)
+	if err := rbtMQConn.Connect(); err != nil {
+		log.Fatalf("RabbitMQ connection failed: %v", err)
+	}
+	rdisConn := redisdb.NewRedisClient(conf)
 	usr_service := service_usr.NewUsuarioservice(mogDbConn)
 
 	cli_service := service_cliente.NewClienteervice(mogDbConn)
 
-	sor_service := service_sorteio.NewSorteioService(rbtMQConn, mogDbConn)
+	sor_service := service_sorteio.NewSorteioService(rbtMQConn, mogDbConn, conf)
+
+	aov_service := service_aovivo.NewAoVivoService(rdisConn, sor_service, conf)
+	sor_service.AoTransicionar("ao_vivo", aov_service.Transicao)
+
+	agd_service := service_agenda.NewAgendaService(mogDbConn)
+
+	rgr_service := service_regra.NewRegraService(mogDbConn)
+
+	car_service := service_carteira.NewCarteiraService(mogDbConn)
+	sor_service.AoTransicionar("estorno", car_service.EstornarSorteio)
+
+	provedorPix, err := psp.NewPSP(conf)
+	if err != nil {
+		log.Fatalf("PSP Pix: %v", err)
+	}
+	mpg_service := service_meiopag.NewMeioPagService(mogDbConn)
+
+	pix_service := service_pix.NewPixService(mogDbConn, provedorPix, car_service, mpg_service, conf)
+
+	saq_service := service_saque.NewSaqueService(mogDbConn, provedorPix, car_service, mpg_service, conf)
+
+	cnc_service := service_conciliacao.NewConciliacaoService(mogDbConn)
+
+	rifa_service := service_rifa.NewRifaService(mogDbConn, rdisConn, sor_service, rgr_service, car_service, conf)
+
+	apt_service := service_aposta.NewApostaService(rbtMQConn, mogDbConn, sor_service, rgr_service, car_service, conf)
+
+	blo_service := service_bolao.NewBolaoService(mogDbConn, sor_service, apt_service, car_service)
+	sor_service.AoLiquidar("bolao", blo_service.LiquidarSorteio)
+
+	// Depois dos bolões, que definem a parte de cada cota.
+	prm_service := service_premiacao.NewPremiacaoService(mogDbConn, sor_service, car_service, conf)
+	sor_service.AoLiquidar("premiacao", prm_service.PagarSorteio)
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -59,17 +127,43 @@ func main() {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Idempotency-Key"},
+		ExposedHeaders:   []string{"Link", "Location", "Idempotent-Replayed"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
 
 	r.Get("/", healthcheck)
-	hand_usr.RegisterUsuarioAPIHandlers(r, usr_service)
+	hand_usr.RegisterUsuarioAPIHandlers(r, usr_service, cli_service)
 
 	hand_cliente.RegisterClientePIHandlers(r, cli_service)
-	hand_sorteio.RegisterSorteioPIHandlers(r, sor_service)
+	hand_meiopag.RegisterMeioPagAPIHandlers(r, mpg_service, conf)
+	hand_sorteio.RegisterSorteioPIHandlers(r, sor_service, aov_service, conf)
+	hand_agenda.RegisterAgendaAPIHandlers(r, agd_service, conf)
+	hand_regra.RegisterRegraAPIHandlers(r, rgr_service, conf)
+	idempotencia := handler.Idempotencia(rdisConn, time.Duration(conf.IdempotenciaHoras)*time.Hour)
+
+	hand_rifa.RegisterRifaAPIHandlers(r, rifa_service, conf, idempotencia)
+	hand_aposta.RegisterApostaAPIHandlers(r, apt_service, conf, idempotencia)
+	hand_bolao.RegisterBolaoAPIHandlers(r, blo_service, conf, idempotencia)
+	hand_carteira.RegisterCarteiraAPIHandlers(r, car_service, conf, idempotencia)
+	hand_pix.RegisterPixAPIHandlers(r, pix_service, conf, idempotencia, conf.Mode != config.PRODUCTION)
+	hand_saque.RegisterSaqueAPIHandlers(r, saq_service, conf, provedorPix, idempotencia)
+	hand_premiacao.RegisterPremiacaoAPIHandlers(r, prm_service, conf)
+	hand_conciliacao.RegisterConciliacaoAPIHandlers(r, cnc_service, conf)
+	hand_admin.RegisterAdminAPIHandlers(r, conf, rbtMQConn)
+
+	if conf.SchedulerIntervalo > 0 {
+		scheduler := service_agenda.NewScheduler(mogDbConn, agd_service, sor_service, time.Duration(conf.SchedulerIntervalo)*time.Second)
+		go scheduler.Run(context.Background())
+	}
+
+	// A API só publica; o aceite das apostas roda no cmd/worker.
+	go func() {
+		if err := rbtMQConn.Manter(conf.ConsumerConfig.Reconnect.MaxAttempt, conf.ConsumerConfig.Reconnect.Interval, nil); err != nil {
+			log.Fatalf("RabbitMQ connection lost: %v", err)
+		}
+	}()
 
 	srv := server.NewHTTPServer(r, conf)
 
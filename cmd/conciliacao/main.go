@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+
+	service_conciliacao "github.com/katana/fortuna/backend-go/pkg/service/conciliacao"
+)
+
+var (
+	VERSION = "0.1.0-dev"
+	COMMIT  = "ABCDEFG-dev"
+)
+
+// O comando concilia um extrato de liquidação do PSP, em CSV ou OFX, com os
+// depósitos e saques Pix, grava a execução e imprime o resultado em JSON.
+// Sai com código 1 se a conciliação não roda e 2 se há itens pendentes.
+func main() {
+	arquivo := flag.String("arquivo", "", "extrato do PSP (.csv ou .ofx)")
+	formato := flag.String("formato", "", "csv ou ofx; sem ele vale a extensão do arquivo")
+	usuario := flag.String("usuario", "conciliacao", "responsável registrado na execução")
+	itens := flag.Bool("itens", false, "imprime os itens além do resumo")
+	timeout := flag.Duration("timeout", 5*time.Minute, "tempo máximo da conciliação")
+	flag.Parse()
+
+	if *arquivo == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	logger.Info("start Fortuna conciliacao " + VERSION + " " + COMMIT)
+	conf := config.NewConfig()
+
+	extrato, err := os.Open(*arquivo)
+	if err != nil {
+		log.Fatalf("Extrato: %v", err)
+	}
+	defer extrato.Close()
+
+	mogDbConn := mongodb.New(conf)
+	cnc_service := service_conciliacao.NewConciliacaoService(mogDbConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	cnc, err := cnc_service.Conciliar(ctx, *arquivo, *formato, extrato, *usuario)
+	if err != nil {
+		log.Fatalf("Conciliação falhou: %v", err)
+	}
+
+	if !*itens {
+		cnc.Itens = nil
+	}
+	saida := json.NewEncoder(os.Stdout)
+	saida.SetIndent("", "  ")
+	saida.Encode(cnc)
+
+	resumo := cnc.Resumo
+	if resumo.Divergentes+resumo.Duplicados+resumo.SemRegistro+resumo.Ausentes > 0 {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+
+	hand_aposta "github.com/katana/fortuna/backend-go/internal/handler/aposta"
+
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/rabbitmq"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/redisdb"
+
+	service_aovivo "github.com/katana/fortuna/backend-go/pkg/service/aovivo"
+	service_aposta "github.com/katana/fortuna/backend-go/pkg/service/aposta"
+	service_carteira "github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	service_regra "github.com/katana/fortuna/backend-go/pkg/service/regra"
+	service_sorteio "github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+)
+
+var (
+	VERSION = "0.1.0-dev"
+	COMMIT  = "ABCDEFG-dev"
+)
+
+// O worker consome a fila de apostas com ConsumerCount consumidores e
+// prefetch de PrefetchCount, aceitando ou rejeitando cada aposta pendente.
+func main() {
+	logger.Info("start Application Fortuna fast Worker")
+	conf := config.NewConfig()
+	if err := conf.Validar(); err != nil {
+		log.Fatalf("Config: %v", err)
+	}
+
+	fila := []rabbitmq.Fila{
+		rabbitmq.FilaConsumo(conf.ConsumerConfig),
+	}
+
+	mogDbConn := mongodb.New(conf)
+	rbtMQConn := rabbitmq.NewRabbitMQ(fila, conf)
+	if err := rbtMQConn.Connect(); err != nil {
+		log.Fatalf("RabbitMQ connection failed: %v", err)
+	}
+
+	sor_service := service_sorteio.NewSorteioService(rbtMQConn, mogDbConn, conf)
+	rgr_service := service_regra.NewRegraService(mogDbConn)
+	car_service := service_carteira.NewCarteiraService(mogDbConn)
+	apt_service := service_aposta.NewApostaService(rbtMQConn, mogDbConn, sor_service, rgr_service, car_service, conf)
+
+	// Os totais de cada aposta aceita seguem pelo Redis para o painel ao vivo da API.
+	rdisConn := redisdb.NewRedisClient(conf)
+	aov_service := service_aovivo.NewAoVivoService(rdisConn, sor_service, conf)
+	apt_service.AoAceitar(aov_service.VendaAceita)
+
+	consumir := func() error {
+		return rbtMQConn.ConsumirPool(conf.ConsumerConfig, hand_aposta.ConsumirApostas(apt_service, rbtMQConn, conf.ConsumerConfig.QueueName))
+	}
+	if err := consumir(); err != nil {
+		log.Fatalf("Consumer failed to start: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	falha := make(chan error, 1)
+	go func() {
+		falha <- rbtMQConn.Manter(conf.ConsumerConfig.Reconnect.MaxAttempt, conf.ConsumerConfig.Reconnect.Interval, consumir)
+	}()
+
+	log.Printf("Worker Run on [Queue: %s], [Consumers: %d], [Prefetch: %d], [Version: %s], [Commit: %s]",
+		conf.ConsumerConfig.QueueName, conf.ConsumerConfig.ConsumerCount, conf.ConsumerConfig.PrefetchCount, VERSION, COMMIT)
+
+	select {
+	case <-ctx.Done():
+		// Mensagens sem ack voltam para a fila; o processamento é idempotente.
+		logger.Info("stop Application Fortuna fast Worker")
+	case err := <-falha:
+		log.Fatalf("RabbitMQ connection lost: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/rabbitmq"
+)
+
+const limiteEstacionadas = 100
+
+func getAllEstacionadas(rbt rabbitmq.RabbitInterface, fila string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		limite, _ := strconv.Atoi(r.URL.Query().Get("limite"))
+		if limite <= 0 || limite > limiteEstacionadas {
+			limite = limiteEstacionadas
+		}
+
+		result, err := rbt.ListarEstacionadas(r.Context(), fila, limite)
+		if err != nil {
+			logger.Error("erro ao listar mensagens estacionadas", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error ao listar mensagens estacionadas", "codigo": 500}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func reprocessarEstacionada(rbt rabbitmq.RabbitInterface, fila string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := rbt.ReprocessarEstacionada(r.Context(), fila, chi.URLParam(r, "id"))
+		responderEstacionada(w, err, `{"MSG": "Mensagem devolvida para a fila", "codigo": 200}`)
+	}
+}
+
+func descartarEstacionada(rbt rabbitmq.RabbitInterface, fila string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := rbt.DescartarEstacionada(r.Context(), fila, chi.URLParam(r, "id"))
+		responderEstacionada(w, err, `{"MSG": "Mensagem descartada", "codigo": 200}`)
+	}
+}
+
+func responderEstacionada(w http.ResponseWriter, err error, sucesso string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		if errors.Is(err, rabbitmq.ErrMensagemNaoEncontrada) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Mensagem não encontrada", "codigo": 404}`))
+			return
+		}
+		logger.Error("erro ao tratar mensagem estacionada", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao tratar mensagem estacionada", "codigo": 500}`))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(sucesso))
+}
@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/simulacao"
+)
+
+func simularJogo() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		params := model.ParametrosSimulacao{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			logger.Error("erro ao decodificar parâmetros da simulação", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Parâmetros da simulação inválidos", "codigo": 400}`))
+			return
+		}
+
+		result, err := simulacao.Simular(r.Context(), params)
+		if err != nil {
+			if errors.Is(err, simulacao.ErrParametrosInvalidos) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+				return
+			}
+			logger.Error("erro ao simular jogo", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error ao simular jogo", "codigo": 500}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/rabbitmq"
+)
+
+// RegisterAdminAPIHandlers registra as rotas administrativas; todas exigem
+// token com role admin.
+func RegisterAdminAPIHandlers(r chi.Router, conf *config.Config, rbt rabbitmq.RabbitInterface) {
+	r.Route("/api/v1/admin", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(conf.TokenAuth))
+		r.Use(handler.ExigirRole("admin"))
+
+		r.Post("/simulacao", simularJogo())
+
+		fila := conf.ConsumerConfig.QueueName
+		r.Get("/apostas/estacionadas", getAllEstacionadas(rbt, fila))
+		r.Post("/apostas/estacionadas/{id}/reprocessar", reprocessarEstacionada(rbt, fila))
+		r.Post("/apostas/estacionadas/{id}/descartar", descartarEstacionada(rbt, fila))
+	})
+}
@@ -0,0 +1,128 @@
+package agenda
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/agenda"
+)
+
+func createAgenda(service agenda.AgendaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		agd := &model.Agenda{}
+
+		err := json.NewDecoder(r.Body).Decode(agd)
+		if err != nil {
+			logger.Error("error decoding request body", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		result, err := service.Create(r.Context(), *agd)
+		if err != nil {
+			if errors.Is(err, agenda.ErrAgendaInvalida) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			logger.Error("erro ao acessar a camada de service da agenda", err)
+			http.Error(w, "Error ou salvar Agenda", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func updateAgenda(service agenda.AgendaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idp := chi.URLParam(r, "id")
+
+		agd := &model.Agenda{}
+		err := json.NewDecoder(r.Body).Decode(agd)
+		if err != nil {
+			logger.Error("error decoding request body", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		_, err = service.Update(r.Context(), idp, agd)
+		if err != nil {
+			switch {
+			case errors.Is(err, agenda.ErrAgendaNaoEncontrada):
+				http.Error(w, "Agenda nao encontrada", http.StatusNotFound)
+			case errors.Is(err, agenda.ErrAgendaInvalida):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				logger.Error("erro ao acessar a camada de service da agenda no upd", err)
+				http.Error(w, "Error ao atualizar agenda", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": "Success", "codigo": 1})
+	}
+}
+
+func getByIdAgenda(service agenda.AgendaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		idp := chi.URLParam(r, "id")
+		result, err := service.GetByID(r.Context(), idp)
+		if err != nil {
+			logger.Error("erro ao acessar a camada de service da agenda no por id", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Agenda não encontrada", "codigo": 404}`))
+			return
+		}
+
+		err = json.NewEncoder(w).Encode(result)
+		if err != nil {
+			logger.Error("erro ao converter em json", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error to parse Agenda to JSON", "codigo": 500}`))
+			return
+		}
+	}
+}
+
+func getAllAgenda(service agenda.AgendaServiceInterface) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		filters := model.FilterAgenda{
+			Nome:    r.URL.Query().Get("nome"),
+			Jogo:    r.URL.Query().Get("jogo"),
+			Enabled: r.URL.Query().Get("enabled"),
+		}
+
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+		result, err := service.GetAll(r.Context(), filters, limit, page)
+		if err != nil {
+			logger.Error("erro ao acessar a camada de service da agenda no all", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Agenda not found", "codigo": 404}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		err = json.NewEncoder(w).Encode(result)
+		if err != nil {
+			logger.Error("erro ao converter para json", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error to parse Agenda to JSON", "codigo": 500}`))
+			return
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package agenda
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/agenda"
+)
+
+// RegisterAgendaAPIHandlers registra as rotas da agenda de sorteios. A
+// consulta é pública; cadastro e alteração exigem token com role admin.
+func RegisterAgendaAPIHandlers(r chi.Router, service agenda.AgendaServiceInterface, conf *config.Config) {
+	r.Route("/api/v1/agenda", func(r chi.Router) {
+		r.Get("/getbyid/{id}", getByIdAgenda(service))
+		r.Get("/all", func(w http.ResponseWriter, r *http.Request) {
+			handler := getAllAgenda(service)
+			handler.ServeHTTP(w, r)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+			r.Use(handler.ExigirRole("admin"))
+
+			r.Post("/add", createAgenda(service))
+			r.Put("/update/{id}", updateAgenda(service))
+		})
+	})
+}
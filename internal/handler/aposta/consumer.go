@@ -0,0 +1,44 @@
+package aposta
+
+import (
+	"context"
+	"errors"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/rabbitmq"
+	"github.com/katana/fortuna/backend-go/pkg/service/aposta"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// ConsumirApostas devolve o callback da fila de apostas. Mensagens que nunca
+// vão ser processadas são rejeitadas e caem no parking pelo dead-letter; erros
+// transitórios seguem a política de retry da fila. Se nem o retry consegue
+// publicar, a mensagem também é rejeitada para o parking em vez de voltar à
+// fila, onde seria entregue de novo sem fim.
+func ConsumirApostas(service aposta.ApostaServiceInterface, rbt rabbitmq.RabbitInterface, fila string) func(msg *amqp.Delivery) {
+	return func(msg *amqp.Delivery) {
+		ctx := context.Background()
+
+		result, err := service.ProcessarMensagem(ctx, msg.Body)
+		if err != nil {
+			if errors.Is(err, aposta.ErrApostaInvalida) || errors.Is(err, aposta.ErrApostaNaoEncontrada) {
+				logger.Error("mensagem de aposta rejeitada", err)
+				msg.Nack(false, false)
+				return
+			}
+			logger.Error("erro ao processar aposta, agendando nova tentativa", err)
+			if errRetry := rbt.Retentar(ctx, fila, msg, err); errRetry != nil {
+				logger.Error("erro ao agendar nova tentativa da aposta, estacionando", errRetry)
+				msg.Nack(false, false)
+			}
+			return
+		}
+
+		logger.Info("aposta processada",
+			zap.String("aposta_id", result.ID.Hex()),
+			zap.String("status", result.Status),
+			zap.String("motivo", result.Motivo))
+		msg.Ack(false)
+	}
+}
@@ -0,0 +1,213 @@
+package aposta
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/aposta"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/regras"
+)
+
+// createAposta responde 202: a aposta fica pendente até o consumidor da fila
+// aceitá-la ou rejeitá-la, e o cliente acompanha por GET /api/v1/aposta/{id}.
+// O apostador é sempre o cliente do token, nunca o do corpo.
+func createAposta(service aposta.ApostaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		apt := &model.Aposta{}
+		if err := json.NewDecoder(r.Body).Decode(apt); err != nil {
+			logger.Error("error decoding request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Dados da aposta inválidos", "codigo": 400}`))
+			return
+		}
+		apt.ClienteID, _ = handler.ClienteDoToken(r)
+
+		result, err := service.Create(r.Context(), *apt)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.Header().Set("Location", "/api/v1/aposta/"+result.ID.Hex())
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// createSurpresinha responde 202 com as apostas geradas, todas pendentes como
+// em createAposta. Se o registro parar no meio, as apostas já registradas
+// seguem na fila e a resposta traz só elas.
+func createSurpresinha(service aposta.ApostaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		pedido := &model.PedidoSurpresinha{}
+		if err := json.NewDecoder(r.Body).Decode(pedido); err != nil {
+			logger.Error("error decoding request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Dados da surpresinha inválidos", "codigo": 400}`))
+			return
+		}
+		pedido.ClienteID, _ = handler.ClienteDoToken(r)
+
+		result, err := service.Surpresinha(r.Context(), *pedido)
+		if err != nil && len(result) == 0 {
+			responderErro(w, err)
+			return
+		}
+		if err != nil {
+			logger.Error("surpresinha registrada em parte", err)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getByIdAposta(service aposta.ApostaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.GetByID(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			if errors.Is(err, aposta.ErrApostaNaoEncontrada) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"MSG": "Aposta não encontrada", "codigo": 404}`))
+				return
+			}
+			logger.Error("erro ao acessar a camada de service da aposta no por id", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error ao consultar aposta", "codigo": 500}`))
+			return
+		}
+		if !handler.PodeAcessarCliente(r, result.ClienteID) {
+			handler.ErroHttpMsgAcessoNegado.Write(w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	validacao := &regras.ErroValidacao{}
+	switch {
+	case errors.Is(err, sorteio.ErrSorteioNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+	case errors.As(err, &validacao):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": "Aposta não cumpre as regras do jogo", "codigo": 422, "erros": validacao.Erros})
+	case errors.Is(err, aposta.ErrApostaInvalida):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	case errors.Is(err, aposta.ErrFilaIndisponivel):
+		logger.Error("erro ao enfileirar aposta", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"MSG": "Processamento de apostas indisponível", "codigo": 503}`))
+	default:
+		logger.Error("erro ao acessar a camada de service da aposta", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao registrar aposta", "codigo": 500}`))
+	}
+}
+
+func getComprovante(service aposta.ApostaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if !acessarAposta(w, r, service, chi.URLParam(r, "id")) {
+			return
+		}
+
+		result, err := service.Comprovante(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErroComprovante(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getComprovantePDF(service aposta.ApostaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		if !acessarAposta(w, r, service, id) {
+			return
+		}
+
+		result, err := service.ComprovantePDF(r.Context(), id)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			responderErroComprovante(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `inline; filename="comprovante-`+id+`.pdf"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(result)
+	}
+}
+
+// acessarAposta confere se o token é do dono da aposta ou de um admin e, se
+// não for, já responde o erro.
+func acessarAposta(w http.ResponseWriter, r *http.Request, service aposta.ApostaServiceInterface, ID string) bool {
+	apt, err := service.GetByID(r.Context(), ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		responderErroComprovante(w, err)
+		return false
+	}
+	if !handler.PodeAcessarCliente(r, apt.ClienteID) {
+		handler.ErroHttpMsgAcessoNegado.Write(w)
+		return false
+	}
+	return true
+}
+
+// verificarAposta é pública: o revendedor informa o código impresso ou o
+// conteúdo lido do QR e recebe o bilhete com o status atual.
+func verificarAposta(service aposta.ApostaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.Verificar(r.Context(), chi.URLParam(r, "code"))
+		if err != nil {
+			responderErroComprovante(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func responderErroComprovante(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, aposta.ErrApostaNaoEncontrada):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Aposta não encontrada", "codigo": 404}`))
+	case errors.Is(err, aposta.ErrComprovanteInvalido):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Comprovante não encontrado ou inválido", "codigo": 404}`))
+	case errors.Is(err, aposta.ErrSemComprovante):
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"MSG": "Comprovante disponível só para apostas aceitas", "codigo": 409}`))
+	default:
+		logger.Error("erro ao emitir comprovante da aposta", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao emitir comprovante", "codigo": 500}`))
+	}
+}
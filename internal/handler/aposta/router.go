@@ -0,0 +1,40 @@
+package aposta
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/aposta"
+)
+
+// RegisterApostaAPIHandlers registra as rotas de aposta. O registro exige o
+// token de um cliente, que é quem paga; idempotencia protege o registro
+// contra novas tentativas do mesmo cliente. A aposta e o comprovante são do
+// próprio cliente ou de um admin; só a verificação pelo código é pública.
+func RegisterApostaAPIHandlers(r chi.Router, service aposta.ApostaServiceInterface, conf *config.Config, idempotencia func(http.Handler) http.Handler) {
+	r.Route("/api/v1/aposta", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+
+			r.Group(func(r chi.Router) {
+				r.Use(handler.ExigirCliente())
+
+				r.With(idempotencia).Post("/", createAposta(service))
+				r.With(idempotencia).Post("/surpresinha", createSurpresinha(service))
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(handler.ExigirRole("cliente", "admin"))
+
+				r.Get("/{id}", getByIdAposta(service))
+				r.Get("/{id}/comprovante", getComprovante(service))
+				r.Get("/{id}/comprovante/pdf", getComprovantePDF(service))
+			})
+		})
+
+		r.Get("/verify/{code}", verificarAposta(service))
+	})
+}
@@ -0,0 +1,174 @@
+package bolao
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/aposta"
+	"github.com/katana/fortuna/backend-go/pkg/service/bolao"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/regras"
+)
+
+// createBolao cria o bolão organizado pelo cliente do token, que paga os jogos.
+func createBolao(service bolao.BolaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		blo := &model.Bolao{}
+		if err := json.NewDecoder(r.Body).Decode(blo); err != nil {
+			logger.Error("error decoding request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Dados do bolão inválidos", "codigo": 400}`))
+			return
+		}
+		blo.OrganizadorID, _ = handler.ClienteDoToken(r)
+
+		result, err := service.Create(r.Context(), *blo)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getByIdBolao(service bolao.BolaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.GetByID(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getAllBolao(service bolao.BolaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		filters := model.FilterBolao{
+			SorteioID:     r.URL.Query().Get("sorteio_id"),
+			OrganizadorID: r.URL.Query().Get("organizador_id"),
+			Status:        r.URL.Query().Get("status"),
+		}
+
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+		result, err := service.GetAll(r.Context(), filters, limit, page)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// comprarCotas compra as cotas para o cliente do token.
+func comprarCotas(service bolao.BolaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		cota := &model.CotaBolao{}
+		if err := json.NewDecoder(r.Body).Decode(cota); err != nil {
+			logger.Error("error decoding request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Dados da cota inválidos", "codigo": 400}`))
+			return
+		}
+		cota.ClienteID, _ = handler.ClienteDoToken(r)
+
+		result, err := service.ComprarCotas(r.Context(), chi.URLParam(r, "id"), *cota)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getCotas(service bolao.BolaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.GetCotas(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// liquidarBolao permite repetir a divisão do prêmio caso a etapa automática,
+// executada na liquidação do sorteio, tenha falhado.
+func liquidarBolao(service bolao.BolaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.Liquidar(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	validacao := &regras.ErroValidacao{}
+	switch {
+	case errors.Is(err, bolao.ErrBolaoNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Bolão não encontrado", "codigo": 404}`))
+	case errors.Is(err, sorteio.ErrSorteioNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+	case errors.Is(err, bolao.ErrBolaoEncerrado):
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"MSG": "Bolão não está aberto para venda", "codigo": 409}`))
+	case errors.Is(err, bolao.ErrCotasEsgotadas), errors.Is(err, bolao.ErrSorteioNaoLiquidado):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 409})
+	case errors.Is(err, carteira.ErrSaldoInsuficiente):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"MSG": "Saldo insuficiente na carteira", "codigo": 422}`))
+	case errors.As(err, &validacao):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": "Jogo do bolão não cumpre as regras do jogo", "codigo": 422, "erros": validacao.Erros})
+	case errors.Is(err, bolao.ErrBolaoInvalido), errors.Is(err, aposta.ErrApostaInvalida):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	case errors.Is(err, aposta.ErrFilaIndisponivel):
+		logger.Error("erro ao enfileirar jogo do bolão", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"MSG": "Processamento de apostas indisponível", "codigo": 503}`))
+	default:
+		logger.Error("erro ao acessar a camada de service do bolão", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao processar bolão", "codigo": 500}`))
+	}
+}
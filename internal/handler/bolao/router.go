@@ -0,0 +1,38 @@
+package bolao
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/bolao"
+)
+
+// RegisterBolaoAPIHandlers registra as rotas de bolão. Criar o bolão e
+// comprar cotas exigem o token do cliente que paga; repetir a liquidação
+// exige role admin. idempotencia protege a compra de cotas contra novas
+// tentativas do mesmo cliente.
+func RegisterBolaoAPIHandlers(r chi.Router, service bolao.BolaoServiceInterface, conf *config.Config, idempotencia func(http.Handler) http.Handler) {
+	r.Route("/api/v1/bolao", func(r chi.Router) {
+		r.Get("/getbyid/{id}", getByIdBolao(service))
+		r.Get("/all", getAllBolao(service))
+		r.Get("/cotas/{id}", getCotas(service))
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+			r.Use(handler.ExigirCliente())
+
+			r.Post("/add", createBolao(service))
+			r.With(idempotencia).Post("/cotas/{id}", comprarCotas(service))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+			r.Use(handler.ExigirRole("admin"))
+
+			r.Post("/liquidar/{id}", liquidarBolao(service))
+		})
+	})
+}
@@ -0,0 +1,179 @@
+package carteira
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// contasCasa são as contas da casa listadas no balanço.
+var contasCasa = []string{
+	model.ContaCompensacaoDeposito,
+	model.ContaPremiosAPagar,
+	model.ContaReceita,
+	model.ContaImpostoRetido,
+	model.ContaSaquesPendentes,
+}
+
+// getSaldo devolve o saldo da carteira ao próprio cliente ou a um admin.
+func getSaldo(service carteira.CarteiraServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		clienteID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "cliente"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Cliente inválido", "codigo": 400}`))
+			return
+		}
+		if !handler.PodeAcessarCliente(r, clienteID) {
+			handler.ErroHttpMsgAcessoNegado.Write(w)
+			return
+		}
+
+		result, err := service.Saldo(r.Context(), model.ContaCliente(clienteID))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getExtrato(service carteira.CarteiraServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		clienteID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "cliente"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Cliente inválido", "codigo": 400}`))
+			return
+		}
+		if !handler.PodeAcessarCliente(r, clienteID) {
+			handler.ErroHttpMsgAcessoNegado.Write(w)
+			return
+		}
+
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+		result, err := service.Extrato(r.Context(), model.ContaCliente(clienteID), limit, page)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// depositar lança um depósito manual. Sem referência no corpo vale a
+// Idempotency-Key, para a mesma requisição nunca creditar duas vezes; em
+// ambos os casos com o prefixo model.ReferenciaManual.
+func depositar(service carteira.CarteiraServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		clienteID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "cliente"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Cliente inválido", "codigo": 400}`))
+			return
+		}
+
+		pedido := &model.PedidoDeposito{}
+		if err := json.NewDecoder(r.Body).Decode(pedido); err != nil {
+			logger.Error("error decoding request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Dados do depósito inválidos", "codigo": 400}`))
+			return
+		}
+		if pedido.Referencia == "" {
+			pedido.Referencia = r.Header.Get(handler.HeaderIdempotencia)
+		}
+		if pedido.Referencia == "" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"MSG": "Referência ou Idempotency-Key obrigatória", "codigo": 422}`))
+			return
+		}
+		pedido.Referencia = model.ReferenciaManual + pedido.Referencia
+
+		result, err := service.Depositar(r.Context(), clienteID, *pedido)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getContasCasa(service carteira.CarteiraServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result := make([]*model.SaldoCarteira, 0, len(contasCasa))
+		for _, conta := range contasCasa {
+			saldo, err := service.Saldo(r.Context(), conta)
+			if err != nil {
+				responderErro(w, err)
+				return
+			}
+			result = append(result, saldo)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// recalcularConta refaz o saldo guardado pelos lançamentos; o campo anterior
+// da resposta mostra se havia divergência.
+func recalcularConta(service carteira.CarteiraServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.Recalcular(r.Context(), chi.URLParam(r, "conta"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, carteira.ErrSaldoInsuficiente):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"MSG": "Saldo insuficiente na carteira", "codigo": 422}`))
+	case errors.Is(err, carteira.ErrLancamentoInvalido):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	case errors.Is(err, carteira.ErrLancamentoDivergente):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 409})
+	case errors.Is(err, carteira.ErrLancamentoNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Lançamento não encontrado", "codigo": 404}`))
+	default:
+		logger.Error("erro ao acessar a camada de service da carteira", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao processar carteira", "codigo": 500}`))
+	}
+}
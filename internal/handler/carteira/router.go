@@ -0,0 +1,36 @@
+package carteira
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+)
+
+// RegisterCarteiraAPIHandlers registra as rotas da carteira. Saldo e extrato
+// são do próprio cliente ou de um admin; depósito manual, contas da casa e
+// recálculo exigem role admin. idempotencia protege o depósito contra novas
+// tentativas da mesma requisição.
+func RegisterCarteiraAPIHandlers(r chi.Router, service carteira.CarteiraServiceInterface, conf *config.Config, idempotencia func(http.Handler) http.Handler) {
+	r.Route("/api/v1/carteira", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(conf.TokenAuth))
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.ExigirRole("cliente", "admin"))
+
+			r.Get("/saldo/{cliente}", getSaldo(service))
+			r.Get("/extrato/{cliente}", getExtrato(service))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.ExigirRole("admin"))
+
+			r.With(idempotencia).Post("/deposito/{cliente}", depositar(service))
+			r.Get("/casa", getContasCasa(service))
+			r.Post("/recalcular/{conta}", recalcularConta(service))
+		})
+	})
+}
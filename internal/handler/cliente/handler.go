@@ -7,12 +7,12 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/katana/back-end/orcafacil-go/internal/config/logger"
-	"github.com/katana/back-end/orcafacil-go/pkg/service/cliente"
-	"github.com/katana/back-end/orcafacil-go/pkg/service/validation"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/service/cliente"
+	"github.com/katana/fortuna/backend-go/pkg/service/validation"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
-	"github.com/katana/back-end/orcafacil-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/model"
 )
 
 func createCliente(service cliente.ClienteServiceInterface) http.HandlerFunc {
@@ -4,7 +4,7 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/katana/back-end/orcafacil-go/pkg/service/cliente"
+	"github.com/katana/fortuna/backend-go/pkg/service/cliente"
 )
 
 func RegisterClientePIHandlers(r chi.Router, service cliente.ClienteServiceInterface) {
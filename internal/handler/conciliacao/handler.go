@@ -0,0 +1,117 @@
+package conciliacao
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/conciliacao"
+	"github.com/katana/fortuna/backend-go/pkg/service/conciliacao/extrato"
+)
+
+// tamanhoMaximo limita o extrato enviado.
+const tamanhoMaximo = 10 << 20
+
+// conciliarExtrato recebe o extrato como multipart no campo "arquivo" ou
+// direto no corpo, com ?arquivo= e ?formato= (csv ou ofx). Sem formato, vale
+// a extensão do nome do arquivo.
+func conciliarExtrato(service conciliacao.ConciliacaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		_, claims, _ := jwtauth.FromContext(r.Context())
+		usuario, _ := claims["sub"].(string)
+
+		r.Body = http.MaxBytesReader(w, r.Body, tamanhoMaximo)
+
+		nome := r.URL.Query().Get("arquivo")
+		var corpo io.Reader = r.Body
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			arquivo, cabecalho, err := r.FormFile("arquivo")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"MSG": "Arquivo do extrato não enviado", "codigo": 400}`))
+				return
+			}
+			defer arquivo.Close()
+			corpo = arquivo
+			if nome == "" {
+				nome = cabecalho.Filename
+			}
+		}
+
+		result, err := service.Conciliar(r.Context(), nome, r.URL.Query().Get("formato"), corpo, usuario)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// getAllConciliacao lista as execuções, só com o resumo, filtrando por
+// ?usuario=.
+func getAllConciliacao(service conciliacao.ConciliacaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		filters := model.FilterConciliacao{Usuario: r.URL.Query().Get("usuario")}
+
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+		result, err := service.GetAll(r.Context(), filters, limit, page)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getByIdConciliacao(service conciliacao.ConciliacaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.GetByID(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.Is(err, conciliacao.ErrConciliacaoNaoEncontrada):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Conciliação não encontrada", "codigo": 404}`))
+	case errors.As(err, &maxBytes):
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(`{"MSG": "Extrato maior que o permitido", "codigo": 413}`))
+	case errors.Is(err, extrato.ErrFormatoDesconhecido),
+		errors.Is(err, extrato.ErrExtratoInvalido),
+		errors.Is(err, conciliacao.ErrExtratoVazio):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	default:
+		logger.Error("erro ao acessar a camada de service da conciliacao", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao processar conciliação", "codigo": 500}`))
+	}
+}
@@ -0,0 +1,22 @@
+package conciliacao
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/conciliacao"
+)
+
+// RegisterConciliacaoAPIHandlers registra a conciliação dos extratos do PSP,
+// restrita a tokens com role admin.
+func RegisterConciliacaoAPIHandlers(r chi.Router, service conciliacao.ConciliacaoServiceInterface, conf *config.Config) {
+	r.Route("/api/v1/conciliacao", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(conf.TokenAuth))
+		r.Use(handler.ExigirRole("admin"))
+
+		r.Post("/", conciliarExtrato(service))
+		r.Get("/all", getAllConciliacao(service))
+		r.Get("/getbyid/{id}", getByIdConciliacao(service))
+	})
+}
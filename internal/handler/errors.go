@@ -35,3 +35,13 @@ var ErroHttpMsgMethodNotAllowed HttpMsg = HttpMsg{
 	Msg:  "Erro Method Not Allowed",
 	Code: http.StatusMethodNotAllowed,
 }
+
+var ErroHttpMsgNaoAutenticado HttpMsg = HttpMsg{
+	Msg:  "Token de acesso ausente ou inválido",
+	Code: http.StatusUnauthorized,
+}
+
+var ErroHttpMsgAcessoNegado HttpMsg = HttpMsg{
+	Msg:  "Acesso restrito ao perfil informado",
+	Code: http.StatusForbidden,
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/redisdb"
+)
+
+const (
+	HeaderIdempotencia = "Idempotency-Key"
+
+	// prazoProcessando limita quanto tempo uma chave fica presa se o processo
+	// cair no meio da requisição.
+	prazoProcessando   = time.Minute
+	tamanhoMaximoCorpo = 1 << 20
+
+	idemProcessando = "processando"
+	idemConcluido   = "concluido"
+)
+
+// scriptReservarChave grava a chave como em processamento se ela ainda não
+// existe; caso exista, devolve o que está guardado.
+const scriptReservarChave = `
+local atual = redis.call('GET', KEYS[1])
+if atual then return atual end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return ''
+`
+
+var ErroHttpMsgIdempotenciaDivergente HttpMsg = HttpMsg{
+	Msg:  "Idempotency-Key já usada com outra requisição",
+	Code: http.StatusUnprocessableEntity,
+}
+
+var ErroHttpMsgIdempotenciaEmAndamento HttpMsg = HttpMsg{
+	Msg:  "Requisição com esta Idempotency-Key ainda em processamento",
+	Code: http.StatusConflict,
+}
+
+type respostaIdempotente struct {
+	Estado      string            `json:"estado"`
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Corpo       []byte            `json:"corpo,omitempty"`
+}
+
+// gravadorResposta repassa a resposta ao cliente e guarda uma cópia.
+type gravadorResposta struct {
+	http.ResponseWriter
+	status int
+	corpo  bytes.Buffer
+}
+
+func (g *gravadorResposta) WriteHeader(status int) {
+	if g.status == 0 {
+		g.status = status
+	}
+	g.ResponseWriter.WriteHeader(status)
+}
+
+func (g *gravadorResposta) Write(b []byte) (int, error) {
+	if g.status == 0 {
+		g.status = http.StatusOK
+	}
+	g.corpo.Write(b)
+	return g.ResponseWriter.Write(b)
+}
+
+// Idempotencia torna seguras as novas tentativas de requisições com o header
+// Idempotency-Key. A primeira requisição com a chave é processada e a resposta
+// fica guardada no Redis por ttl; repetições com o mesmo corpo recebem a
+// resposta original, com outro corpo recebem 422 e, enquanto a primeira não
+// termina, 409. Respostas 5xx não são guardadas, para permitir nova tentativa.
+// A chave vale só para o usuário do token, por isso o middleware vem depois de
+// jwtauth.Verifier. Sem o header a requisição segue normalmente.
+func Idempotencia(rdb redisdb.RedisClientInterface, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			chave := r.Header.Get(HeaderIdempotencia)
+			if chave == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			corpo, err := io.ReadAll(http.MaxBytesReader(w, r.Body, tamanhoMaximoCorpo))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"MSG": "Corpo da requisição inválido", "codigo": 400}`))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(corpo))
+
+			dono := chamador(r)
+			soma := sha256.Sum256(append([]byte(dono+" "+r.Method+" "+r.URL.Path+"\n"), corpo...))
+			fingerprint := hex.EncodeToString(soma[:])
+			chaveRedis := "idem:" + dono + ":" + r.URL.Path + ":" + chave
+
+			reserva, _ := json.Marshal(respostaIdempotente{Estado: idemProcessando, Fingerprint: fingerprint})
+			atual, err := rdb.Eval(r.Context(), scriptReservarChave, []string{chaveRedis}, reserva, prazoProcessando.Milliseconds())
+			if err != nil {
+				// Sem Redis a requisição segue sem a proteção, como se não tivesse a chave.
+				logger.Error("erro ao reservar Idempotency-Key", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if guardado, _ := atual.(string); guardado != "" {
+				responderGuardado(w, guardado, fingerprint)
+				return
+			}
+
+			gravador := &gravadorResposta{ResponseWriter: w}
+			next.ServeHTTP(gravador, r)
+
+			if gravador.status >= http.StatusInternalServerError {
+				if err := rdb.DeleteData(r.Context(), chaveRedis); err != nil {
+					logger.Error("erro ao liberar Idempotency-Key", err)
+				}
+				return
+			}
+
+			resposta := respostaIdempotente{
+				Estado:      idemConcluido,
+				Fingerprint: fingerprint,
+				Status:      gravador.status,
+				Headers:     make(map[string]string),
+				Corpo:       gravador.corpo.Bytes(),
+			}
+			for _, h := range []string{"Content-Type", "Location"} {
+				if v := w.Header().Get(h); v != "" {
+					resposta.Headers[h] = v
+				}
+			}
+
+			data, _ := json.Marshal(resposta)
+			rdb.SaveData(r.Context(), chaveRedis, data, ttl)
+		})
+	}
+}
+
+// chamador identifica o dono da Idempotency-Key pelo token verificado, para
+// que a mesma chave de dois usuários não devolva a resposta de um ao outro.
+func chamador(r *http.Request) string {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return ""
+	}
+	if sub, _ := claims["sub"].(string); sub != "" {
+		return sub
+	}
+	cliente, _ := claims[ClaimCliente].(string)
+	return cliente
+}
+
+func responderGuardado(w http.ResponseWriter, guardado, fingerprint string) {
+	resposta := respostaIdempotente{}
+	if err := json.Unmarshal([]byte(guardado), &resposta); err != nil {
+		logger.Error("erro ao ler resposta guardada da Idempotency-Key", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao ler requisição anterior", "codigo": 500}`))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	switch {
+	case resposta.Fingerprint != fingerprint:
+		ErroHttpMsgIdempotenciaDivergente.Write(w)
+	case resposta.Estado != idemConcluido:
+		ErroHttpMsgIdempotenciaEmAndamento.Write(w)
+	default:
+		for h, v := range resposta.Headers {
+			w.Header().Set(h, v)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(resposta.Status)
+		w.Write(resposta.Corpo)
+	}
+}
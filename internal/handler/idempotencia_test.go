@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/jwtauth"
+)
+
+// redisTeste guarda as chaves num mapa e só entende o scriptReservarChave.
+type redisTeste struct {
+	dados map[string][]byte
+	falha bool
+}
+
+func (rt *redisTeste) ReadData(ctx context.Context, key string) ([]byte, error) {
+	return rt.dados[key], nil
+}
+
+func (rt *redisTeste) SaveData(ctx context.Context, key string, data []byte, timer time.Duration) bool {
+	rt.dados[key] = data
+	return true
+}
+
+func (rt *redisTeste) DeleteData(ctx context.Context, key string) error {
+	delete(rt.dados, key)
+	return nil
+}
+
+func (rt *redisTeste) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	if rt.falha {
+		return nil, errors.New("redis fora do ar")
+	}
+	if atual, ok := rt.dados[keys[0]]; ok {
+		return string(atual), nil
+	}
+	rt.dados[keys[0]] = args[0].([]byte)
+	return "", nil
+}
+
+func (rt *redisTeste) Publish(ctx context.Context, channel string, data []byte) error {
+	return nil
+}
+
+func (rt *redisTeste) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	return nil, nil
+}
+
+type requisicaoTeste struct {
+	usuario string
+	chave   string
+	corpo   string
+}
+
+func TestIdempotencia(t *testing.T) {
+	auth := jwtauth.New("HS256", []byte("segredo-de-teste"), nil)
+	token := func(usuario string) string {
+		_, tokenString, _ := auth.Encode(map[string]interface{}{"sub": usuario, ClaimCliente: usuario})
+		return tokenString
+	}
+
+	tests := []struct {
+		name         string
+		anterior     *requisicaoTeste
+		statusAntes  int
+		atual        requisicaoTeste
+		redisFalha   bool
+		wantStatus   int
+		wantChamadas int
+		wantReplay   bool
+	}{
+		{
+			name:         "sem chave segue normalmente",
+			atual:        requisicaoTeste{usuario: "ana", corpo: `{"valor":100}`},
+			wantStatus:   http.StatusCreated,
+			wantChamadas: 1,
+		},
+		{
+			name:         "primeira requisição com a chave",
+			atual:        requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":100}`},
+			wantStatus:   http.StatusCreated,
+			wantChamadas: 1,
+		},
+		{
+			name:         "repetição devolve a resposta guardada",
+			anterior:     &requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":100}`},
+			atual:        requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":100}`},
+			wantStatus:   http.StatusCreated,
+			wantChamadas: 1,
+			wantReplay:   true,
+		},
+		{
+			name:         "mesma chave com outro corpo",
+			anterior:     &requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":100}`},
+			atual:        requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":999}`},
+			wantStatus:   http.StatusUnprocessableEntity,
+			wantChamadas: 1,
+		},
+		{
+			name:         "mesma chave de outro usuário é outra requisição",
+			anterior:     &requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":100}`},
+			atual:        requisicaoTeste{usuario: "bia", chave: "k1", corpo: `{"valor":100}`},
+			wantStatus:   http.StatusCreated,
+			wantChamadas: 2,
+		},
+		{
+			name:         "resposta 5xx libera a chave",
+			anterior:     &requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":100}`},
+			statusAntes:  http.StatusInternalServerError,
+			atual:        requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":100}`},
+			wantStatus:   http.StatusCreated,
+			wantChamadas: 2,
+		},
+		{
+			name:         "sem Redis segue sem proteção",
+			atual:        requisicaoTeste{usuario: "ana", chave: "k1", corpo: `{"valor":100}`},
+			redisFalha:   true,
+			wantStatus:   http.StatusCreated,
+			wantChamadas: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb := &redisTeste{dados: make(map[string][]byte)}
+			chamadas := 0
+			status := http.StatusCreated
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				chamadas++
+				corpo, _ := io.ReadAll(r.Body)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				w.Write(corpo)
+			})
+			h := jwtauth.Verifier(auth)(Idempotencia(rdb, time.Hour)(next))
+
+			enviar := func(req requisicaoTeste) *httptest.ResponseRecorder {
+				r := httptest.NewRequest(http.MethodPost, "/api/v1/saque/", strings.NewReader(req.corpo))
+				r.Header.Set("Authorization", "BEARER "+token(req.usuario))
+				if req.chave != "" {
+					r.Header.Set(HeaderIdempotencia, req.chave)
+				}
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				return w
+			}
+
+			if tt.anterior != nil {
+				if tt.statusAntes != 0 {
+					status = tt.statusAntes
+				}
+				enviar(*tt.anterior)
+				status = http.StatusCreated
+			}
+			rdb.falha = tt.redisFalha
+
+			w := enviar(tt.atual)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if chamadas != tt.wantChamadas {
+				t.Errorf("handler chamado %d vezes, want %d", chamadas, tt.wantChamadas)
+			}
+			if replay := w.Header().Get("Idempotent-Replayed") == "true"; replay != tt.wantReplay {
+				t.Errorf("Idempotent-Replayed = %v, want %v", replay, tt.wantReplay)
+			}
+			if tt.wantStatus == http.StatusCreated && w.Body.String() != tt.atual.corpo {
+				t.Errorf("corpo = %q, want %q", w.Body.String(), tt.atual.corpo)
+			}
+		})
+	}
+}
+
+func TestIdempotenciaEmAndamento(t *testing.T) {
+	rdb := &redisTeste{dados: make(map[string][]byte)}
+	var segunda *httptest.ResponseRecorder
+
+	var h http.Handler
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if segunda == nil {
+			segunda = httptest.NewRecorder()
+			repetida := httptest.NewRequest(http.MethodPost, "/api/v1/saque/", strings.NewReader(`{}`))
+			repetida.Header.Set(HeaderIdempotencia, "k1")
+			h.ServeHTTP(segunda, repetida)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	h = Idempotencia(rdb, time.Hour)(next)
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/saque/", strings.NewReader(`{}`))
+	r.Header.Set(HeaderIdempotencia, "k1")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if segunda.Code != http.StatusConflict {
+		t.Errorf("status da repetição em andamento = %d, want %d", segunda.Code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,127 @@
+package meiopag
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/meiopag"
+)
+
+// createMeioPag cadastra o meio com o nome da query (?nome=Pix). As regras
+// vêm no corpo, que é opcional.
+func createMeioPag(service meiopag.MeioPagServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		regras, ok := lerRegras(w, r)
+		if !ok {
+			return
+		}
+
+		result, err := service.Create(r.Context(), r.URL.Query().Get("nome"), regras)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// updateMeioPag renomeia o meio quando o nome vem na rota e altera só as
+// regras presentes no corpo.
+func updateMeioPag(service meiopag.MeioPagServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		regras, ok := lerRegras(w, r)
+		if !ok {
+			return
+		}
+
+		result, err := service.Update(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "nome"), regras)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getByIdMeioPag(service meiopag.MeioPagServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.GetByID(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getAllMeioPag(service meiopag.MeioPagServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		filters := model.FilterMeioPagamento{
+			MeioPg:   r.URL.Query().Get("nome"),
+			Operacao: r.URL.Query().Get("operacao"),
+			Enabled:  r.URL.Query().Get("enabled"),
+		}
+
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+		result, err := service.GetAll(r.Context(), filters, limit, page)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// lerRegras decodifica o corpo opcional; corpo vazio não altera nenhuma regra.
+func lerRegras(w http.ResponseWriter, r *http.Request) (model.RegrasMeioPagamento, bool) {
+	regras := model.RegrasMeioPagamento{}
+	if err := json.NewDecoder(r.Body).Decode(&regras); err != nil && err != io.EOF {
+		logger.Error("error decoding request body", err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"MSG": "Regras do meio de pagamento inválidas", "codigo": 400}`))
+		return regras, false
+	}
+	return regras, true
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, meiopag.ErrMeioPagamentoNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Meio de pagamento não encontrado", "codigo": 404}`))
+	case errors.Is(err, meiopag.ErrMeioPagamentoDuplicado):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 409})
+	case errors.Is(err, meiopag.ErrMeioPagamentoInvalido):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	default:
+		logger.Error("erro ao acessar a camada de service do meio de pagamento", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao processar meio de pagamento", "codigo": 500}`))
+	}
+}
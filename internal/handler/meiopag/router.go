@@ -0,0 +1,27 @@
+package meiopag
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/meiopag"
+)
+
+// RegisterMeioPagAPIHandlers registra as rotas dos meios de pagamento. A
+// consulta é pública; cadastro e alteração exigem token com role admin.
+func RegisterMeioPagAPIHandlers(r chi.Router, service meiopag.MeioPagServiceInterface, conf *config.Config) {
+	r.Route("/api/v1/meiopag", func(r chi.Router) {
+		r.Get("/getbyid/{id}", getByIdMeioPag(service))
+		r.Get("/all", getAllMeioPag(service))
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+			r.Use(handler.ExigirRole("admin"))
+
+			r.Post("/add", createMeioPag(service))
+			r.Put("/update/{id}/{nome}", updateMeioPag(service))
+			r.Put("/update/{id}", updateMeioPag(service))
+		})
+	})
+}
@@ -2,8 +2,15 @@ package handler
 
 import (
 	"net/http"
+	"slices"
+
+	"github.com/go-chi/jwtauth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ClaimCliente é a claim do token com o id do cliente ligado ao usuário.
+const ClaimCliente = "cliente"
+
 func ResponseApplicationJSON() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -12,3 +19,86 @@ func ResponseApplicationJSON() func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// ExigirRole só deixa passar requisições com token válido cuja claim "role"
+// esteja entre as informadas. Deve ser usado depois de jwtauth.Verifier.
+func ExigirRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+			token, claims, err := jwtauth.FromContext(r.Context())
+			if err != nil || token == nil {
+				ErroHttpMsgNaoAutenticado.Write(w)
+				return
+			}
+
+			role, _ := claims["role"].(string)
+			if !slices.Contains(roles, role) {
+				ErroHttpMsgAcessoNegado.Write(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ExigirCliente só deixa passar tokens válidos de um usuário ligado a um
+// cliente, que as rotas usam como dono da operação. Deve ser usado depois de
+// jwtauth.Verifier.
+func ExigirCliente() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+			token, _, err := jwtauth.FromContext(r.Context())
+			if err != nil || token == nil {
+				ErroHttpMsgNaoAutenticado.Write(w)
+				return
+			}
+
+			if _, ok := ClienteDoToken(r); !ok {
+				ErroHttpMsgAcessoNegado.Write(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClienteDoToken devolve o cliente da claim ClaimCliente do token verificado.
+func ClienteDoToken(r *http.Request) (primitive.ObjectID, bool) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+
+	hex, _ := claims[ClaimCliente].(string)
+	clienteID, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return clienteID, true
+}
+
+// EhAdmin informa se o token verificado tem role admin.
+func EhAdmin(r *http.Request) bool {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return false
+	}
+	role, _ := claims["role"].(string)
+	return role == "admin"
+}
+
+// PodeAcessarCliente informa se o token é do próprio cliente ou de um admin.
+func PodeAcessarCliente(r *http.Request, clienteID primitive.ObjectID) bool {
+	if EhAdmin(r) {
+		return true
+	}
+
+	dono, ok := ClienteDoToken(r)
+	return ok && dono == clienteID
+}
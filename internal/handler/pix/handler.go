@@ -0,0 +1,164 @@
+package pix
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/psp"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/meiopag"
+	"github.com/katana/fortuna/backend-go/pkg/service/pix"
+	"github.com/skip2/go-qrcode"
+)
+
+// tamanhoWebhook limita o corpo aceito do PSP.
+const tamanhoWebhook = 1 << 20
+
+func criarCobranca(service pix.PixServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		pedido := &model.PedidoCobrancaPix{}
+		if err := json.NewDecoder(r.Body).Decode(pedido); err != nil {
+			logger.Error("error decoding request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Dados da cobrança inválidos", "codigo": 400}`))
+			return
+		}
+		pedido.ClienteID, _ = handler.ClienteDoToken(r)
+
+		result, err := service.CriarCobranca(r.Context(), *pedido)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getCobranca(service pix.PixServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.GetByTxID(r.Context(), chi.URLParam(r, "txid"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+		if !handler.PodeAcessarCliente(r, result.ClienteID) {
+			handler.ErroHttpMsgAcessoNegado.Write(w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// getQRCode devolve o BR Code da cobrança como imagem PNG.
+func getQRCode(service pix.PixServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		txid := chi.URLParam(r, "txid")
+
+		result, err := service.GetByTxID(r.Context(), txid)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			responderErro(w, err)
+			return
+		}
+		if !handler.PodeAcessarCliente(r, result.ClienteID) {
+			handler.ErroHttpMsgAcessoNegado.Write(w)
+			return
+		}
+
+		png, err := qrcode.Encode(result.Payload, qrcode.Medium, 256)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			responderErro(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Disposition", `inline; filename="pix-`+txid+`.png"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(png)
+	}
+}
+
+// receberWebhook lê o corpo cru, que é o que a assinatura cobre. Qualquer
+// resposta fora de 2xx faz o PSP reenviar a notificação.
+func receberWebhook(service pix.PixServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		corpo, err := io.ReadAll(io.LimitReader(r.Body, tamanhoWebhook))
+		if err != nil {
+			logger.Error("error reading webhook body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Notificação inválida", "codigo": 400}`))
+			return
+		}
+
+		result, err := service.ReceberWebhook(r.Context(), corpo, r.Header.Get(psp.HeaderAssinatura))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// simularPagamento paga a cobrança pelo PSP de teste; ?valor= em centavos
+// permite simular pagamento do QR estático sem valor ou com valor diferente.
+func simularPagamento(service pix.PixServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		valor, _ := strconv.ParseInt(r.URL.Query().Get("valor"), 10, 64)
+
+		result, err := service.SimularPagamento(r.Context(), chi.URLParam(r, "txid"), valor)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, psp.ErrAssinaturaInvalida):
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"MSG": "Assinatura do webhook inválida", "codigo": 401}`))
+	case errors.Is(err, pix.ErrCobrancaNaoEncontrada):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Cobrança Pix não encontrada", "codigo": 404}`))
+	case errors.Is(err, pix.ErrCobrancaInvalida), errors.Is(err, carteira.ErrLancamentoInvalido),
+		errors.Is(err, meiopag.ErrOperacaoNaoPermitida), errors.Is(err, meiopag.ErrValorForaDoLimite):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	case errors.Is(err, meiopag.ErrMeioPagamentoNaoEncontrado):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"MSG": "Meio de pagamento Pix não cadastrado", "codigo": 422}`))
+	case errors.Is(err, pix.ErrSimulacaoIndisponivel):
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte(`{"MSG": "PSP configurado não simula pagamentos", "codigo": 501}`))
+	default:
+		logger.Error("erro ao acessar a camada de service do Pix", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao processar Pix", "codigo": 500}`))
+	}
+}
@@ -0,0 +1,37 @@
+package pix
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/pix"
+)
+
+// RegisterPixAPIHandlers registra as rotas de depósito por Pix. A cobrança é
+// criada para o cliente do token e consultada por ele ou por um admin. O
+// webhook é chamado pelo PSP e autenticado pela assinatura do corpo; simular
+// só existe fora de produção, para pagar cobranças sem um PSP de verdade.
+func RegisterPixAPIHandlers(r chi.Router, service pix.PixServiceInterface, conf *config.Config, idempotencia func(http.Handler) http.Handler, simular bool) {
+	r.Route("/api/v1/pix", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+
+			r.With(handler.ExigirCliente(), idempotencia).Post("/cobranca", criarCobranca(service))
+
+			r.Group(func(r chi.Router) {
+				r.Use(handler.ExigirRole("cliente", "admin"))
+
+				r.Get("/cobranca/{txid}", getCobranca(service))
+				r.Get("/cobranca/{txid}/qrcode", getQRCode(service))
+			})
+		})
+
+		r.Post("/webhook", receberWebhook(service))
+		if simular {
+			r.Post("/simular/{txid}", simularPagamento(service))
+		}
+	})
+}
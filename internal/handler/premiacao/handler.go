@@ -0,0 +1,141 @@
+package premiacao
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/premiacao"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// getAllPremio lista os prêmios do maior para o menor, filtrando por
+// ?sorteio=, ?cliente= e ?status=.
+func getAllPremio(service premiacao.PremiacaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		filters := model.FilterPagamentoPremio{Status: r.URL.Query().Get("status")}
+		for param, destino := range map[string]*primitive.ObjectID{
+			"sorteio": &filters.SorteioID,
+			"cliente": &filters.ClienteID,
+		} {
+			valor := r.URL.Query().Get(param)
+			if valor == "" {
+				continue
+			}
+			id, err := primitive.ObjectIDFromHex(valor)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]interface{}{"MSG": "Filtro " + param + " inválido", "codigo": 400})
+				return
+			}
+			*destino = id
+		}
+
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+		result, err := service.GetAll(r.Context(), filters, limit, page)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getByIdPremio(service premiacao.PremiacaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.GetByID(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+		if !handler.PodeAcessarCliente(r, result.ClienteID) {
+			handler.ErroHttpMsgAcessoNegado.Write(w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// resgatarPremio credita um prêmio grande depois da conferência do
+// administrador, registrado como quem fez o resgate.
+func resgatarPremio(service premiacao.PremiacaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		_, claims, _ := jwtauth.FromContext(r.Context())
+		usuario, _ := claims["sub"].(string)
+
+		result, err := service.Resgatar(r.Context(), chi.URLParam(r, "id"), usuario)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// pagarSorteio repete o pagamento dos prêmios de um sorteio liquidado.
+func pagarSorteio(service premiacao.PremiacaoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id := chi.URLParam(r, "id")
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Sorteio inválido", "codigo": 400}`))
+			return
+		}
+
+		if err := service.Reprocessar(r.Context(), id); err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": "Success", "codigo": 1})
+	}
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, premiacao.ErrPremioNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Prêmio não encontrado", "codigo": 404}`))
+	case errors.Is(err, sorteio.ErrSorteioNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+	case errors.Is(err, premiacao.ErrPremioJaPago):
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"MSG": "Prêmio já creditado", "codigo": 409}`))
+	case errors.Is(err, premiacao.ErrSorteioNaoLiquidado):
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"MSG": "Sorteio ainda não liquidado", "codigo": 409}`))
+	case errors.Is(err, carteira.ErrLancamentoInvalido):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	default:
+		logger.Error("erro ao acessar a camada de service da premiacao", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao processar prêmios", "codigo": 500}`))
+	}
+}
@@ -0,0 +1,32 @@
+package premiacao
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/premiacao"
+)
+
+// RegisterPremiacaoAPIHandlers registra a consulta dos prêmios pagos. Cada
+// prêmio é visto pelo próprio ganhador ou por um admin; a listagem, o resgate
+// manual e o reprocessamento exigem token com role admin.
+func RegisterPremiacaoAPIHandlers(r chi.Router, service premiacao.PremiacaoServiceInterface, conf *config.Config) {
+	r.Route("/api/v1/premio", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(conf.TokenAuth))
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.ExigirRole("cliente", "admin"))
+
+			r.Get("/getbyid/{id}", getByIdPremio(service))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.ExigirRole("admin"))
+
+			r.Get("/all", getAllPremio(service))
+			r.Post("/{id}/resgatar", resgatarPremio(service))
+			r.Post("/sorteio/{id}/pagar", pagarSorteio(service))
+		})
+	})
+}
@@ -0,0 +1,130 @@
+package regra
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/regra"
+)
+
+func createRegra(service regra.RegraServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rgr := &model.RegraJogo{}
+
+		err := json.NewDecoder(r.Body).Decode(rgr)
+		if err != nil {
+			logger.Error("error decoding request body", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		result, err := service.Create(r.Context(), *rgr)
+		if err != nil {
+			switch {
+			case errors.Is(err, regra.ErrRegraInvalida):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, regra.ErrRegraDuplicada):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				logger.Error("erro ao acessar a camada de service da regra", err)
+				http.Error(w, "Error ou salvar Regra", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func updateRegra(service regra.RegraServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idp := chi.URLParam(r, "id")
+
+		rgr := &model.RegraJogo{}
+		err := json.NewDecoder(r.Body).Decode(rgr)
+		if err != nil {
+			logger.Error("error decoding request body", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		_, err = service.Update(r.Context(), idp, rgr)
+		if err != nil {
+			switch {
+			case errors.Is(err, regra.ErrRegraNaoEncontrada):
+				http.Error(w, "Regra nao encontrada", http.StatusNotFound)
+			case errors.Is(err, regra.ErrRegraInvalida):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				logger.Error("erro ao acessar a camada de service da regra no upd", err)
+				http.Error(w, "Error ao atualizar regra", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": "Success", "codigo": 1})
+	}
+}
+
+func getByIdRegra(service regra.RegraServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		idp := chi.URLParam(r, "id")
+		result, err := service.GetByID(r.Context(), idp)
+		if err != nil {
+			logger.Error("erro ao acessar a camada de service da regra no por id", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Regra não encontrada", "codigo": 404}`))
+			return
+		}
+
+		err = json.NewEncoder(w).Encode(result)
+		if err != nil {
+			logger.Error("erro ao converter em json", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error to parse Regra to JSON", "codigo": 500}`))
+			return
+		}
+	}
+}
+
+func getAllRegra(service regra.RegraServiceInterface) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		filters := model.FilterRegraJogo{
+			Jogo:    r.URL.Query().Get("jogo"),
+			Enabled: r.URL.Query().Get("enabled"),
+		}
+
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+		result, err := service.GetAll(r.Context(), filters, limit, page)
+		if err != nil {
+			logger.Error("erro ao acessar a camada de service da regra no all", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Regra not found", "codigo": 404}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		err = json.NewEncoder(w).Encode(result)
+		if err != nil {
+			logger.Error("erro ao converter para json", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error to parse Regra to JSON", "codigo": 500}`))
+			return
+		}
+	})
+}
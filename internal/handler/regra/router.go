@@ -0,0 +1,31 @@
+package regra
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/regra"
+)
+
+// RegisterRegraAPIHandlers registra as rotas das regras de jogo. A consulta é
+// pública; cadastro e alteração exigem token com role admin.
+func RegisterRegraAPIHandlers(r chi.Router, service regra.RegraServiceInterface, conf *config.Config) {
+	r.Route("/api/v1/regra", func(r chi.Router) {
+		r.Get("/getbyid/{id}", getByIdRegra(service))
+		r.Get("/all", func(w http.ResponseWriter, r *http.Request) {
+			handler := getAllRegra(service)
+			handler.ServeHTTP(w, r)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+			r.Use(handler.ExigirRole("admin"))
+
+			r.Post("/add", createRegra(service))
+			r.Put("/update/{id}", updateRegra(service))
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package rifa
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/rifa"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/regras"
+)
+
+func reservarNumeros(service rifa.RifaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		reserva := model.ReservaRifa{}
+		if err := json.NewDecoder(r.Body).Decode(&reserva); err != nil {
+			logger.Error("error decoding request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Dados da reserva inválidos", "codigo": 400}`))
+			return
+		}
+		reserva.ClienteID, _ = handler.ClienteDoToken(r)
+
+		result, err := service.Reservar(r.Context(), chi.URLParam(r, "id"), reserva)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func confirmarReserva(service rifa.RifaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		clienteID, _ := handler.ClienteDoToken(r)
+		result, err := service.Confirmar(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "reserva"), clienteID)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func liberarReserva(service rifa.RifaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		clienteID, _ := handler.ClienteDoToken(r)
+		if err := service.Liberar(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "reserva"), clienteID); err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"MSG": "Reserva liberada", "codigo": 200}`))
+	}
+}
+
+func getDisponiveis(service rifa.RifaServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.Disponiveis(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	validacao := &regras.ErroValidacao{}
+	switch {
+	case errors.Is(err, sorteio.ErrSorteioNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+	case errors.Is(err, rifa.ErrReservaNaoEncontrada):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Reserva não encontrada ou expirada", "codigo": 404}`))
+	case errors.Is(err, rifa.ErrNumeroIndisponivel):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 409})
+	case errors.Is(err, rifa.ErrVendasEncerradas):
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"MSG": "Vendas do sorteio não estão abertas", "codigo": 409}`))
+	case errors.Is(err, carteira.ErrSaldoInsuficiente):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"MSG": "Saldo insuficiente na carteira", "codigo": 422}`))
+	case errors.As(err, &validacao):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": "Reserva não cumpre as regras do jogo", "codigo": 422, "erros": validacao.Erros})
+	case errors.Is(err, rifa.ErrNaoERifa), errors.Is(err, rifa.ErrReservaInvalida):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	default:
+		logger.Error("erro ao acessar a camada de service da rifa", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao processar rifa", "codigo": 500}`))
+	}
+}
@@ -0,0 +1,28 @@
+package rifa
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/rifa"
+)
+
+// RegisterRifaAPIHandlers registra as rotas da rifa. Reservar, confirmar e
+// liberar exigem o token do cliente dono da reserva, que paga na confirmação.
+func RegisterRifaAPIHandlers(r chi.Router, service rifa.RifaServiceInterface, conf *config.Config, idempotencia func(http.Handler) http.Handler) {
+	r.Route("/api/v1/rifa", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+			r.Use(handler.ExigirCliente())
+
+			r.With(idempotencia).Post("/reservar/{id}", reservarNumeros(service))
+			r.With(idempotencia).Post("/confirmar/{id}/{reserva}", confirmarReserva(service))
+			r.Post("/liberar/{id}/{reserva}", liberarReserva(service))
+		})
+
+		r.Get("/disponiveis/{id}", getDisponiveis(service))
+	})
+}
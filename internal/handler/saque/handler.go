@@ -0,0 +1,220 @@
+package saque
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/psp"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/meiopag"
+	"github.com/katana/fortuna/backend-go/pkg/service/saque"
+	"github.com/katana/fortuna/backend-go/pkg/service/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func solicitarSaque(service saque.SaqueServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		pedido := &model.PedidoSaque{}
+		if err := json.NewDecoder(r.Body).Decode(pedido); err != nil {
+			logger.Error("error decoding request body", err)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Dados do saque inválidos", "codigo": 400}`))
+			return
+		}
+		pedido.ClienteID, _ = handler.ClienteDoToken(r)
+
+		result, err := service.Solicitar(r.Context(), *pedido)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getByIdSaque(service saque.SaqueServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.GetByID(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+		if !handler.PodeAcessarCliente(r, result.ClienteID) {
+			handler.ErroHttpMsgAcessoNegado.Write(w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// getAllSaque lista os saques, filtrando por ?cliente= e ?status=. Sem
+// ?cliente=, o cliente do token só vê os próprios saques.
+func getAllSaque(service saque.SaqueServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		filters := model.FilterSaque{Status: r.URL.Query().Get("status")}
+		if cliente := r.URL.Query().Get("cliente"); cliente != "" {
+			clienteID, err := primitive.ObjectIDFromHex(cliente)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"MSG": "Cliente inválido", "codigo": 400}`))
+				return
+			}
+			filters.ClienteID = clienteID
+		} else if !handler.EhAdmin(r) {
+			filters.ClienteID, _ = handler.ClienteDoToken(r)
+		}
+		if !handler.PodeAcessarCliente(r, filters.ClienteID) {
+			handler.ErroHttpMsgAcessoNegado.Write(w)
+			return
+		}
+
+		listarSaques(w, r, service, filters)
+	}
+}
+
+// getFilaAprovacao lista os saques acima do limite esperando um administrador.
+func getFilaAprovacao(service saque.SaqueServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		listarSaques(w, r, service, model.FilterSaque{Status: model.SaqueAguardandoAprovacao})
+	}
+}
+
+func aprovarSaque(service saque.SaqueServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		decisao, ok := lerDecisao(w, r)
+		if !ok {
+			return
+		}
+
+		result, err := service.Aprovar(r.Context(), chi.URLParam(r, "id"), decisao)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func rejeitarSaque(service saque.SaqueServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		decisao, ok := lerDecisao(w, r)
+		if !ok {
+			return
+		}
+
+		result, err := service.Rejeitar(r.Context(), chi.URLParam(r, "id"), decisao)
+		if err != nil {
+			responderErro(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// registrarChave cadastra no DICT simulado a chave de e-mail, telefone ou
+// aleatória de um titular, para testar saques com esses tipos de chave.
+func registrarChave(simulador psp.Simulador) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		dono := psp.DonoChave{}
+		if err := json.NewDecoder(r.Body).Decode(&dono); err != nil || dono.Chave == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Informe chave, documento e nome", "codigo": 400}`))
+			return
+		}
+		dono.Documento = validation.ExtractNumbers(dono.Documento)
+
+		simulador.RegistrarChave(dono)
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(dono)
+	}
+}
+
+func listarSaques(w http.ResponseWriter, r *http.Request, service saque.SaqueServiceInterface, filters model.FilterSaque) {
+	limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+	page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+	result, err := service.GetAll(r.Context(), filters, limit, page)
+	if err != nil {
+		responderErro(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// lerDecisao lê o motivo opcional do corpo e identifica o administrador pelo token.
+func lerDecisao(w http.ResponseWriter, r *http.Request) (model.DecisaoSaque, bool) {
+	decisao := model.DecisaoSaque{}
+	if err := json.NewDecoder(r.Body).Decode(&decisao); err != nil && err != io.EOF {
+		logger.Error("error decoding request body", err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"MSG": "Dados da decisão inválidos", "codigo": 400}`))
+		return decisao, false
+	}
+
+	_, claims, _ := jwtauth.FromContext(r.Context())
+	decisao.Usuario, _ = claims["sub"].(string)
+
+	return decisao, true
+}
+
+func responderErro(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, saque.ErrSaqueNaoEncontrado):
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"MSG": "Saque não encontrado", "codigo": 404}`))
+	case errors.Is(err, saque.ErrSaqueJaAvaliado):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 409})
+	case errors.Is(err, carteira.ErrSaldoInsuficiente):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"MSG": "Saldo insuficiente na carteira", "codigo": 422}`))
+	case errors.Is(err, saque.ErrChaveDeOutroTitular):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"MSG": "A chave Pix precisa ser do CPF/CNPJ do cliente", "codigo": 422}`))
+	case errors.Is(err, meiopag.ErrMeioPagamentoNaoEncontrado):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"MSG": "Meio de pagamento Pix não cadastrado", "codigo": 422}`))
+	case errors.Is(err, saque.ErrSaqueInvalido), errors.Is(err, saque.ErrChaveInvalida),
+		errors.Is(err, carteira.ErrLancamentoInvalido),
+		errors.Is(err, meiopag.ErrOperacaoNaoPermitida), errors.Is(err, meiopag.ErrValorForaDoLimite):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+	default:
+		logger.Error("erro ao acessar a camada de service do saque", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"MSG": "Error ao processar saque", "codigo": 500}`))
+	}
+}
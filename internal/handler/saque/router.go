@@ -0,0 +1,47 @@
+package saque
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/psp"
+	"github.com/katana/fortuna/backend-go/pkg/service/saque"
+)
+
+// RegisterSaqueAPIHandlers registra o pedido e a consulta dos saques e a fila
+// de aprovação. O pedido exige token de cliente, que é o dono do saque; a
+// consulta é do próprio cliente ou de um admin e a fila exige role admin.
+// Fora de produção, com PSP de teste, também registra chaves no DICT simulado.
+func RegisterSaqueAPIHandlers(r chi.Router, service saque.SaqueServiceInterface, conf *config.Config, provedor psp.PSPInterface, idempotencia func(http.Handler) http.Handler) {
+	r.Route("/api/v1/saque", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(conf.TokenAuth))
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.ExigirCliente())
+
+			r.With(idempotencia).Post("/", solicitarSaque(service))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.ExigirRole("cliente", "admin"))
+
+			r.Get("/all", getAllSaque(service))
+			r.Get("/getbyid/{id}", getByIdSaque(service))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.ExigirRole("admin"))
+
+			r.Get("/fila", getFilaAprovacao(service))
+			r.Post("/{id}/aprovar", aprovarSaque(service))
+			r.Post("/{id}/rejeitar", rejeitarSaque(service))
+		})
+
+		if simulador, ok := provedor.(psp.Simulador); ok && conf.Mode != config.PRODUCTION {
+			r.Post("/simular/chave", registrarChave(simulador))
+		}
+	})
+}
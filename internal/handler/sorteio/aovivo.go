@@ -0,0 +1,136 @@
+package sorteio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/aovivo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+)
+
+const (
+	// intervaloVendas agrupa os eventos de vendas: o cliente recebe no máximo
+	// um por intervalo, sempre com os totais mais recentes.
+	intervaloVendas = time.Second
+	// intervaloPing mantém a conexão viva em proxies que derrubam conexões ociosas.
+	intervaloPing = 15 * time.Second
+)
+
+// aoVivoSorteio transmite o painel do sorteio por Server-Sent Events: primeiro
+// o estado atual e depois os eventos publicados por qualquer réplica.
+func aoVivoSorteio(service aovivo.AoVivoServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ID := chi.URLParam(r, "id")
+
+		// Assina antes de ler o estado para não perder eventos entre os dois.
+		eventos, sair, err := service.Assinar(r.Context(), ID)
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"MSG": "Painel ao vivo indisponível", "codigo": 503}`))
+			return
+		}
+		defer sair()
+
+		estado, err := service.Estado(r.Context(), ID)
+		if err != nil {
+			if errors.Is(err, sorteio.ErrSorteioNaoEncontrado) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+				return
+			}
+			logger.Error("erro ao consultar estado ao vivo do sorteio", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error ao consultar Sorteio", "codigo": 500}`))
+			return
+		}
+
+		// A conexão fica aberta além do WriteTimeout do servidor.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			logger.Error("erro ao remover prazo de escrita do painel ao vivo", err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		data, _ := json.Marshal(estado)
+		if err := escreverEvento(w, rc, model.EventoEstado, data); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(intervaloVendas)
+		defer ticker.Stop()
+
+		var vendas []byte
+		ultimo := time.Now()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+
+			case msg, ok := <-eventos:
+				if !ok {
+					return
+				}
+
+				evento := model.EventoAoVivo{}
+				if err := json.Unmarshal(msg, &evento); err != nil {
+					logger.Error("evento ao vivo ilegível", err)
+					continue
+				}
+				if evento.Tipo == model.EventoVendas {
+					vendas = msg
+					continue
+				}
+
+				// Os totais pendentes saem antes para manter a ordem dos eventos.
+				if vendas != nil {
+					if err := escreverEvento(w, rc, model.EventoVendas, vendas); err != nil {
+						return
+					}
+					vendas = nil
+				}
+				if err := escreverEvento(w, rc, evento.Tipo, msg); err != nil {
+					return
+				}
+				ultimo = time.Now()
+
+			case <-ticker.C:
+				switch {
+				case vendas != nil:
+					if err := escreverEvento(w, rc, model.EventoVendas, vendas); err != nil {
+						return
+					}
+					vendas = nil
+				case time.Since(ultimo) >= intervaloPing:
+					if _, err := w.Write([]byte(": ping\n\n")); err != nil {
+						return
+					}
+					if err := rc.Flush(); err != nil {
+						return
+					}
+				default:
+					continue
+				}
+				ultimo = time.Now()
+			}
+		}
+	}
+}
+
+func escreverEvento(w http.ResponseWriter, rc *http.ResponseController, tipo string, data []byte) error {
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", tipo, data); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
@@ -0,0 +1,342 @@
+package sorteio
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+)
+
+func createSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		str := &model.Sorteio{}
+
+		err := json.NewDecoder(r.Body).Decode(str)
+		if err != nil {
+			logger.Error("error decoding request body", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		result, err := service.Create(r.Context(), *str)
+		if err != nil {
+			if errors.Is(err, sorteio.ErrSorteioInvalido) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			logger.Error("erro ao acessar a camada de service do sorteio", err)
+			http.Error(w, "Error ou salvar Sorteio", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func updateSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idp := chi.URLParam(r, "id")
+
+		_, err := service.GetByID(r.Context(), idp)
+		if err != nil {
+			http.Error(w, "Sorteio nao encontrado", http.StatusNotFound)
+			return
+		}
+
+		str := &model.Sorteio{}
+		err = json.NewDecoder(r.Body).Decode(str)
+		if err != nil {
+			logger.Error("error decoding request body", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		_, err = service.Update(r.Context(), idp, str)
+		if err != nil {
+			if errors.Is(err, sorteio.ErrTransicaoInvalida) {
+				http.Error(w, "Sorteio só pode ser alterado enquanto agendado", http.StatusConflict)
+				return
+			}
+			if errors.Is(err, sorteio.ErrSorteioInvalido) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			logger.Error("erro ao acessar a camada de service do sorteio no upd", err)
+			http.Error(w, "Error ao atualizar sorteio", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"MSG": "Success", "codigo": 1})
+	}
+}
+
+func getByIdSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		idp := chi.URLParam(r, "id")
+		result, err := service.GetByID(r.Context(), idp)
+		if err != nil {
+			logger.Error("erro ao acessar a camada de service do sorteio no por id", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+			return
+		}
+
+		err = json.NewEncoder(w).Encode(result)
+		if err != nil {
+			logger.Error("erro ao converter em json", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error to parse Sorteio to JSON", "codigo": 500}`))
+			return
+		}
+	}
+}
+
+func getAllSorteio(service sorteio.SorteioServiceInterface) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		filters := model.FilterSorteio{
+			Nome:    r.URL.Query().Get("nome"),
+			Jogo:    r.URL.Query().Get("jogo"),
+			Status:  r.URL.Query().Get("status"),
+			Enabled: r.URL.Query().Get("enabled"),
+		}
+
+		limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+
+		result, err := service.GetAll(r.Context(), filters, limit, page)
+		if err != nil {
+			logger.Error("erro ao acessar a camada de service do sorteio no all", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Sorteio not found", "codigo": 404}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		err = json.NewEncoder(w).Encode(result)
+		if err != nil {
+			logger.Error("erro ao converter para json", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error to parse Sorteio to JSON", "codigo": 500}`))
+			return
+		}
+	})
+}
+
+func abrirSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := service.Abrir(r.Context(), chi.URLParam(r, "id"))
+		responderTransicao(w, result, err)
+	}
+}
+
+func fecharSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := service.Fechar(r.Context(), chi.URLParam(r, "id"))
+		responderTransicao(w, result, err)
+	}
+}
+
+func sortearSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			FonteEntropia string `json:"fonte_entropia"`
+			Entropia      string `json:"entropia"`
+		}{}
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			logger.Error("error decoding request body", err)
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		result, err := service.Sortear(r.Context(), chi.URLParam(r, "id"), body.FonteEntropia, body.Entropia)
+		responderTransicao(w, result, err)
+	}
+}
+
+// reprocessarSorteio repete as etapas pendentes do sorteio, como o estorno
+// das apostas de um sorteio cancelado.
+func reprocessarSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := service.Reprocessar(r.Context(), chi.URLParam(r, "id"))
+		responderTransicao(w, result, err)
+	}
+}
+
+func liquidarSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := service.Liquidar(r.Context(), chi.URLParam(r, "id"))
+		responderTransicao(w, result, err)
+	}
+}
+
+func cancelarSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Motivo string `json:"motivo"`
+		}{}
+
+		// O motivo é opcional; um corpo vazio não impede o cancelamento.
+		json.NewDecoder(r.Body).Decode(&body)
+
+		result, err := service.Cancelar(r.Context(), chi.URLParam(r, "id"), body.Motivo)
+		responderTransicao(w, result, err)
+	}
+}
+
+func verificarSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := service.Verificar(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			logger.Error("erro ao verificar sorteio", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getProvaInclusao(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := service.ProvaInclusao(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "aposta"))
+		if err != nil {
+			switch {
+			case errors.Is(err, sorteio.ErrSorteioNaoEncontrado):
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+			case errors.Is(err, sorteio.ErrApostaNaoEncontrada):
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"MSG": "Aposta não encontrada no sorteio", "codigo": 404}`))
+			case errors.Is(err, sorteio.ErrSemCompromisso):
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"MSG": "Apostas do sorteio ainda não comprometidas", "codigo": 409}`))
+			case errors.Is(err, sorteio.ErrApostaNaoComprometida):
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"MSG": "Aposta fora do conjunto comprometido no fechamento", "codigo": 422}`))
+			default:
+				logger.Error("erro ao gerar prova de inclusão", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"MSG": "Error ao gerar prova de inclusão", "codigo": 500}`))
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getRateioSorteio(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := service.Rateio(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			logger.Error("erro ao consultar rateio do sorteio", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getEstimativaJogo(service sorteio.SorteioServiceInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := service.Estimativa(r.Context(), chi.URLParam(r, "jogo"))
+		if err != nil {
+			if errors.Is(err, sorteio.ErrSemProximoSorteio) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"MSG": "Nenhum sorteio programado para o jogo", "codigo": 404}`))
+				return
+			}
+			logger.Error("erro ao estimar premio do jogo", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error ao estimar premio", "codigo": 500}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func getAllJogos() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		type Jogo struct {
+			Nome      string       `json:"nome"`
+			Descricao string       `json:"descricao"`
+			Formato   jogo.Formato `json:"formato"`
+		}
+
+		result := make([]Jogo, 0)
+		for _, g := range jogo.Listar() {
+			result = append(result, Jogo{Nome: g.Nome(), Descricao: g.Descricao(), Formato: g.Formato()})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func responderTransicao(w http.ResponseWriter, result *model.Sorteio, err error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sorteio.ErrSorteioNaoEncontrado):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"MSG": "Sorteio não encontrado", "codigo": 404}`))
+		case errors.Is(err, sorteio.ErrTransicaoInvalida):
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"MSG": "Transição de status não permitida", "codigo": 409}`))
+		case errors.Is(err, sorteio.ErrTransicaoConcorrente):
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"MSG": "Sorteio alterado por outra requisição", "codigo": 409}`))
+		case errors.Is(err, sorteio.ErrEntropiaObrigatoria):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"MSG": "Entropia pública obrigatória", "codigo": 400}`))
+		case errors.Is(err, sorteio.ErrEtapasPendentes):
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 409})
+		case errors.Is(err, sorteio.ErrFonteDivergente):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]interface{}{"MSG": err.Error(), "codigo": 422})
+		case errors.Is(err, sorteio.ErrForaDaJanela):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"MSG": "Fora da janela de vendas do sorteio", "codigo": 422}`))
+		default:
+			logger.Error("erro ao alterar status do sorteio", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error ao alterar status do sorteio", "codigo": 500}`))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,45 @@
+package sorteio
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth"
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/handler"
+	"github.com/katana/fortuna/backend-go/pkg/service/aovivo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+)
+
+// RegisterSorteioPIHandlers registra as rotas de sorteio. Criar, alterar,
+// mudar o ciclo de vida do sorteio e reprocessar as etapas que falharam são
+// operações de administrador.
+func RegisterSorteioPIHandlers(r chi.Router, service sorteio.SorteioServiceInterface, painel aovivo.AoVivoServiceInterface, conf *config.Config) {
+	r.Route("/api/v1/sorteio", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(conf.TokenAuth))
+			r.Use(handler.ExigirRole("admin"))
+
+			r.Post("/add", createSorteio(service))
+			r.Put("/update/{id}", updateSorteio(service))
+			r.Post("/abrir/{id}", abrirSorteio(service))
+			r.Post("/fechar/{id}", fecharSorteio(service))
+			r.Post("/sortear/{id}", sortearSorteio(service))
+			r.Post("/liquidar/{id}", liquidarSorteio(service))
+			r.Post("/cancelar/{id}", cancelarSorteio(service))
+			r.Post("/reprocessar/{id}", reprocessarSorteio(service))
+		})
+
+		r.Get("/getbyid/{id}", getByIdSorteio(service))
+		r.Get("/all", func(w http.ResponseWriter, r *http.Request) {
+			handler := getAllSorteio(service)
+			handler.ServeHTTP(w, r)
+		})
+		r.Get("/verificar/{id}", verificarSorteio(service))
+		r.Get("/prova/{id}/{aposta}", getProvaInclusao(service))
+		r.Get("/rateio/{id}", getRateioSorteio(service))
+		r.Get("/jogos", getAllJogos())
+		r.Get("/estimativa/{jogo}", getEstimativaJogo(service))
+		r.Get("/{id}/ao-vivo", aoVivoSorteio(painel))
+	})
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
 	"strconv"
 	"time"
 
@@ -11,7 +12,9 @@ import (
 	"github.com/go-chi/jwtauth"
 	"github.com/katana/fortuna/backend-go/internal/config/logger"
 	"github.com/katana/fortuna/backend-go/internal/dto"
+	"github.com/katana/fortuna/backend-go/internal/handler"
 	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/cliente"
 	"github.com/katana/fortuna/backend-go/pkg/service/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -161,7 +164,10 @@ func getAllUsuario(service user.UserServiceInterface) http.Handler {
 	})
 }
 
-func PegarJwt(service user.UserServiceInterface) http.HandlerFunc {
+// PegarJwt autentica o usuário e emite o token. Usuário ligado a um cliente
+// leva o id dele na claim handler.ClaimCliente, que as rotas de carteira e de
+// aposta usam como dono da operação.
+func PegarJwt(service user.UserServiceInterface, clientes cliente.ClienteServiceInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
 		jwt := r.Context().Value("jwt").(*jwtauth.JWTAuth)
@@ -205,6 +211,17 @@ func PegarJwt(service user.UserServiceInterface) http.HandlerFunc {
 			"role": userExist.Role,
 		}
 
+		cli, err := clientes.GetByUsuario(r.Context(), userExist.ID)
+		switch {
+		case err == nil:
+			tokenClaims[handler.ClaimCliente] = cli.ID.Hex()
+		case !errors.Is(err, cliente.ErrClienteNaoEncontrado):
+			logger.Error("erro ao localizar cliente do usuario", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"MSG": "Error ao gerar token", "codigo": 500}`))
+			return
+		}
+
 		// Gera o token com as informações incluídas
 		_, tokenString, _ := jwt.Encode(tokenClaims)
 		accessToken := dto.GetJWTOutput{AccessToken: tokenString}
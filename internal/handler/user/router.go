@@ -4,13 +4,14 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/katana/fortuna/backend-go/pkg/service/cliente"
 	"github.com/katana/fortuna/backend-go/pkg/service/user"
 )
 
-func RegisterUsuarioAPIHandlers(r chi.Router, service user.UserServiceInterface) {
+func RegisterUsuarioAPIHandlers(r chi.Router, service user.UserServiceInterface, clientes cliente.ClienteServiceInterface) {
 	r.Route("/api/v1/usuario", func(r chi.Router) {
 		r.Post("/add", createUser(service))
-		r.Post("/getjwt", PegarJwt(service))
+		r.Post("/getjwt", PegarJwt(service, clientes))
 		r.Put("/update/{id}/{nome}", updateUser(service))
 		r.Get("/getbyid/{id}", getByIdUsuario(service)) // Adicionado a barra no início
 		r.Get("/all", func(w http.ResponseWriter, r *http.Request) {
@@ -9,11 +9,17 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 type MongoDBInterface interface {
 	GetCollection(collectionName string) *mongo.Collection
 	GetCollectionByName(name string) *mongo.Collection
+	// WithTransaction executa fn numa transação multi-documento, repetindo-a
+	// nos erros transitórios. As operações de fn devem usar o sessCtx recebido.
+	// Exige o MongoDB em replica set.
+	WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) (interface{}, error)) (interface{}, error)
 }
 
 type mongodb_pool struct {
@@ -63,6 +69,20 @@ func (d *mongodb_pool) GetCollectionByName(name string) (DBCollection *mongo.Col
 	return d.DB.Database(d.DBName).Collection(name)
 }
 
+func (d *mongodb_pool) WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) (interface{}, error)) (interface{}, error) {
+	session, err := d.DB.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	opts := options.Transaction().
+		SetReadConcern(readconcern.Snapshot()).
+		SetWriteConcern(writeconcern.Majority())
+
+	return session.WithTransaction(ctx, fn, opts)
+}
+
 func ObjectIDFromHex(hex string) (objectID primitive.ObjectID, err error) {
 	objectID, err = primitive.ObjectIDFromHex(hex)
 	if err != nil {
@@ -0,0 +1,131 @@
+package psp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/pkg/service/pix/brcode"
+)
+
+// ispbFake identifica o PSP de teste nos EndToEndIDs gerados.
+const ispbFake = "00000000"
+
+const alfanumerico = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// documento reconhece as chaves CPF e CNPJ, já só com dígitos.
+var documento = regexp.MustCompile(`^([0-9]{11}|[0-9]{14})$`)
+
+// FakePSP é um PSP local, sem rede, para homologação e testes. As cobranças
+// apontam para um host fictício e os pagamentos só acontecem por
+// SimularPagamento, que assina a notificação com o mesmo segredo do webhook.
+// O DICT de teste resolve chaves CPF e CNPJ para o próprio documento; as
+// demais precisam de RegistrarChave e ficam só na memória do processo.
+type FakePSP struct {
+	segredo string
+
+	mu       sync.Mutex
+	chaves   map[string]DonoChave
+	enviados map[string]*PixEnviado
+}
+
+func NewFakePSP(segredo string) *FakePSP {
+	return &FakePSP{
+		segredo:  segredo,
+		chaves:   make(map[string]DonoChave),
+		enviados: make(map[string]*PixEnviado),
+	}
+}
+
+func (f *FakePSP) CriarCobranca(ctx context.Context, cob Cobranca) (*CobrancaCriada, error) {
+	return &CobrancaCriada{
+		TxID:     cob.TxID,
+		Location: "pix.fake.fortuna.local/qr/v2/" + Aleatorio(32),
+		ExpiraEm: time.Now().Add(cob.Expiracao),
+	}, nil
+}
+
+func (f *FakePSP) VerificarWebhook(corpo []byte, assinatura string) (*Notificacao, error) {
+	return verificarAssinatura(f.segredo, corpo, assinatura)
+}
+
+func (f *FakePSP) SimularPagamento(ctx context.Context, txid string, valor int64) ([]byte, string, error) {
+	agora := time.Now()
+	notificacao := Notificacao{Pix: []PagamentoPix{{
+		EndToEndID: "E" + ispbFake + agora.UTC().Format("200601021504") + Aleatorio(11),
+		TxID:       txid,
+		Valor:      brcode.Valor(valor),
+		Horario:    agora,
+	}}}
+
+	corpo, err := json.Marshal(notificacao)
+	if err != nil {
+		return nil, "", err
+	}
+	return corpo, Assinar(f.segredo, corpo), nil
+}
+
+func (f *FakePSP) ConsultarChave(ctx context.Context, chave string) (*DonoChave, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if dono, ok := f.chaves[chave]; ok {
+		return &dono, nil
+	}
+	if documento.MatchString(chave) {
+		return &DonoChave{Chave: chave, Documento: chave, Nome: "Titular " + chave}, nil
+	}
+	return nil, ErrChaveNaoEncontrada
+}
+
+func (f *FakePSP) RegistrarChave(dono DonoChave) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.chaves[dono.Chave] = dono
+}
+
+// EnviarPix confirma na hora; repetir o ID devolve o mesmo envio. Recusa
+// valores não positivos e chaves fora do DICT de teste.
+func (f *FakePSP) EnviarPix(ctx context.Context, transferencia Transferencia) (*PixEnviado, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if enviado, ok := f.enviados[transferencia.ID]; ok {
+		return enviado, nil
+	}
+
+	if transferencia.Valor <= 0 {
+		return nil, fmt.Errorf("%w: valor %d", ErrPixRecusado, transferencia.Valor)
+	}
+	if _, ok := f.chaves[transferencia.Chave]; !ok && !documento.MatchString(transferencia.Chave) {
+		return nil, fmt.Errorf("%w: %v", ErrPixRecusado, ErrChaveNaoEncontrada)
+	}
+
+	agora := time.Now()
+	enviado := &PixEnviado{
+		EndToEndID: "E" + ispbFake + agora.UTC().Format("200601021504") + Aleatorio(11),
+		Horario:    agora,
+	}
+	f.enviados[transferencia.ID] = enviado
+	return enviado, nil
+}
+
+// Aleatorio gera identificadores alfanuméricos como txid e EndToEndID.
+func Aleatorio(tamanho int) string {
+	b := make([]byte, tamanho)
+	max := big.NewInt(int64(len(alfanumerico)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		b[i] = alfanumerico[n.Int64()]
+	}
+	return string(b)
+}
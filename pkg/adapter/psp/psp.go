@@ -0,0 +1,125 @@
+package psp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+)
+
+// HeaderAssinatura leva o HMAC-SHA256 do corpo do webhook, como "sha256=<hex>".
+const HeaderAssinatura = "X-Webhook-Signature"
+
+var (
+	ErrAssinaturaInvalida = errors.New("assinatura do webhook do PSP inválida")
+	ErrPSPDesconhecido    = errors.New("PSP não suportado")
+	ErrChaveNaoEncontrada = errors.New("chave Pix não encontrada no DICT")
+	// ErrPixRecusado é a recusa definitiva de um envio: o PSP garante que o
+	// Pix não saiu. Qualquer outro erro de EnviarPix deixa o envio incerto.
+	ErrPixRecusado = errors.New("Pix recusado pelo PSP")
+)
+
+// Cobranca é o pedido de uma cobrança Pix dinâmica ao PSP. Valor em centavos.
+type Cobranca struct {
+	TxID      string
+	Valor     int64
+	Chave     string
+	Descricao string
+	Expiracao time.Duration
+}
+
+// CobrancaCriada traz a URL do payload que vai no QR dinâmico.
+type CobrancaCriada struct {
+	TxID     string
+	Location string
+	ExpiraEm time.Time
+}
+
+// PagamentoPix é um Pix recebido, no formato do webhook da API Pix do BC:
+// o valor vem como texto em reais, ex.: "10.50".
+type PagamentoPix struct {
+	EndToEndID string    `json:"endToEndId"`
+	TxID       string    `json:"txid"`
+	Valor      string    `json:"valor"`
+	Horario    time.Time `json:"horario"`
+}
+
+type Notificacao struct {
+	Pix []PagamentoPix `json:"pix"`
+}
+
+// DonoChave é o titular de uma chave Pix segundo o DICT. Documento só com dígitos.
+type DonoChave struct {
+	Chave     string `json:"chave"`
+	Documento string `json:"documento"`
+	Nome      string `json:"nome"`
+}
+
+// Transferencia é um Pix enviado pela casa. ID identifica o envio no PSP, que
+// não paga duas vezes o mesmo ID. Valor em centavos.
+type Transferencia struct {
+	ID    string
+	Chave string
+	Valor int64
+}
+
+type PixEnviado struct {
+	EndToEndID string
+	Horario    time.Time
+}
+
+// PSPInterface é o que o serviço de Pix precisa do provedor de pagamentos.
+type PSPInterface interface {
+	CriarCobranca(ctx context.Context, cob Cobranca) (*CobrancaCriada, error)
+	// VerificarWebhook confere a assinatura e devolve os pagamentos notificados.
+	VerificarWebhook(corpo []byte, assinatura string) (*Notificacao, error)
+	// ConsultarChave busca no DICT o titular da chave.
+	ConsultarChave(ctx context.Context, chave string) (*DonoChave, error)
+	// EnviarPix devolve ErrPixRecusado quando o PSP recusa o envio de vez.
+	EnviarPix(ctx context.Context, transferencia Transferencia) (*PixEnviado, error)
+}
+
+// Simulador é implementado pelos PSPs de teste: gera a notificação assinada
+// de um pagamento como se o PSP tivesse chamado o webhook.
+type Simulador interface {
+	SimularPagamento(ctx context.Context, txid string, valor int64) (corpo []byte, assinatura string, err error)
+	// RegistrarChave cadastra no DICT de teste uma chave de e-mail, telefone ou aleatória.
+	RegistrarChave(dono DonoChave)
+}
+
+// NewPSP escolhe o adaptador pelo nome configurado em PixPSP.
+func NewPSP(conf *config.Config) (PSPInterface, error) {
+	switch conf.PixPSP {
+	case config.PSPFake:
+		return NewFakePSP(conf.PixWebhookSegredo), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrPSPDesconhecido, conf.PixPSP)
+}
+
+// Assinar calcula o valor do HeaderAssinatura para o corpo.
+func Assinar(segredo string, corpo []byte) string {
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write(corpo)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verificarAssinatura compara em tempo constante e decodifica a notificação.
+func verificarAssinatura(segredo string, corpo []byte, assinatura string) (*Notificacao, error) {
+	esperada := Assinar(segredo, corpo)
+	if !hmac.Equal([]byte(esperada), []byte(strings.TrimSpace(assinatura))) {
+		return nil, ErrAssinaturaInvalida
+	}
+
+	notificacao := &Notificacao{}
+	if err := json.Unmarshal(corpo, notificacao); err != nil {
+		return nil, fmt.Errorf("notificação do PSP ilegível: %w", err)
+	}
+	return notificacao, nil
+}
@@ -0,0 +1,140 @@
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// FilaConsumo monta a declaração da fila do ConsumerConfig com a política de
+// retry configurada. Quem publica e quem consome devem usar a mesma declaração,
+// já que o RabbitMQ recusa redeclarar uma fila com argumentos diferentes. A
+// fila sempre tem o parking pelo dead-letter; com MaxRetry zero a mensagem
+// com falha vai direto para ele.
+func FilaConsumo(cc config.ConsumerConfig) Fila {
+	tentativas := cc.MaxRetry
+	if tentativas < 0 {
+		tentativas = 0
+	}
+	return Fila{
+		Name:    cc.QueueName,
+		Durable: true,
+		Retry: &Retry{
+			Tentativas: tentativas,
+			Atraso:     time.Duration(cc.RetryDelay) * time.Second,
+		},
+	}
+}
+
+// ConsumirPool abre ConsumerCount consumidores na fila do ConsumerConfig,
+// cada um no próprio canal com prefetch de PrefetchCount mensagens. Quando
+// ExchangeName é informado o exchange é declarado e a fila ligada a ele pela
+// RoutingKey. As mensagens chegam sem auto-ack: o callback decide Ack ou Nack.
+func (rbm *rbm_pool) ConsumirPool(cc config.ConsumerConfig, callback func(msg *amqp.Delivery)) error {
+	if rbm.conn == nil || rbm.conn.IsClosed() {
+		return errors.New("conexão com o RabbitMQ fechada")
+	}
+
+	if cc.ExchangeName != "" {
+		if err := rbm.channel.ExchangeDeclare(
+			cc.ExchangeName, // name
+			cc.ExchangeType, // type
+			true,            // durable
+			false,           // auto-deleted
+			false,           // internal
+			false,           // no-wait
+			nil,             // arguments
+		); err != nil {
+			logger.Error("Erro to ExchangeDeclare in RabbitMQ", err)
+			return err
+		}
+
+		if err := rbm.channel.QueueBind(cc.QueueName, cc.RoutingKey, cc.ExchangeName, false, nil); err != nil {
+			logger.Error("Erro to QueueBind in RabbitMQ", err)
+			return err
+		}
+	}
+
+	consumidores := cc.ConsumerCount
+	if consumidores <= 0 {
+		consumidores = 1
+	}
+
+	for i := 0; i < consumidores; i++ {
+		ch, err := rbm.conn.Channel()
+		if err != nil {
+			logger.Error("Erro to Connect in RabbitMQ Channel", err)
+			return err
+		}
+
+		if cc.PrefetchCount > 0 {
+			if err := ch.Qos(cc.PrefetchCount, 0, false); err != nil {
+				logger.Error("Erro to set Qos in RabbitMQ Channel", err)
+				return err
+			}
+		}
+
+		tag := fmt.Sprintf("%s-%d", cc.ConsumerName, i+1)
+		msgs, err := ch.Consume(
+			cc.QueueName, // queue
+			tag,          // consumer
+			false,        // auto-ack
+			false,        // exclusive
+			false,        // no-local
+			false,        // no-wait
+			nil,          // args
+		)
+		if err != nil {
+			logger.Error("Failed to register a consumer", err)
+			return err
+		}
+
+		go func(tag string, msgs <-chan amqp.Delivery) {
+			logger.Info("Start Consumer " + tag)
+			for msg := range msgs {
+				callback(&msg)
+			}
+			logger.Info("Close Consumer " + tag)
+		}(tag, msgs)
+	}
+
+	return nil
+}
+
+// Manter fica aguardando a queda da conexão e reconecta a cada intervalo
+// segundos, até tentativas falhas seguidas (0 tenta para sempre). Depois de
+// reconectar chama aoReconectar, que deve registrar de novo os consumidores.
+// Só retorna quando desiste de reconectar.
+func (rbm *rbm_pool) Manter(tentativas, intervalo int, aoReconectar func() error) error {
+	if intervalo <= 0 {
+		intervalo = 30
+	}
+
+	for {
+		err := <-rbm.err
+		logger.Error("Connection is closed, trying to reconnect in RabbitMQ", err)
+
+		for falhas := 1; ; falhas++ {
+			time.Sleep(time.Duration(intervalo) * time.Second)
+
+			err = rbm.Connect()
+			if err == nil && aoReconectar != nil {
+				if err = aoReconectar(); err != nil {
+					rbm.conn.Close()
+				}
+			}
+			if err == nil {
+				logger.Info("Reconnect RabbitMQ Success")
+				break
+			}
+
+			if tentativas > 0 && falhas >= tentativas {
+				return fmt.Errorf("RabbitMQ indisponível após %d tentativas: %w", falhas, err)
+			}
+		}
+	}
+}
@@ -2,32 +2,44 @@ package rabbitmq
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 
 	"github.com/katana/fortuna/backend-go/internal/config/logger"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-func (rbm *rbm_pool) SenderRb(ctx context.Context, exchange_name string, msg *Message) error {
-	logger.Info("entro na FUNCAO DE PUBLICACAO")
+// SenderRb publica a mensagem na fila pelo exchange padrão, que entrega pela
+// routing key igual ao nome da fila. As mensagens são persistentes.
+func (rbm *rbm_pool) SenderRb(ctx context.Context, queue_name string, msg *Message) error {
 	if rbm.channel == nil {
-		logger.Info("RMB.CHANNEL E NULL")
-	}
-	if ctx == nil {
-		logger.Info("ctx E NULL")
+		return errors.New("canal do RabbitMQ não conectado")
 	}
+
 	err := rbm.channel.PublishWithContext(ctx,
-		exchange_name, // exchange amq.direct
-		"",            // routing key
-		false,         // mandatory
-		false,         // immediate
+		"",         // exchange padrão
+		queue_name, // routing key
+		false,      // mandatory
+		false,      // immediate
 		amqp.Publishing{
-			Body:        msg.Data,
-			ContentType: msg.ContentType,
+			Body:         msg.Data,
+			ContentType:  msg.ContentType,
+			MessageId:    novoMessageID(),
+			DeliveryMode: amqp.Persistent,
 		})
 
 	if err != nil {
-		logger.Error("DEU MERDA AQUI NA PUPLICACAO", err)
+		logger.Error("erro ao publicar mensagem no RabbitMQ", err)
+		return err
 	}
 
 	return nil
 }
+
+// novoMessageID identifica a mensagem para as operações de admin no parking.
+func novoMessageID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -17,6 +17,12 @@ type RabbitInterface interface {
 	Consumer(queue_name string, callback func(msg *amqp.Delivery))
 	Connect() error
 	Start(queue_name string, callback func(msg *amqp.Delivery))
+	ConsumirPool(cc config.ConsumerConfig, callback func(msg *amqp.Delivery)) error
+	Manter(tentativas, intervalo int, aoReconectar func() error) error
+	Retentar(ctx context.Context, queue_name string, msg *amqp.Delivery, motivo error) error
+	ListarEstacionadas(ctx context.Context, queue_name string, limite int) ([]MensagemEstacionada, error)
+	ReprocessarEstacionada(ctx context.Context, queue_name string, ID string) error
+	DescartarEstacionada(ctx context.Context, queue_name string, ID string) error
 }
 
 type Message struct {
@@ -31,6 +37,7 @@ type Fila struct {
 	Exclusive  bool       // exclusive
 	NoWait     bool       // no-wait
 	Arguments  amqp.Table // arguments
+	Retry      *Retry     // política de novas tentativas; nil desliga
 }
 
 type rbm_pool struct {
@@ -65,9 +72,12 @@ func (rbm *rbm_pool) Connect() error {
 		return err
 	}
 
+	conn := rbm.conn
 	go func() {
-		<-rbm.conn.NotifyClose(make(chan *amqp.Error)) //Listen to NotifyClose
-		rbm.err <- errors.New("connection closed")
+		// Um Close explícito não traz erro e não deve disparar a reconexão.
+		if e := <-conn.NotifyClose(make(chan *amqp.Error, 1)); e != nil {
+			rbm.err <- e
+		}
 	}()
 
 	rbm.channel, err = rbm.conn.Channel()
@@ -77,6 +87,14 @@ func (rbm *rbm_pool) Connect() error {
 	}
 
 	for _, fl := range rbm.queues {
+		if fl.Retry != nil {
+			if err = rbm.declararRetry(fl); err != nil {
+				logger.Error("Erro to declare retry queues in RabbitMQ", err)
+				return err
+			}
+			continue
+		}
+
 		_, err = rbm.channel.QueueDeclare(
 			fl.Name,       // name
 			fl.Durable,    // durable
@@ -0,0 +1,275 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Headers gravados nas mensagens que passam pela política de retry.
+const (
+	HeaderTentativas    = "x-retry-count"
+	HeaderMotivo        = "x-retry-motivo"
+	HeaderFilaOrigem    = "x-fila-origem"
+	HeaderEstacionadaEm = "x-estacionada-em"
+)
+
+var ErrMensagemNaoEncontrada = errors.New("mensagem não encontrada na fila de estacionamento")
+
+// Retry é a política de novas tentativas de uma fila. A tentativa k espera
+// Atraso * 2^(k-1) numa fila de espera "<fila>.retry.<k>" cujo TTL devolve a
+// mensagem à fila principal pelo dead-letter. Esgotadas as Tentativas a
+// mensagem vai para "<fila>.parking", onde fica até um admin decidir.
+// Mensagens rejeitadas sem requeue também caem no parking pelo DLX da fila.
+type Retry struct {
+	Tentativas int
+	Atraso     time.Duration
+}
+
+// MensagemEstacionada é uma mensagem parada no parking, exposta aos admins.
+type MensagemEstacionada struct {
+	ID            string    `json:"id"`
+	FilaOrigem    string    `json:"fila_origem"`
+	Tentativas    int64     `json:"tentativas"`
+	Motivo        string    `json:"motivo"`
+	EstacionadaEm time.Time `json:"estacionada_em"`
+	ContentType   string    `json:"content_type"`
+	Corpo         string    `json:"corpo"`
+}
+
+func NomeEstacionamento(queue_name string) string {
+	return queue_name + ".parking"
+}
+
+func nomeEspera(queue_name string, tentativa int) string {
+	return fmt.Sprintf("%s.retry.%d", queue_name, tentativa)
+}
+
+// AtrasoTentativa devolve quanto a tentativa (a partir de 1) espera antes de voltar à fila.
+func (rt *Retry) AtrasoTentativa(tentativa int) time.Duration {
+	return rt.Atraso << (tentativa - 1)
+}
+
+// declararRetry declara a fila principal com o DLX apontando para o parking,
+// o parking e uma fila de espera por tentativa.
+func (rbm *rbm_pool) declararRetry(fl Fila) error {
+	args := amqp.Table{}
+	for k, v := range fl.Arguments {
+		args[k] = v
+	}
+	args["x-dead-letter-exchange"] = ""
+	args["x-dead-letter-routing-key"] = NomeEstacionamento(fl.Name)
+
+	if _, err := rbm.channel.QueueDeclare(fl.Name, fl.Durable, fl.AutoDelete, fl.Exclusive, fl.NoWait, args); err != nil {
+		return err
+	}
+
+	if _, err := rbm.channel.QueueDeclare(NomeEstacionamento(fl.Name), true, false, false, false, nil); err != nil {
+		return err
+	}
+
+	for k := 1; k <= fl.Retry.Tentativas; k++ {
+		espera := amqp.Table{
+			"x-message-ttl":             fl.Retry.AtrasoTentativa(k).Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": fl.Name,
+		}
+		if _, err := rbm.channel.QueueDeclare(nomeEspera(fl.Name, k), true, false, false, false, espera); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Retentar agenda uma nova tentativa da mensagem conforme a política da fila,
+// ou a estaciona quando as tentativas acabaram, e então confirma a original.
+// Se a publicação falhar a mensagem original fica sem ack e o chamador decide.
+func (rbm *rbm_pool) Retentar(ctx context.Context, queue_name string, msg *amqp.Delivery, motivo error) error {
+	var politica *Retry
+	for _, fl := range rbm.queues {
+		if fl.Name == queue_name {
+			politica = fl.Retry
+		}
+	}
+	if politica == nil {
+		return fmt.Errorf("fila %s sem política de retry", queue_name)
+	}
+
+	tentativas := contarTentativas(msg.Headers)
+
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[HeaderMotivo] = motivo.Error()
+	headers[HeaderFilaOrigem] = queue_name
+
+	destino := NomeEstacionamento(queue_name)
+	if tentativas < int64(politica.Tentativas) {
+		tentativas++
+		destino = nomeEspera(queue_name, int(tentativas))
+	} else {
+		headers[HeaderEstacionadaEm] = time.Now().Format(time.RFC3339)
+	}
+	headers[HeaderTentativas] = tentativas
+
+	if err := rbm.republicar(ctx, rbm.channel, destino, msg, headers); err != nil {
+		logger.Error("erro ao agendar nova tentativa no RabbitMQ", err)
+		return err
+	}
+
+	return msg.Ack(false)
+}
+
+// ListarEstacionadas devolve até limite mensagens do parking sem removê-las.
+func (rbm *rbm_pool) ListarEstacionadas(ctx context.Context, queue_name string, limite int) ([]MensagemEstacionada, error) {
+	result := make([]MensagemEstacionada, 0)
+
+	err := rbm.percorrerEstacionadas(queue_name, func(ch *amqp.Channel, msg *amqp.Delivery) (bool, error) {
+		result = append(result, estacionada(msg))
+		return len(result) >= limite, nil
+	})
+
+	return result, err
+}
+
+// ReprocessarEstacionada devolve a mensagem à fila principal com o contador de tentativas zerado.
+func (rbm *rbm_pool) ReprocessarEstacionada(ctx context.Context, queue_name string, ID string) error {
+	return rbm.acaoEstacionada(queue_name, ID, func(ch *amqp.Channel, msg *amqp.Delivery) error {
+		headers := amqp.Table{}
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		headers[HeaderTentativas] = int64(0)
+		delete(headers, HeaderEstacionadaEm)
+
+		if err := rbm.republicar(ctx, ch, queue_name, msg, headers); err != nil {
+			return err
+		}
+		return msg.Ack(false)
+	})
+}
+
+// DescartarEstacionada remove a mensagem do parking definitivamente.
+func (rbm *rbm_pool) DescartarEstacionada(ctx context.Context, queue_name string, ID string) error {
+	return rbm.acaoEstacionada(queue_name, ID, func(ch *amqp.Channel, msg *amqp.Delivery) error {
+		return msg.Ack(false)
+	})
+}
+
+func (rbm *rbm_pool) acaoEstacionada(queue_name string, ID string, acao func(ch *amqp.Channel, msg *amqp.Delivery) error) error {
+	encontrada := false
+
+	err := rbm.percorrerEstacionadas(queue_name, func(ch *amqp.Channel, msg *amqp.Delivery) (bool, error) {
+		if msg.MessageId != ID {
+			return false, nil
+		}
+		encontrada = true
+		return true, acao(ch, msg)
+	})
+	if err != nil {
+		return err
+	}
+	if !encontrada {
+		return ErrMensagemNaoEncontrada
+	}
+	return nil
+}
+
+// percorrerEstacionadas lê o parking num canal próprio, uma mensagem por vez,
+// até visita pedir para parar ou a fila acabar. As mensagens que visita não
+// confirmou voltam para a fila quando o canal é fechado.
+func (rbm *rbm_pool) percorrerEstacionadas(queue_name string, visita func(ch *amqp.Channel, msg *amqp.Delivery) (bool, error)) error {
+	if rbm.conn == nil || rbm.conn.IsClosed() {
+		return errors.New("conexão com o RabbitMQ fechada")
+	}
+
+	ch, err := rbm.conn.Channel()
+	if err != nil {
+		logger.Error("Erro to Connect in RabbitMQ Channel", err)
+		return err
+	}
+	defer ch.Close()
+
+	fila, err := ch.QueueDeclarePassive(NomeEstacionamento(queue_name), true, false, false, false, nil)
+	if err != nil {
+		logger.Error("Erro to inspect parking queue in RabbitMQ", err)
+		return err
+	}
+
+	for i := 0; i < fila.Messages; i++ {
+		msg, ok, err := ch.Get(fila.Name, false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		parar, err := visita(ch, &msg)
+		if err != nil || parar {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rbm *rbm_pool) republicar(ctx context.Context, ch *amqp.Channel, destino string, msg *amqp.Delivery, headers amqp.Table) error {
+	return ch.PublishWithContext(ctx,
+		"",      // exchange padrão
+		destino, // routing key
+		false,   // mandatory
+		false,   // immediate
+		amqp.Publishing{
+			Headers:      headers,
+			Body:         msg.Body,
+			ContentType:  msg.ContentType,
+			MessageId:    msg.MessageId,
+			DeliveryMode: amqp.Persistent,
+		})
+}
+
+func estacionada(msg *amqp.Delivery) MensagemEstacionada {
+	result := MensagemEstacionada{
+		ID:          msg.MessageId,
+		Tentativas:  contarTentativas(msg.Headers),
+		ContentType: msg.ContentType,
+		Corpo:       string(msg.Body),
+	}
+	result.FilaOrigem, _ = msg.Headers[HeaderFilaOrigem].(string)
+	result.Motivo, _ = msg.Headers[HeaderMotivo].(string)
+	if data, ok := msg.Headers[HeaderEstacionadaEm].(string); ok {
+		result.EstacionadaEm, _ = time.Parse(time.RFC3339, data)
+	}
+
+	// Mensagens rejeitadas caem no parking pelo DLX, sem os headers da política.
+	if result.Motivo == "" {
+		if mortes, ok := msg.Headers["x-death"].([]interface{}); ok && len(mortes) > 0 {
+			if morte, ok := mortes[0].(amqp.Table); ok {
+				result.Motivo, _ = morte["reason"].(string)
+				result.FilaOrigem, _ = morte["queue"].(string)
+				result.EstacionadaEm, _ = morte["time"].(time.Time)
+			}
+		}
+	}
+
+	return result
+}
+
+func contarTentativas(headers amqp.Table) int64 {
+	switch v := headers[HeaderTentativas].(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	}
+	return 0
+}
@@ -13,6 +13,14 @@ import (
 type RedisClientInterface interface {
 	ReadData(ctx context.Context, key string) (data []byte, err error)
 	SaveData(ctx context.Context, key string, data []byte, timer time.Duration) (ok bool)
+	DeleteData(ctx context.Context, key string) error
+	// Eval executa um script Lua de forma atômica no servidor.
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+	// Publish entrega a mensagem aos assinantes do canal em todas as réplicas.
+	Publish(ctx context.Context, channel string, data []byte) error
+	// Subscribe assina o canal até ctx terminar; o canal devolvido é fechado
+	// quando a assinatura acaba.
+	Subscribe(ctx context.Context, channel string) (msgs <-chan []byte, err error)
 }
 
 type redis_client struct {
@@ -72,3 +80,50 @@ func (rs *redis_client) SaveData(ctx context.Context, key string, data []byte, t
 
 	return
 }
+
+func (rs *redis_client) DeleteData(ctx context.Context, key string) error {
+	return rs.rdb.Del(ctx, key).Err()
+}
+
+func (rs *redis_client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return redis.NewScript(script).Run(ctx, rs.rdb, keys, args...).Result()
+}
+
+func (rs *redis_client) Publish(ctx context.Context, channel string, data []byte) error {
+	return rs.rdb.Publish(ctx, channel, data).Err()
+}
+
+func (rs *redis_client) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	sub := rs.rdb.Subscribe(ctx, channel)
+
+	// Aguarda a confirmação para não perder mensagens publicadas logo em seguida.
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	msgs := make(chan []byte)
+	go func() {
+		defer close(msgs)
+		defer sub.Close()
+
+		entrada := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-entrada:
+				if !ok {
+					return
+				}
+				select {
+				case msgs <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return msgs, nil
+}
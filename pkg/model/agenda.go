@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/service/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Agenda é a definição de um sorteio recorrente, por exemplo "toda quarta e
+// sábado às 20:00 America/Sao_Paulo, vendas fecham 1h antes". O agendador
+// cria os próximos sorteios a partir dela e abre/fecha as vendas.
+// FechamentoAntes e AberturaAntes são minutos antes do sorteio; sem
+// AberturaAntes as vendas abrem no horário do sorteio anterior da agenda.
+// MarcadorDataFonte é trocado pela data do sorteio na fonte da entropia da agenda.
+const MarcadorDataFonte = "{data}"
+
+type Agenda struct {
+	ID               primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType         string             `bson:"data_type" json:"-"`
+	Nome             string             `bson:"nome" json:"nome"`
+	Jogo             string             `bson:"jogo" json:"jogo"`
+	DiasSemana       []time.Weekday     `bson:"dias_semana" json:"dias_semana"`
+	Horario          string             `bson:"horario" json:"horario"`
+	Fuso             string             `bson:"fuso" json:"fuso"`
+	FechamentoAntes  int                `bson:"fechamento_antes" json:"fechamento_antes"`
+	AberturaAntes    int                `bson:"abertura_antes" json:"abertura_antes"`
+	DiasAntecedencia int                `bson:"dias_antecedencia" json:"dias_antecedencia"`
+	ValorAposta      int64              `bson:"valor_aposta" json:"valor_aposta"`
+	PercentualPremio int64              `bson:"percentual_premio" json:"percentual_premio"`
+	TabelaPremios    []FaixaPremio      `bson:"tabela_premios" json:"tabela_premios"`
+	TetoAcumulado    int64              `bson:"teto_acumulado" json:"teto_acumulado,omitempty"`
+	// Fonte pública da entropia dos sorteios criados; {data} vira a data do
+	// sorteio, por exemplo "Mega-Sena oficial de {data}".
+	FonteEntropia string `bson:"fonte_entropia" json:"fonte_entropia"`
+	Enabled       bool   `bson:"enabled" json:"enabled"`
+	CreatedAt     string `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt     string `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+type FilterAgenda struct {
+	Nome    string `json:"nome"`
+	Jogo    string `json:"jogo"`
+	Enabled string `json:"enabled"`
+}
+
+func (a Agenda) AgendaConvet() string {
+	data, err := json.Marshal(a)
+
+	if err != nil {
+		logger.Error("error to convert Agenda to JSON", err)
+
+		return ""
+	}
+
+	return string(data)
+}
+
+func NewAgenda(agenda_request Agenda) *Agenda {
+	dt := time.Now().Format(time.RFC3339)
+
+	fuso := agenda_request.Fuso
+	if fuso == "" {
+		fuso = "America/Sao_Paulo"
+	}
+
+	antecedencia := agenda_request.DiasAntecedencia
+	if antecedencia <= 0 {
+		antecedencia = 7
+	}
+
+	return &Agenda{
+		ID:               primitive.NewObjectID(),
+		DataType:         "agenda",
+		Nome:             validation.CareString(agenda_request.Nome),
+		Jogo:             agenda_request.Jogo,
+		DiasSemana:       agenda_request.DiasSemana,
+		Horario:          agenda_request.Horario,
+		Fuso:             fuso,
+		FechamentoAntes:  agenda_request.FechamentoAntes,
+		AberturaAntes:    agenda_request.AberturaAntes,
+		DiasAntecedencia: antecedencia,
+		ValorAposta:      agenda_request.ValorAposta,
+		PercentualPremio: agenda_request.PercentualPremio,
+		TabelaPremios:    agenda_request.TabelaPremios,
+		TetoAcumulado:    agenda_request.TetoAcumulado,
+		FonteEntropia:    strings.TrimSpace(agenda_request.FonteEntropia),
+		Enabled:          true,
+		CreatedAt:        dt,
+		UpdatedAt:        dt,
+	}
+}
+
+// FonteDoSorteio monta a fonte da entropia do sorteio da agenda na data informada.
+func (a Agenda) FonteDoSorteio(dataSorteio time.Time) string {
+	return strings.ReplaceAll(a.FonteEntropia, MarcadorDataFonte, dataSorteio.Format("2006-01-02"))
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de evento do painel ao vivo de um sorteio.
+const (
+	// EventoEstado é o retrato completo enviado quando o cliente conecta.
+	EventoEstado = "estado"
+	// EventoVendas traz os totais depois de cada aposta aceita.
+	EventoVendas = "vendas"
+	// EventoStatus acompanha cada transição de status do sorteio.
+	EventoStatus = "status"
+	// EventoNumero revela um número do resultado durante o sorteio.
+	EventoNumero = "numero"
+)
+
+// EventoAoVivo é a mensagem publicada no canal do sorteio e repassada aos
+// clientes do painel. Revelados traz os números do resultado já mostrados.
+type EventoAoVivo struct {
+	Tipo            string             `json:"tipo"`
+	SorteioID       primitive.ObjectID `json:"sorteio_id"`
+	Status          string             `json:"status"`
+	QtdApostas      int64              `json:"qtd_apostas"`
+	TotalArrecadado int64              `json:"total_arrecadado"`
+	PremioEstimado  int64              `json:"premio_estimado"`
+	Numero          *int               `json:"numero,omitempty"`
+	Posicao         int                `json:"posicao,omitempty"`
+	Revelados       []int              `json:"revelados,omitempty"`
+	Data            string             `json:"data"`
+}
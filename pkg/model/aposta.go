@@ -0,0 +1,161 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estados de uma aposta.
+const (
+	ApostaPendente  = "pendente"
+	ApostaAceita    = "aceita"
+	ApostaRejeitada = "rejeitada"
+)
+
+type Aposta struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType  string             `bson:"data_type" json:"-"`
+	SorteioID primitive.ObjectID `bson:"sorteio_id" json:"sorteio_id"`
+	ClienteID primitive.ObjectID `bson:"cliente_id" json:"cliente_id"`
+	Numeros   []int              `bson:"numeros" json:"numeros"`
+	Valor     int64              `bson:"valor" json:"valor"`
+	Status    string             `bson:"status" json:"status"`
+	Motivo    string             `bson:"motivo,omitempty" json:"motivo,omitempty"`
+	Acertos   int                `bson:"acertos" json:"acertos"`
+	Premio    int64              `bson:"premio" json:"premio"`
+	// Erros detalha as regras violadas quando a aposta é rejeitada na fila.
+	Erros []ErroCampo `bson:"erros,omitempty" json:"erros,omitempty"`
+	// Codigo de verificação do comprovante, gravado quando a aposta é aceita.
+	Codigo string `bson:"codigo,omitempty" json:"codigo,omitempty"`
+	// BolaoID marca as apostas feitas pelo organizador em nome de um bolão.
+	BolaoID primitive.ObjectID `bson:"bolao_id,omitempty" json:"bolao_id,omitempty"`
+	// Aposta desdobrada: Linhas são as combinações cobertas pelos Numeros,
+	// todas ou só as da Garantia pedida, e o Valor é uma aposta por linha.
+	// Na apuração AcertosLinhas conta as linhas por quantidade de acertos.
+	Garantia      int             `bson:"garantia,omitempty" json:"garantia,omitempty"`
+	Linhas        [][]int         `bson:"linhas,omitempty" json:"linhas,omitempty"`
+	AcertosLinhas []AcertosLinhas `bson:"acertos_linhas,omitempty" json:"acertos_linhas,omitempty"`
+	// MerkleIndice é a posição da aposta na árvore comprometida no fechamento.
+	MerkleIndice *int64 `bson:"merkle_indice,omitempty" json:"merkle_indice,omitempty"`
+	CreatedAt    string `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt    string `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+type AcertosLinhas struct {
+	Acertos int   `bson:"acertos" json:"acertos"`
+	Linhas  int64 `bson:"linhas" json:"linhas"`
+}
+
+// ProvaInclusao permite ao apostador conferir que a aposta está no conjunto
+// comprometido no fechamento do sorteio.
+type ProvaInclusao struct {
+	SorteioID primitive.ObjectID `json:"sorteio_id"`
+	ApostaID  primitive.ObjectID `json:"aposta_id"`
+	Indice    int64              `json:"indice"`
+	Dados     string             `json:"dados"`
+	Folha     string             `json:"folha"`
+	Caminho   []PassoProva       `json:"caminho"`
+	Raiz      string             `json:"raiz"`
+	Valida    bool               `json:"valida"`
+}
+
+// PassoProva é um irmão no caminho da folha até a raiz e o lado ("esquerda"
+// ou "direita") em que ele fica.
+type PassoProva struct {
+	Hash string `json:"hash"`
+	Lado string `json:"lado"`
+}
+
+// Canonica é a serialização da aposta usada como folha da árvore de Merkle:
+// "v1|aposta|sorteio|cliente|numeros em ordem crescente separados por vírgula|valor".
+// Apostas desdobradas acrescentam "|d<garantia>", que junto com os números
+// determina as linhas.
+func (a Aposta) Canonica() []byte {
+	numeros := append([]int(nil), a.Numeros...)
+	sort.Ints(numeros)
+
+	partes := make([]string, len(numeros))
+	for i, n := range numeros {
+		partes[i] = strconv.Itoa(n)
+	}
+
+	campos := []string{
+		"v1",
+		a.ID.Hex(),
+		a.SorteioID.Hex(),
+		a.ClienteID.Hex(),
+		strings.Join(partes, ","),
+		strconv.FormatInt(a.Valor, 10),
+	}
+	if len(a.Linhas) > 0 {
+		campos = append(campos, "d"+strconv.Itoa(a.Garantia))
+	}
+
+	return []byte(strings.Join(campos, "|"))
+}
+
+func (a Aposta) ApostaConvet() string {
+	data, err := json.Marshal(a)
+
+	if err != nil {
+		logger.Error("error to convert Aposta to JSON", err)
+
+		return ""
+	}
+
+	return string(data)
+}
+
+func NewAposta(aposta_request Aposta) *Aposta {
+	dt := time.Now().Format(time.RFC3339)
+	return &Aposta{
+		ID:        primitive.NewObjectID(),
+		DataType:  "aposta",
+		SorteioID: aposta_request.SorteioID,
+		ClienteID: aposta_request.ClienteID,
+		BolaoID:   aposta_request.BolaoID,
+		Numeros:   aposta_request.Numeros,
+		Garantia:  aposta_request.Garantia,
+		Valor:     aposta_request.Valor,
+		Status:    ApostaPendente,
+		CreatedAt: dt,
+		UpdatedAt: dt,
+	}
+}
+
+// PedidoSurpresinha pede Quantidade apostas geradas ao acaso para o cliente.
+// Fixos entram em todas as apostas e Excluidos em nenhuma.
+type PedidoSurpresinha struct {
+	SorteioID  primitive.ObjectID `json:"sorteio_id"`
+	ClienteID  primitive.ObjectID `json:"cliente_id"`
+	Quantidade int                `json:"quantidade"`
+	Fixos      []int              `json:"fixos,omitempty"`
+	Excluidos  []int              `json:"excluidos,omitempty"`
+}
+
+// Comprovante é o recibo de uma aposta aceita. Codigo é digitado pelo
+// revendedor para conferir o bilhete; QR carrega os dados da aposta assinados.
+// Na verificação pública o QR não é devolvido e o status é o atual.
+type Comprovante struct {
+	ApostaID      primitive.ObjectID `json:"aposta_id"`
+	SorteioID     primitive.ObjectID `json:"sorteio_id"`
+	Sorteio       string             `json:"sorteio"`
+	Concurso      int64              `json:"concurso"`
+	Jogo          string             `json:"jogo"`
+	DataSorteio   time.Time          `json:"data_sorteio"`
+	Numeros       []int              `json:"numeros"`
+	Valor         int64              `json:"valor"`
+	Status        string             `json:"status"`
+	StatusSorteio string             `json:"status_sorteio"`
+	Acertos       int                `json:"acertos"`
+	Premio        int64              `json:"premio"`
+	Codigo        string             `json:"codigo"`
+	QR            string             `json:"qr,omitempty"`
+	CriadaEm      string             `json:"criada_em"`
+}
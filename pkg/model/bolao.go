@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/service/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estados de um bolão.
+const (
+	BolaoAberto    = "aberto"
+	BolaoLiquidado = "liquidado"
+	BolaoCancelado = "cancelado"
+)
+
+// Bolao é uma aposta coletiva: o organizador registra Jogos no sorteio em
+// seu nome e vende Cotas a ValorCota cada. O prêmio das apostas do bolão,
+// menos a TaxaOrganizador (em pontos-base de BaseCalculo), é dividido entre
+// as cotas; as cotas não vendidas ficam com o organizador.
+type Bolao struct {
+	ID              primitive.ObjectID   `bson:"_id" json:"_id"`
+	DataType        string               `bson:"data_type" json:"-"`
+	Nome            string               `bson:"nome" json:"nome"`
+	SorteioID       primitive.ObjectID   `bson:"sorteio_id" json:"sorteio_id"`
+	OrganizadorID   primitive.ObjectID   `bson:"organizador_id" json:"organizador_id"`
+	Jogos           [][]int              `bson:"jogos" json:"jogos"`
+	ApostaIDs       []primitive.ObjectID `bson:"aposta_ids" json:"aposta_ids,omitempty"`
+	Cotas           int64                `bson:"cotas" json:"cotas"`
+	CotasVendidas   int64                `bson:"cotas_vendidas" json:"cotas_vendidas"`
+	ValorCota       int64                `bson:"valor_cota" json:"valor_cota"`
+	TaxaOrganizador int64                `bson:"taxa_organizador" json:"taxa_organizador"`
+	Status          string               `bson:"status" json:"status"`
+	Motivo          string               `bson:"motivo,omitempty" json:"motivo,omitempty"`
+	// Preenchidos na liquidação, em centavos.
+	Premio            int64  `bson:"premio" json:"premio"`
+	Taxa              int64  `bson:"taxa" json:"taxa"`
+	PremioPorCota     int64  `bson:"premio_por_cota" json:"premio_por_cota"`
+	PremioOrganizador int64  `bson:"premio_organizador" json:"premio_organizador"`
+	CreatedAt         string `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt         string `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+type FilterBolao struct {
+	SorteioID     string `json:"sorteio_id"`
+	OrganizadorID string `json:"organizador_id"`
+	Status        string `json:"status"`
+}
+
+// CotaBolao é uma compra de cotas de um bolão. Premio é a parte do cliente
+// depois da liquidação, já com a taxa do organizador descontada.
+type CotaBolao struct {
+	ID         primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType   string             `bson:"data_type" json:"-"`
+	BolaoID    primitive.ObjectID `bson:"bolao_id" json:"bolao_id"`
+	ClienteID  primitive.ObjectID `bson:"cliente_id" json:"cliente_id"`
+	Quantidade int64              `bson:"quantidade" json:"quantidade"`
+	Valor      int64              `bson:"valor" json:"valor"`
+	Premio     int64              `bson:"premio" json:"premio"`
+	CreatedAt  string             `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt  string             `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+func (b Bolao) BolaoConvet() string {
+	data, err := json.Marshal(b)
+
+	if err != nil {
+		logger.Error("error to convert Bolao to JSON", err)
+
+		return ""
+	}
+
+	return string(data)
+}
+
+func NewBolao(bolao_request Bolao) *Bolao {
+	dt := time.Now().Format(time.RFC3339)
+	return &Bolao{
+		ID:              primitive.NewObjectID(),
+		DataType:        "bolao",
+		Nome:            validation.CareString(bolao_request.Nome),
+		SorteioID:       bolao_request.SorteioID,
+		OrganizadorID:   bolao_request.OrganizadorID,
+		Jogos:           bolao_request.Jogos,
+		Cotas:           bolao_request.Cotas,
+		TaxaOrganizador: bolao_request.TaxaOrganizador,
+		Status:          BolaoAberto,
+		CreatedAt:       dt,
+		UpdatedAt:       dt,
+	}
+}
+
+func NewCotaBolao(cota_request CotaBolao) *CotaBolao {
+	dt := time.Now().Format(time.RFC3339)
+	return &CotaBolao{
+		ID:         primitive.NewObjectID(),
+		DataType:   "cota_bolao",
+		BolaoID:    cota_request.BolaoID,
+		ClienteID:  cota_request.ClienteID,
+		Quantidade: cota_request.Quantidade,
+		Valor:      cota_request.Valor,
+		CreatedAt:  dt,
+		UpdatedAt:  dt,
+	}
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de lançamento da carteira.
+const (
+	LancamentoDeposito = "deposito"
+	LancamentoAposta   = "aposta"
+	LancamentoCota     = "cota_bolao"
+	LancamentoPremio   = "premio"
+	LancamentoProvisao = "provisao_premio"
+	LancamentoSaque    = "saque"
+	LancamentoBloqueio = "saque_bloqueio"
+	LancamentoEstorno  = "estorno"
+)
+
+// Contas da casa. As contas dos clientes usam ContaCliente.
+const (
+	// ContaCompensacaoDeposito é a contrapartida do dinheiro que entra e sai
+	// pelos meios de pagamento.
+	ContaCompensacaoDeposito = "casa:compensacao_deposito"
+	// ContaPremiosAPagar é a obrigação com os prêmios ainda não creditados.
+	ContaPremiosAPagar = "casa:premios_a_pagar"
+	// ContaReceita recebe o valor das apostas e provisiona os prêmios.
+	ContaReceita = "casa:receita"
+	// ContaImpostoRetido guarda o imposto retido na fonte sobre os prêmios.
+	ContaImpostoRetido = "casa:imposto_retido"
+	// ContaSaquesPendentes segura o valor dos saques pedidos e ainda não pagos.
+	ContaSaquesPendentes = "casa:saques_pendentes"
+
+	prefixoContaCliente = "cliente:"
+)
+
+// Prefixos da referência dos depósitos, um por origem, para um depósito
+// manual nunca ocupar a referência de um Pix ainda não confirmado.
+const (
+	ReferenciaManual = "manual:"
+	ReferenciaPix    = "pix:"
+)
+
+func ContaCliente(clienteID primitive.ObjectID) string {
+	return prefixoContaCliente + clienteID.Hex()
+}
+
+// ContaDeCliente informa se a conta pertence a um cliente. Só essas contas
+// não podem ficar com saldo negativo.
+func ContaDeCliente(conta string) bool {
+	return strings.HasPrefix(conta, prefixoContaCliente)
+}
+
+// Partida é um lado do lançamento: Valor positivo credita a conta e negativo
+// debita, em centavos. As partidas de um lançamento sempre somam zero.
+type Partida struct {
+	Conta string `bson:"conta" json:"conta"`
+	Valor int64  `bson:"valor" json:"valor"`
+}
+
+// Lancamento é um registro imutável do livro-razão da carteira. Tipo e
+// Referencia identificam o movimento de origem e não se repetem, o que torna
+// lançar de novo o mesmo movimento inofensivo.
+type Lancamento struct {
+	ID         primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType   string             `bson:"data_type" json:"-"`
+	Tipo       string             `bson:"tipo" json:"tipo"`
+	Referencia string             `bson:"referencia" json:"referencia"`
+	SorteioID  primitive.ObjectID `bson:"sorteio_id,omitempty" json:"sorteio_id,omitempty"`
+	Descricao  string             `bson:"descricao,omitempty" json:"descricao,omitempty"`
+	Partidas   []Partida          `bson:"partidas" json:"partidas"`
+	CreatedAt  string             `bson:"created_at" json:"created_at,omitempty"`
+}
+
+// ContaCarteira guarda o saldo de uma conta, derivado dos lançamentos e
+// atualizado na mesma transação de cada um.
+type ContaCarteira struct {
+	ID               primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType         string             `bson:"data_type" json:"-"`
+	Conta            string             `bson:"conta" json:"conta"`
+	Saldo            int64              `bson:"saldo" json:"saldo"`
+	UltimoLancamento primitive.ObjectID `bson:"ultimo_lancamento" json:"ultimo_lancamento"`
+	CreatedAt        string             `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt        string             `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+// SaldoCarteira é o saldo de uma conta. Na recalculação, Anterior traz o
+// saldo que estava guardado antes de ser refeito pelos lançamentos.
+type SaldoCarteira struct {
+	Conta    string `json:"conta"`
+	Saldo    int64  `json:"saldo"`
+	Anterior *int64 `json:"anterior,omitempty"`
+}
+
+// MovimentoCarteira é uma linha do extrato: o lançamento visto pela conta.
+type MovimentoCarteira struct {
+	LancamentoID primitive.ObjectID `json:"lancamento_id"`
+	Tipo         string             `json:"tipo"`
+	Referencia   string             `json:"referencia"`
+	Descricao    string             `json:"descricao,omitempty"`
+	Valor        int64              `json:"valor"`
+	CreatedAt    string             `json:"created_at"`
+}
+
+// PedidoDeposito é o corpo de um depósito lançado manualmente. Taxa é a
+// tarifa do meio de pagamento, descontada do crédito e lançada como receita;
+// só os fluxos dos meios de pagamento a informam.
+type PedidoDeposito struct {
+	Valor      int64  `json:"valor"`
+	Taxa       int64  `json:"-"`
+	Referencia string `json:"referencia"`
+	Descricao  string `json:"descricao"`
+}
+
+func NewLancamento(lancamento_request Lancamento) *Lancamento {
+	return &Lancamento{
+		ID:         primitive.NewObjectID(),
+		DataType:   "lancamento",
+		Tipo:       lancamento_request.Tipo,
+		Referencia: lancamento_request.Referencia,
+		SorteioID:  lancamento_request.SorteioID,
+		Descricao:  lancamento_request.Descricao,
+		Partidas:   lancamento_request.Partidas,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+}
@@ -12,8 +12,8 @@ import (
 type Cliente struct {
 	ID          primitive.ObjectID `bson:"_id" json:"_id"`
 	DataType    string             `bson:"data_type" json:"-"`
-	TipoCliente string             `bson:"tipo" json:"tipo_cliente"`
-	IDUsuario   primitive.ObjectID `bson:"user_id " json:"id_usr"`
+	TipoCliente string             `bson:"tipo_cliente" json:"tipo_cliente"`
+	IDUsuario   primitive.ObjectID `bson:"user_id" json:"id_usr"`
 	Nome        string             `bson:"nome" json:"nome"`
 	Email       string             `bson:"email" json:"email"`
 	Sexo        string             `bson:"sexo" json:"sexo"`
@@ -39,7 +39,7 @@ func (c Cliente) ClienteConvet() string {
 
 type FilterCliente struct {
 	Nome      string             `json:"nome"`
-	IDUsuario primitive.ObjectID `bson:"user_id " json:"id_usr"`
+	IDUsuario primitive.ObjectID `bson:"user_id" json:"id_usr"`
 	Documento string             `bson:"documento" json:"documento"`
 	Enabled   string             `json:"enabled"`
 }
@@ -50,6 +50,11 @@ func NewCliente(cliente_request Cliente) *Cliente {
 		DataType:  "cliente",
 		IDUsuario: cliente_request.IDUsuario,
 		Nome:      validation.CareString(cliente_request.Nome),
+		Email:     cliente_request.Email,
+		Sexo:      cliente_request.Sexo,
+		Telefone:  cliente_request.Telefone,
+		Tipo:      cliente_request.Tipo,
+		Documento: validation.ExtractNumbers(cliente_request.Documento),
 		Enabled:   true,
 		CreatedAt: time.Now().String(),
 	}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Resultado de cada item da conciliação. Sem registro é a linha do extrato
+// que não corresponde a nada no sistema; ausente é o depósito ou saque do
+// sistema que não aparece no extrato.
+const (
+	ItemConciliado  = "conciliado"
+	ItemDivergente  = "divergente"
+	ItemDuplicado   = "duplicado"
+	ItemSemRegistro = "sem_registro"
+	ItemAusente     = "ausente"
+)
+
+// Origem do registro conciliado.
+const (
+	ConciliacaoDeposito = "deposito"
+	ConciliacaoSaque    = "saque"
+)
+
+// Conciliacao é uma execução da conciliação de um extrato de liquidação do
+// PSP com os depósitos e saques Pix do sistema, guardada para auditoria.
+// Inicio e Fim são o período coberto pelo extrato.
+type Conciliacao struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType  string             `bson:"data_type" json:"-"`
+	Arquivo   string             `bson:"arquivo" json:"arquivo"`
+	Formato   string             `bson:"formato" json:"formato"`
+	Usuario   string             `bson:"usuario" json:"usuario"`
+	Inicio    time.Time          `bson:"inicio" json:"inicio"`
+	Fim       time.Time          `bson:"fim" json:"fim"`
+	Linhas    int                `bson:"linhas" json:"linhas"`
+	Resumo    ResumoConciliacao  `bson:"resumo" json:"resumo"`
+	Itens     []ItemConciliacao  `bson:"itens,omitempty" json:"itens,omitempty"`
+	CreatedAt string             `bson:"created_at" json:"created_at,omitempty"`
+}
+
+// ResumoConciliacao conta os itens por resultado. Valores em centavos.
+type ResumoConciliacao struct {
+	Conciliados  int   `bson:"conciliados" json:"conciliados"`
+	Divergentes  int   `bson:"divergentes" json:"divergentes"`
+	Duplicados   int   `bson:"duplicados" json:"duplicados"`
+	SemRegistro  int   `bson:"sem_registro" json:"sem_registro"`
+	Ausentes     int   `bson:"ausentes" json:"ausentes"`
+	ValorExtrato int64 `bson:"valor_extrato" json:"valor_extrato"`
+	ValorSistema int64 `bson:"valor_sistema" json:"valor_sistema"`
+}
+
+// ItemConciliacao liga uma linha do extrato (Linha, a partir de 1) ao
+// registro do sistema. Linha zero indica item ausente do extrato; Registro
+// vazio, linha sem registro.
+type ItemConciliacao struct {
+	Status       string    `bson:"status" json:"status"`
+	Tipo         string    `bson:"tipo,omitempty" json:"tipo,omitempty"`
+	Linha        int       `bson:"linha,omitempty" json:"linha,omitempty"`
+	EndToEndID   string    `bson:"end_to_end_id,omitempty" json:"end_to_end_id,omitempty"`
+	TxID         string    `bson:"txid,omitempty" json:"txid,omitempty"`
+	Registro     string    `bson:"registro,omitempty" json:"registro,omitempty"`
+	ValorExtrato int64     `bson:"valor_extrato" json:"valor_extrato"`
+	ValorSistema int64     `bson:"valor_sistema" json:"valor_sistema"`
+	Data         time.Time `bson:"data" json:"data"`
+}
+
+type FilterConciliacao struct {
+	Usuario string `json:"usuario"`
+}
+
+// Contar soma o item no resumo.
+func (r *ResumoConciliacao) Contar(item ItemConciliacao) {
+	switch item.Status {
+	case ItemConciliado:
+		r.Conciliados++
+	case ItemDivergente:
+		r.Divergentes++
+	case ItemDuplicado:
+		r.Duplicados++
+	case ItemSemRegistro:
+		r.SemRegistro++
+	case ItemAusente:
+		r.Ausentes++
+	}
+	r.ValorExtrato += item.ValorExtrato
+	r.ValorSistema += item.ValorSistema
+}
+
+func NewConciliacao(conciliacao_request Conciliacao) *Conciliacao {
+	resumo := ResumoConciliacao{}
+	for _, item := range conciliacao_request.Itens {
+		resumo.Contar(item)
+	}
+
+	return &Conciliacao{
+		ID:        primitive.NewObjectID(),
+		DataType:  "conciliacao",
+		Arquivo:   conciliacao_request.Arquivo,
+		Formato:   conciliacao_request.Formato,
+		Usuario:   conciliacao_request.Usuario,
+		Inicio:    conciliacao_request.Inicio,
+		Fim:       conciliacao_request.Fim,
+		Linhas:    conciliacao_request.Linhas,
+		Resumo:    resumo,
+		Itens:     conciliacao_request.Itens,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+}
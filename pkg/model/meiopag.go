@@ -0,0 +1,102 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Operações financeiras que um meio de pagamento pode atender.
+const (
+	OperacaoDeposito = "deposito"
+	OperacaoSaque    = "saque"
+)
+
+// MeioPagPix é o nome do meio de pagamento consultado pelos fluxos de Pix.
+const MeioPagPix = "Pix"
+
+// MeioPagamento é um item do catálogo de meios de pagamento. Valores em
+// centavos; ValorMaximo zero não limita. Taxa em pontos-base de BaseCalculo,
+// cobrada sobre o valor da operação.
+type MeioPagamento struct {
+	ID          primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType    string             `bson:"data_type" json:"-"`
+	MeioPg      string             `bson:"meio_pg" json:"meio_pg"`
+	ValorMinimo int64              `bson:"valor_minimo" json:"valor_minimo"`
+	ValorMaximo int64              `bson:"valor_maximo" json:"valor_maximo"`
+	Taxa        int64              `bson:"taxa" json:"taxa"`
+	Operacoes   []string           `bson:"operacoes" json:"operacoes"`
+	Enabled     bool               `bson:"enabled" json:"enabled"`
+	CreatedAt   string             `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt   string             `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+// RegrasMeioPagamento é o corpo opcional do cadastro e da alteração; campos
+// ausentes mantêm o valor atual (ou o padrão, no cadastro).
+type RegrasMeioPagamento struct {
+	ValorMinimo *int64   `json:"valor_minimo"`
+	ValorMaximo *int64   `json:"valor_maximo"`
+	Taxa        *int64   `json:"taxa"`
+	Operacoes   []string `json:"operacoes"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+type FilterMeioPagamento struct {
+	MeioPg   string `json:"meio_pg"`
+	Operacao string `json:"operacao"`
+	Enabled  string `json:"enabled"`
+}
+
+// Permite informa se o meio está ativo e atende a operação.
+func (m *MeioPagamento) Permite(operacao string) bool {
+	return m.Enabled && slices.Contains(m.Operacoes, operacao)
+}
+
+// NoLimite informa se o valor está entre o mínimo e o máximo do meio.
+func (m *MeioPagamento) NoLimite(valor int64) bool {
+	return valor >= m.ValorMinimo && (m.ValorMaximo == 0 || valor <= m.ValorMaximo)
+}
+
+// Tarifa devolve a taxa em centavos sobre o valor, arredondada para baixo.
+func (m *MeioPagamento) Tarifa(valor int64) int64 {
+	return valor * m.Taxa / BaseCalculo
+}
+
+// Aplicar copia para o meio as regras informadas.
+func (m *MeioPagamento) Aplicar(regras RegrasMeioPagamento) {
+	if regras.ValorMinimo != nil {
+		m.ValorMinimo = *regras.ValorMinimo
+	}
+	if regras.ValorMaximo != nil {
+		m.ValorMaximo = *regras.ValorMaximo
+	}
+	if regras.Taxa != nil {
+		m.Taxa = *regras.Taxa
+	}
+	if regras.Operacoes != nil {
+		m.Operacoes = regras.Operacoes
+	}
+	if regras.Enabled != nil {
+		m.Enabled = *regras.Enabled
+	}
+}
+
+// NewMeioPagamento cria o meio ativo e só para depósito, sem limites nem
+// taxa, e então aplica as regras do pedido.
+func NewMeioPagamento(nome string, regras RegrasMeioPagamento) *MeioPagamento {
+	dt := time.Now().Format(time.RFC3339)
+	meio := &MeioPagamento{
+		ID:        primitive.NewObjectID(),
+		DataType:  "meio_pagamento",
+		MeioPg:    strings.TrimSpace(nome),
+		Operacoes: []string{OperacaoDeposito},
+		Enabled:   true,
+		CreatedAt: dt,
+		UpdatedAt: dt,
+	}
+	meio.Aplicar(regras)
+
+	return meio
+}
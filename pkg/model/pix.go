@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos e estados de uma cobrança Pix.
+const (
+	PixEstatico = "estatico"
+	PixDinamico = "dinamico"
+
+	CobrancaAtiva     = "ativa"
+	CobrancaConcluida = "concluida"
+	CobrancaExpirada  = "expirada"
+)
+
+// CobrancaPix é um depósito por Pix identificado pelo TxID. O QR estático pode
+// ser pago mais de uma vez e segue ativo; o dinâmico é concluído no primeiro
+// pagamento. Cada pagamento confirmado pelo webhook vira um depósito na
+// carteira do cliente, referenciado pelo EndToEndID.
+type CobrancaPix struct {
+	ID         primitive.ObjectID  `bson:"_id" json:"_id"`
+	DataType   string              `bson:"data_type" json:"-"`
+	TxID       string              `bson:"txid" json:"txid"`
+	ClienteID  primitive.ObjectID  `bson:"cliente_id" json:"cliente_id"`
+	Tipo       string              `bson:"tipo" json:"tipo"`
+	Valor      int64               `bson:"valor" json:"valor"`
+	Payload    string              `bson:"payload" json:"payload"`
+	Location   string              `bson:"location,omitempty" json:"location,omitempty"`
+	Status     string              `bson:"status" json:"status"`
+	ValorPago  int64               `bson:"valor_pago" json:"valor_pago"`
+	Pagamentos []PagamentoCobranca `bson:"pagamentos" json:"pagamentos,omitempty"`
+	ExpiraEm   time.Time           `bson:"expira_em,omitempty" json:"expira_em,omitempty"`
+	CreatedAt  string              `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt  string              `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+type PagamentoCobranca struct {
+	EndToEndID   string             `bson:"end_to_end_id" json:"end_to_end_id"`
+	Valor        int64              `bson:"valor" json:"valor"`
+	Taxa         int64              `bson:"taxa" json:"taxa"`
+	Horario      time.Time          `bson:"horario" json:"horario"`
+	LancamentoID primitive.ObjectID `bson:"lancamento_id" json:"lancamento_id"`
+}
+
+// PedidoCobrancaPix é o corpo do pedido de depósito por Pix. Valor em
+// centavos; no QR estático pode ser zero para o pagador escolher.
+type PedidoCobrancaPix struct {
+	ClienteID primitive.ObjectID `json:"cliente_id"`
+	Tipo      string             `json:"tipo"`
+	Valor     int64              `json:"valor"`
+}
+
+// Vencida informa se a cobrança dinâmica passou da validade sem pagamento.
+func (c *CobrancaPix) Vencida(agora time.Time) bool {
+	return c.Status == CobrancaAtiva && !c.ExpiraEm.IsZero() && agora.After(c.ExpiraEm)
+}
+
+func NewCobrancaPix(cobranca_request CobrancaPix) *CobrancaPix {
+	dt := time.Now().Format(time.RFC3339)
+	return &CobrancaPix{
+		ID:         primitive.NewObjectID(),
+		DataType:   "cobranca_pix",
+		TxID:       cobranca_request.TxID,
+		ClienteID:  cobranca_request.ClienteID,
+		Tipo:       cobranca_request.Tipo,
+		Valor:      cobranca_request.Valor,
+		Payload:    cobranca_request.Payload,
+		Location:   cobranca_request.Location,
+		Status:     CobrancaAtiva,
+		Pagamentos: make([]PagamentoCobranca, 0),
+		ExpiraEm:   cobranca_request.ExpiraEm,
+		CreatedAt:  dt,
+		UpdatedAt:  dt,
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Origens de um prêmio: a aposta do próprio cliente, a cota de um bolão ou a
+// parte do organizador do bolão (taxa mais as cotas não vendidas).
+const (
+	OrigemAposta      = "aposta"
+	OrigemCota        = "cota_bolao"
+	OrigemOrganizador = "bolao_organizador"
+)
+
+// Estados do pagamento de um prêmio.
+const (
+	PremioPendente          = "pendente"
+	PremioAguardandoResgate = "aguardando_resgate"
+	PremioCreditado         = "creditado"
+)
+
+// PagamentoPremio é o prêmio de um cliente num sorteio liquidado, com o
+// imposto de renda retido na fonte. Valores em centavos: Liquido é Bruto
+// menos Imposto. Origem e Referencia (id da aposta, da cota ou do bolão)
+// identificam o prêmio e não se repetem.
+type PagamentoPremio struct {
+	ID           primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType     string             `bson:"data_type" json:"-"`
+	SorteioID    primitive.ObjectID `bson:"sorteio_id" json:"sorteio_id"`
+	ClienteID    primitive.ObjectID `bson:"cliente_id" json:"cliente_id"`
+	Origem       string             `bson:"origem" json:"origem"`
+	Referencia   string             `bson:"referencia" json:"referencia"`
+	Bruto        int64              `bson:"bruto" json:"bruto"`
+	Aliquota     int64              `bson:"aliquota" json:"aliquota"`
+	Imposto      int64              `bson:"imposto" json:"imposto"`
+	Liquido      int64              `bson:"liquido" json:"liquido"`
+	Status       string             `bson:"status" json:"status"`
+	LancamentoID primitive.ObjectID `bson:"lancamento_id,omitempty" json:"lancamento_id,omitempty"`
+	ResgatadoPor string             `bson:"resgatado_por,omitempty" json:"resgatado_por,omitempty"`
+	CreatedAt    string             `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt    string             `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+type FilterPagamentoPremio struct {
+	SorteioID primitive.ObjectID `json:"sorteio_id"`
+	ClienteID primitive.ObjectID `json:"cliente_id"`
+	Status    string             `json:"status"`
+}
+
+// ChaveLancamento é a referência dos lançamentos do prêmio na carteira.
+func (p *PagamentoPremio) ChaveLancamento() string {
+	return p.Origem + ":" + p.Referencia
+}
+
+func NewPagamentoPremio(premio_request PagamentoPremio) *PagamentoPremio {
+	dt := time.Now().Format(time.RFC3339)
+	return &PagamentoPremio{
+		ID:         primitive.NewObjectID(),
+		DataType:   "pagamento_premio",
+		SorteioID:  premio_request.SorteioID,
+		ClienteID:  premio_request.ClienteID,
+		Origem:     premio_request.Origem,
+		Referencia: premio_request.Referencia,
+		Bruto:      premio_request.Bruto,
+		Aliquota:   premio_request.Aliquota,
+		Imposto:    premio_request.Imposto,
+		Liquido:    premio_request.Bruto - premio_request.Imposto,
+		Status:     premio_request.Status,
+		CreatedAt:  dt,
+		UpdatedAt:  dt,
+	}
+}
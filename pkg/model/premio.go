@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de faixa da tabela de prêmios.
+const (
+	FaixaFixa       = "fixo"
+	FaixaPercentual = "percentual"
+)
+
+// BaseCalculo é o denominador dos percentuais, expressos em pontos-base (10000 = 100%).
+const BaseCalculo = 10000
+
+// FaixaPremio é uma linha da tabela de prêmios de um sorteio. Faixas fixas
+// pagam ValorFixo centavos a cada ganhador; faixas percentuais recebem
+// Percentual pontos-base do prêmio do sorteio, dividido igualmente entre os ganhadores.
+type FaixaPremio struct {
+	Acertos    int    `bson:"acertos" json:"acertos"`
+	Tipo       string `bson:"tipo" json:"tipo"`
+	ValorFixo  int64  `bson:"valor_fixo" json:"valor_fixo,omitempty"`
+	Percentual int64  `bson:"percentual" json:"percentual,omitempty"`
+}
+
+// RateioFaixa é o resultado da apuração de uma faixa. Todos os valores em centavos.
+type RateioFaixa struct {
+	Acertos           int    `bson:"acertos" json:"acertos"`
+	Tipo              string `bson:"tipo" json:"tipo"`
+	Ganhadores        int64  `bson:"ganhadores" json:"ganhadores"`
+	ValorFaixa        int64  `bson:"valor_faixa" json:"valor_faixa"`
+	PremioPorGanhador int64  `bson:"premio_por_ganhador" json:"premio_por_ganhador"`
+	Residuo           int64  `bson:"residuo" json:"residuo"`
+}
+
+// RateioSorteio é o detalhamento do rateio exposto pela API.
+type RateioSorteio struct {
+	SorteioID       primitive.ObjectID `json:"sorteio_id"`
+	Status          string             `json:"status"`
+	QtdApostas      int64              `json:"qtd_apostas"`
+	TotalArrecadado int64              `json:"total_arrecadado"`
+	ValorPremio     int64              `json:"valor_premio"`
+	Acumulado       int64              `json:"acumulado"`
+	Faixas          []RateioFaixa      `json:"faixas"`
+	Residuo         int64              `json:"residuo"`
+	Acumular        int64              `json:"acumular"`
+}
+
+// EstimativaPremio é a estimativa pública da faixa principal do próximo sorteio de um jogo.
+type EstimativaPremio struct {
+	SorteioID   primitive.ObjectID `json:"sorteio_id"`
+	Jogo        string             `json:"jogo"`
+	Status      string             `json:"status"`
+	DataSorteio time.Time          `json:"data_sorteio"`
+	Acumulado   int64              `json:"acumulado"`
+	Estimativa  int64              `json:"estimativa"`
+}
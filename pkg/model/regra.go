@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RegraJogo guarda os limites de venda configurados para um jogo, um
+// documento por jogo. CorteMinutos antecipa o fim das vendas em relação à
+// data de fechamento do sorteio. Os limites por cliente valem para cada
+// sorteio e contam as apostas pendentes e aceitas; zero significa sem limite.
+// MaxNumeros limita os números de uma aposta desdobrada; zero usa o padrão e
+// o tamanho da aposta simples desliga o desdobramento.
+type RegraJogo struct {
+	ID                 primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType           string             `bson:"data_type" json:"-"`
+	Jogo               string             `bson:"jogo" json:"jogo"`
+	CorteMinutos       int                `bson:"corte_minutos" json:"corte_minutos"`
+	MaxNumeros         int                `bson:"max_numeros" json:"max_numeros"`
+	MaxBilhetesCliente int64              `bson:"max_bilhetes_cliente" json:"max_bilhetes_cliente"`
+	MaxValorCliente    int64              `bson:"max_valor_cliente" json:"max_valor_cliente"`
+	Enabled            bool               `bson:"enabled" json:"enabled"`
+	CreatedAt          string             `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt          string             `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+type FilterRegraJogo struct {
+	Jogo    string `json:"jogo"`
+	Enabled string `json:"enabled"`
+}
+
+// ErroCampo descreve uma regra violada pela aposta. Campo aponta o dado da
+// aposta (por exemplo "numeros[2]") e Codigo é estável para o front-end.
+type ErroCampo struct {
+	Campo    string `bson:"campo" json:"campo"`
+	Codigo   string `bson:"codigo" json:"codigo"`
+	Mensagem string `bson:"mensagem" json:"mensagem"`
+}
+
+func (r RegraJogo) RegraJogoConvet() string {
+	data, err := json.Marshal(r)
+
+	if err != nil {
+		logger.Error("error to convert RegraJogo to JSON", err)
+
+		return ""
+	}
+
+	return string(data)
+}
+
+func NewRegraJogo(regra_request RegraJogo) *RegraJogo {
+	dt := time.Now().Format(time.RFC3339)
+	return &RegraJogo{
+		ID:                 primitive.NewObjectID(),
+		DataType:           "regra_jogo",
+		Jogo:               regra_request.Jogo,
+		CorteMinutos:       regra_request.CorteMinutos,
+		MaxNumeros:         regra_request.MaxNumeros,
+		MaxBilhetesCliente: regra_request.MaxBilhetesCliente,
+		MaxValorCliente:    regra_request.MaxValorCliente,
+		Enabled:            true,
+		CreatedAt:          dt,
+		UpdatedAt:          dt,
+	}
+}
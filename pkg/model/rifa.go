@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReservaRifa é o bloqueio temporário de números de uma rifa enquanto o
+// pagamento é feito. Vencido o prazo os números voltam a ficar disponíveis.
+type ReservaRifa struct {
+	ID        string             `json:"id"`
+	SorteioID primitive.ObjectID `json:"sorteio_id"`
+	ClienteID primitive.ObjectID `json:"cliente_id"`
+	Numeros   []int              `json:"numeros"`
+	ExpiraEm  time.Time          `json:"expira_em"`
+}
+
+// DisponibilidadeRifa é a situação dos números de uma rifa num instante.
+type DisponibilidadeRifa struct {
+	SorteioID   primitive.ObjectID `json:"sorteio_id"`
+	Total       int                `json:"total"`
+	Vendidos    int                `json:"vendidos"`
+	Reservados  int                `json:"reservados"`
+	Disponiveis []int              `json:"disponiveis"`
+	Esgotado    bool               `json:"esgotado"`
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de chave Pix aceitos no saque.
+const (
+	ChaveCPF      = "cpf"
+	ChaveCNPJ     = "cnpj"
+	ChaveEmail    = "email"
+	ChaveTelefone = "telefone"
+	ChaveEVP      = "evp"
+)
+
+// Estados do saque. Aguardando aprovação e processando seguram o valor na
+// conta de saques pendentes; pago, rejeitado e falhou são finais.
+const (
+	SaqueAguardandoAprovacao = "aguardando_aprovacao"
+	SaqueProcessando         = "processando"
+	SaquePago                = "pago"
+	SaqueRejeitado           = "rejeitado"
+	SaqueFalhou              = "falhou"
+)
+
+// Saque é o pedido de retirada do saldo da carteira para uma chave Pix do
+// próprio cliente. Valor em centavos, debitado por inteiro; o recebedor
+// recebe Valor menos Taxa.
+type Saque struct {
+	ID            primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType      string             `bson:"data_type" json:"-"`
+	ClienteID     primitive.ObjectID `bson:"cliente_id" json:"cliente_id"`
+	Valor         int64              `bson:"valor" json:"valor"`
+	Taxa          int64              `bson:"taxa" json:"taxa"`
+	TipoChave     string             `bson:"tipo_chave" json:"tipo_chave"`
+	Chave         string             `bson:"chave" json:"chave"`
+	NomeRecebedor string             `bson:"nome_recebedor" json:"nome_recebedor"`
+	Status        string             `bson:"status" json:"status"`
+	Automatico    bool               `bson:"automatico" json:"automatico"`
+	EndToEndID    string             `bson:"end_to_end_id,omitempty" json:"end_to_end_id,omitempty"`
+	PagoEm        time.Time          `bson:"pago_em,omitempty" json:"pago_em,omitempty"`
+	AvaliadoPor   string             `bson:"avaliado_por,omitempty" json:"avaliado_por,omitempty"`
+	Motivo        string             `bson:"motivo,omitempty" json:"motivo,omitempty"`
+	CreatedAt     string             `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt     string             `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+// PedidoSaque é o corpo do pedido de saque.
+type PedidoSaque struct {
+	ClienteID primitive.ObjectID `json:"cliente_id"`
+	Valor     int64              `json:"valor"`
+	TipoChave string             `json:"tipo_chave"`
+	Chave     string             `json:"chave"`
+}
+
+// DecisaoSaque é o corpo da aprovação ou rejeição de um saque na fila.
+// Usuario vem do token do administrador.
+type DecisaoSaque struct {
+	Usuario string `json:"-"`
+	Motivo  string `json:"motivo"`
+}
+
+type FilterSaque struct {
+	ClienteID primitive.ObjectID `json:"cliente_id"`
+	Status    string             `json:"status"`
+}
+
+func NewSaque(saque_request Saque) *Saque {
+	dt := time.Now().Format(time.RFC3339)
+	return &Saque{
+		ID:            primitive.NewObjectID(),
+		DataType:      "saque",
+		ClienteID:     saque_request.ClienteID,
+		Valor:         saque_request.Valor,
+		Taxa:          saque_request.Taxa,
+		TipoChave:     saque_request.TipoChave,
+		Chave:         saque_request.Chave,
+		NomeRecebedor: saque_request.NomeRecebedor,
+		Status:        saque_request.Status,
+		Automatico:    saque_request.Automatico,
+		CreatedAt:     dt,
+		UpdatedAt:     dt,
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+// ParametrosSimulacao descreve a configuração de jogo a ser simulada antes do
+// lançamento. ApostasPorSorteio é a premissa de vendas: cada sorteio simulado
+// recebe essa quantidade de apostas aleatórias de ValorAposta centavos.
+type ParametrosSimulacao struct {
+	Jogo              string        `json:"jogo"`
+	TabelaPremios     []FaixaPremio `json:"tabela_premios"`
+	ValorAposta       int64         `json:"valor_aposta"`
+	PercentualPremio  int64         `json:"percentual_premio"`
+	ApostasPorSorteio int64         `json:"apostas_por_sorteio"`
+	Sorteios          int           `json:"sorteios"`
+	Workers           int           `json:"workers,omitempty"`
+	Semente           int64         `json:"semente,omitempty"`
+}
+
+// PercentisPagamento são os percentis do total pago por sorteio, em centavos.
+type PercentisPagamento struct {
+	P50    int64 `json:"p50"`
+	P90    int64 `json:"p90"`
+	P95    int64 `json:"p95"`
+	P99    int64 `json:"p99"`
+	Maximo int64 `json:"maximo"`
+}
+
+// ProbabilidadeFaixa resume o comportamento de uma faixa da tabela na simulação.
+// ProbabilidadeAposta é a chance de um bilhete cair na faixa; ProbabilidadeSorteio
+// é a chance de a faixa ter ao menos um ganhador num sorteio.
+type ProbabilidadeFaixa struct {
+	Acertos              int     `json:"acertos"`
+	Tipo                 string  `json:"tipo"`
+	ProbabilidadeAposta  float64 `json:"probabilidade_aposta"`
+	ProbabilidadeSorteio float64 `json:"probabilidade_sorteio"`
+	GanhadoresMedio      float64 `json:"ganhadores_medio"`
+	PagamentoMedio       float64 `json:"pagamento_medio"`
+}
+
+// ResultadoSimulacao é o retorno ao apostador e a exposição a prêmios
+// estimados pela simulação. Valores monetários em centavos.
+type ResultadoSimulacao struct {
+	Jogo                 string               `json:"jogo"`
+	Sorteios             int                  `json:"sorteios"`
+	ApostasPorSorteio    int64                `json:"apostas_por_sorteio"`
+	Semente              int64                `json:"semente"`
+	ArrecadadoPorSorteio int64                `json:"arrecadado_por_sorteio"`
+	RTP                  float64              `json:"rtp"`
+	PagamentoMedio       float64              `json:"pagamento_medio"`
+	DesvioPadrao         float64              `json:"desvio_padrao"`
+	Percentis            PercentisPagamento   `json:"percentis"`
+	AcumuladoMedio       float64              `json:"acumulado_medio"`
+	ResiduoMedio         float64              `json:"residuo_medio"`
+	Faixas               []ProbabilidadeFaixa `json:"faixas"`
+}
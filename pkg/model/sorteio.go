@@ -0,0 +1,163 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/service/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estados do ciclo de vida de um sorteio.
+const (
+	SorteioAgendado  = "agendado"
+	SorteioAberto    = "aberto"
+	SorteioFechado   = "fechado"
+	SorteioSorteado  = "sorteado"
+	SorteioLiquidado = "liquidado"
+	SorteioCancelado = "cancelado"
+)
+
+// transicoesSorteio define, para cada estado, para quais estados o sorteio pode ir.
+var transicoesSorteio = map[string][]string{
+	SorteioAgendado:  {SorteioAberto, SorteioCancelado},
+	SorteioAberto:    {SorteioFechado, SorteioCancelado},
+	SorteioFechado:   {SorteioSorteado, SorteioCancelado},
+	SorteioSorteado:  {SorteioLiquidado},
+	SorteioLiquidado: {},
+	SorteioCancelado: {},
+}
+
+type Sorteio struct {
+	ID               primitive.ObjectID `bson:"_id" json:"_id"`
+	DataType         string             `bson:"data_type" json:"-"`
+	Nome             string             `bson:"nome" json:"nome"`
+	Concurso         int64              `bson:"concurso" json:"concurso"`
+	Status           string             `bson:"status" json:"status"`
+	Jogo             string             `bson:"jogo" json:"jogo"`
+	AgendaID         primitive.ObjectID `bson:"agenda_id,omitempty" json:"agenda_id,omitempty"`
+	ValorAposta      int64              `bson:"valor_aposta" json:"valor_aposta"`
+	PercentualPremio int64              `bson:"percentual_premio" json:"percentual_premio"`
+	TabelaPremios    []FaixaPremio      `bson:"tabela_premios" json:"tabela_premios"`
+	TetoAcumulado    int64              `bson:"teto_acumulado" json:"teto_acumulado,omitempty"`
+	DataAbertura     time.Time          `bson:"data_abertura" json:"data_abertura"`
+	DataFechamento   time.Time          `bson:"data_fechamento" json:"data_fechamento"`
+	DataSorteio      time.Time          `bson:"data_sorteio" json:"data_sorteio"`
+	Resultado        []int              `bson:"resultado" json:"resultado,omitempty"`
+	SeedHash         string             `bson:"seed_hash" json:"seed_hash,omitempty"`
+	ServerSeed       string             `bson:"server_seed" json:"-"`
+	Entropia         string             `bson:"entropia" json:"entropia,omitempty"`
+	// Fonte pública da entropia, definida antes da abertura e fixa a partir dela.
+	FonteEntropia    string             `bson:"fonte_entropia" json:"fonte_entropia,omitempty"`
+	MerkleRaiz       string             `bson:"merkle_raiz" json:"merkle_raiz,omitempty"`
+	MerkleFolhas     int64              `bson:"merkle_folhas" json:"merkle_folhas"`
+	QtdApostas       int64              `bson:"qtd_apostas" json:"qtd_apostas"`
+	TotalArrecadado  int64              `bson:"total_arrecadado" json:"total_arrecadado"`
+	ValorPremio      int64              `bson:"valor_premio" json:"valor_premio"`
+	Rateio           []RateioFaixa      `bson:"rateio" json:"rateio,omitempty"`
+	Residuo          int64              `bson:"residuo" json:"residuo"`
+	Acumulado        int64              `bson:"acumulado" json:"acumulado"`
+	AcumuladoProximo int64              `bson:"acumulado_proximo" json:"acumulado_proximo"`
+	Historico        []TransicaoSorteio `bson:"historico" json:"historico,omitempty"`
+	// Etapas posteriores à transição ou à liquidação que falharam e esperam
+	// o reprocessamento por um admin.
+	EtapasPendentes []string `bson:"etapas_pendentes" json:"etapas_pendentes,omitempty"`
+	Enabled         bool     `bson:"enabled" json:"enabled"`
+	CreatedAt       string   `bson:"created_at" json:"created_at,omitempty"`
+	UpdatedAt       string   `bson:"updated_at" json:"updated_at,omitempty"`
+}
+
+type TransicaoSorteio struct {
+	De     string    `bson:"de" json:"de"`
+	Para   string    `bson:"para" json:"para"`
+	Motivo string    `bson:"motivo,omitempty" json:"motivo,omitempty"`
+	Data   time.Time `bson:"data" json:"data"`
+}
+
+// VerificacaoSorteio reúne os dados públicos necessários para recalcular o resultado.
+type VerificacaoSorteio struct {
+	SorteioID     primitive.ObjectID `json:"sorteio_id"`
+	Status        string             `json:"status"`
+	SeedHash      string             `json:"seed_hash"`
+	ServerSeed    string             `json:"server_seed,omitempty"`
+	FonteEntropia string             `json:"fonte_entropia,omitempty"`
+	Entropia      string             `json:"entropia,omitempty"`
+	Jogo          string             `json:"jogo"`
+	MerkleRaiz    string             `json:"merkle_raiz,omitempty"`
+	Resultado     []int              `json:"resultado,omitempty"`
+	Recalculado   []int              `json:"resultado_recalculado,omitempty"`
+	Valido        bool               `json:"valido"`
+	Motivo        string             `json:"motivo,omitempty"`
+}
+
+type FilterSorteio struct {
+	Nome    string `json:"nome"`
+	Jogo    string `json:"jogo"`
+	Status  string `json:"status"`
+	Enabled string `json:"enabled"`
+}
+
+func (s Sorteio) SorteioConvet() string {
+	data, err := json.Marshal(s)
+
+	if err != nil {
+		logger.Error("error to convert Sorteio to JSON", err)
+
+		return ""
+	}
+
+	return string(data)
+}
+
+// PodeTransitar informa se o sorteio pode sair do estado atual para o estado informado.
+func (s *Sorteio) PodeTransitar(para string) bool {
+	for _, st := range transicoesSorteio[s.Status] {
+		if st == para {
+			return true
+		}
+	}
+	return false
+}
+
+// JanelaAberta informa se o instante informado está dentro da janela de vendas.
+func (s *Sorteio) JanelaAberta(agora time.Time) bool {
+	if !s.DataAbertura.IsZero() && agora.Before(s.DataAbertura) {
+		return false
+	}
+	if !s.DataFechamento.IsZero() && !agora.Before(s.DataFechamento) {
+		return false
+	}
+	return true
+}
+
+func StatusSorteioValido(status string) bool {
+	_, existe := transicoesSorteio[status]
+	return existe
+}
+
+func NewSorteio(sorteio_request Sorteio) *Sorteio {
+	dt := time.Now().Format(time.RFC3339)
+	return &Sorteio{
+		ID:               primitive.NewObjectID(),
+		DataType:         "sorteio",
+		Nome:             validation.CareString(sorteio_request.Nome),
+		Concurso:         sorteio_request.Concurso,
+		Status:           SorteioAgendado,
+		Jogo:             sorteio_request.Jogo,
+		AgendaID:         sorteio_request.AgendaID,
+		ValorAposta:      sorteio_request.ValorAposta,
+		PercentualPremio: sorteio_request.PercentualPremio,
+		TabelaPremios:    sorteio_request.TabelaPremios,
+		TetoAcumulado:    sorteio_request.TetoAcumulado,
+		FonteEntropia:    strings.TrimSpace(sorteio_request.FonteEntropia),
+		DataAbertura:     sorteio_request.DataAbertura,
+		DataFechamento:   sorteio_request.DataFechamento,
+		DataSorteio:      sorteio_request.DataSorteio,
+		Historico:        []TransicaoSorteio{},
+		Enabled:          true,
+		CreatedAt:        dt,
+		UpdatedAt:        dt,
+	}
+}
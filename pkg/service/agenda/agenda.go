@@ -0,0 +1,296 @@
+package agenda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/rateio"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrAgendaNaoEncontrada = errors.New("agenda não encontrada")
+	ErrAgendaInvalida      = errors.New("dados da agenda inválidos")
+)
+
+type AgendaServiceInterface interface {
+	Create(ctx context.Context, agenda model.Agenda) (*model.Agenda, error)
+	Update(ctx context.Context, ID string, agendaToChange *model.Agenda) (bool, error)
+	GetByID(ctx context.Context, ID string) (*model.Agenda, error)
+	GetAll(ctx context.Context, filters model.FilterAgenda, limit, page int64) (*model.Paginate, error)
+	GetAtivas(ctx context.Context) ([]*model.Agenda, error)
+}
+
+type AgendaDataService struct {
+	mdb mongodb.MongoDBInterface
+}
+
+func NewAgendaService(mongo_connection mongodb.MongoDBInterface) *AgendaDataService {
+	return &AgendaDataService{
+		mdb: mongo_connection,
+	}
+}
+
+func (ads *AgendaDataService) Create(ctx context.Context, agenda model.Agenda) (*model.Agenda, error) {
+	collection := ads.mdb.GetCollection("cfStore")
+
+	agd := model.NewAgenda(agenda)
+	if err := validarAgenda(agd); err != nil {
+		return nil, err
+	}
+
+	result, err := collection.InsertOne(ctx, agd)
+	if err != nil {
+		logger.Error("erro salvar Agenda", err)
+		return nil, err
+	}
+
+	agd.ID = result.InsertedID.(primitive.ObjectID)
+
+	return agd, nil
+}
+
+func (ads *AgendaDataService) Update(ctx context.Context, ID string, agenda *model.Agenda) (bool, error) {
+	collection := ads.mdb.GetCollection("cfStore")
+
+	atual, err := ads.GetByID(ctx, ID)
+	if err != nil {
+		return false, err
+	}
+
+	// Os campos omitidos mantêm o valor atual antes da validação.
+	if agenda.Nome != "" {
+		atual.Nome = agenda.Nome
+	}
+	if agenda.DiasSemana != nil {
+		atual.DiasSemana = agenda.DiasSemana
+	}
+	if agenda.Horario != "" {
+		atual.Horario = agenda.Horario
+	}
+	if agenda.Fuso != "" {
+		atual.Fuso = agenda.Fuso
+	}
+	if agenda.FechamentoAntes > 0 {
+		atual.FechamentoAntes = agenda.FechamentoAntes
+	}
+	if agenda.AberturaAntes > 0 {
+		atual.AberturaAntes = agenda.AberturaAntes
+	}
+	if agenda.DiasAntecedencia > 0 {
+		atual.DiasAntecedencia = agenda.DiasAntecedencia
+	}
+	if agenda.ValorAposta > 0 {
+		atual.ValorAposta = agenda.ValorAposta
+	}
+	if agenda.PercentualPremio > 0 {
+		atual.PercentualPremio = agenda.PercentualPremio
+	}
+	if agenda.TabelaPremios != nil {
+		atual.TabelaPremios = agenda.TabelaPremios
+	}
+	if agenda.TetoAcumulado > 0 {
+		atual.TetoAcumulado = agenda.TetoAcumulado
+	}
+	if agenda.FonteEntropia != "" {
+		atual.FonteEntropia = strings.TrimSpace(agenda.FonteEntropia)
+	}
+	atual.Enabled = agenda.Enabled
+
+	if err := validarAgenda(atual); err != nil {
+		return false, err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: atual.ID},
+		{Key: "data_type", Value: "agenda"},
+	}
+
+	update := bson.D{{Key: "$set",
+		Value: bson.D{
+			{Key: "nome", Value: atual.Nome},
+			{Key: "dias_semana", Value: atual.DiasSemana},
+			{Key: "horario", Value: atual.Horario},
+			{Key: "fuso", Value: atual.Fuso},
+			{Key: "fechamento_antes", Value: atual.FechamentoAntes},
+			{Key: "abertura_antes", Value: atual.AberturaAntes},
+			{Key: "dias_antecedencia", Value: atual.DiasAntecedencia},
+			{Key: "valor_aposta", Value: atual.ValorAposta},
+			{Key: "percentual_premio", Value: atual.PercentualPremio},
+			{Key: "tabela_premios", Value: atual.TabelaPremios},
+			{Key: "teto_acumulado", Value: atual.TetoAcumulado},
+			{Key: "fonte_entropia", Value: atual.FonteEntropia},
+			{Key: "enabled", Value: atual.Enabled},
+			{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+		},
+	}}
+
+	_, err = collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error while updating data", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (ads *AgendaDataService) GetByID(ctx context.Context, ID string) (*model.Agenda, error) {
+	collection := ads.mdb.GetCollection("cfStore")
+
+	agenda := &model.Agenda{}
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		logger.Error("Error to parse ObjectIDFromHex", err)
+		return nil, err
+	}
+
+	filter := bson.D{
+		{Key: "data_type", Value: "agenda"},
+		{Key: "_id", Value: objectID},
+	}
+
+	err = collection.FindOne(ctx, filter).Decode(agenda)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrAgendaNaoEncontrada
+		}
+		logger.Error("erro ao consultar Agenda", err)
+		return nil, err
+	}
+
+	return agenda, nil
+}
+
+func (ads *AgendaDataService) GetAll(ctx context.Context, filters model.FilterAgenda, limit, page int64) (*model.Paginate, error) {
+	collection := ads.mdb.GetCollection("cfStore")
+
+	query := bson.M{"data_type": "agenda"}
+
+	if filters.Nome != "" {
+		query["nome"] = bson.M{"$regex": fmt.Sprintf(".*%s.*", filters.Nome), "$options": "i"}
+	}
+	if filters.Jogo != "" {
+		query["jogo"] = filters.Jogo
+	}
+	if filters.Enabled != "" {
+		enable, err := strconv.ParseBool(filters.Enabled)
+		if err != nil {
+			logger.Error("erro converter campo enabled", err)
+			return nil, err
+		}
+		query["enabled"] = enable
+	}
+
+	count, err := collection.CountDocuments(ctx, query, &options.CountOptions{})
+	if err != nil {
+		logger.Error("erro ao consultar todas as Agendas", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+
+	curr, err := collection.Find(ctx, query, pagination.GetPaginatedOpts())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Agenda, 0)
+	for curr.Next(ctx) {
+		agd := &model.Agenda{}
+		if err := curr.Decode(agd); err != nil {
+			logger.Error("erro ao consulta todas as Agendas", err)
+		}
+		result = append(result, agd)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+func (ads *AgendaDataService) GetAtivas(ctx context.Context) ([]*model.Agenda, error) {
+	collection := ads.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "data_type", Value: "agenda"},
+		{Key: "enabled", Value: true},
+	}
+
+	curr, err := collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("erro ao consultar Agendas ativas", err)
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]*model.Agenda, 0)
+	for curr.Next(ctx) {
+		agd := &model.Agenda{}
+		if err := curr.Decode(agd); err != nil {
+			logger.Error("erro ao consultar Agendas ativas", err)
+			continue
+		}
+		result = append(result, agd)
+	}
+
+	return result, nil
+}
+
+func validarAgenda(agenda *model.Agenda) error {
+	if _, err := jogo.Obter(agenda.Jogo); err != nil {
+		return fmt.Errorf("%w: jogo %q não registrado", ErrAgendaInvalida, agenda.Jogo)
+	}
+	if len(agenda.DiasSemana) == 0 {
+		return fmt.Errorf("%w: informe ao menos um dia da semana", ErrAgendaInvalida)
+	}
+	for _, d := range agenda.DiasSemana {
+		if d < time.Sunday || d > time.Saturday {
+			return fmt.Errorf("%w: dia da semana %d inválido (0=domingo a 6=sábado)", ErrAgendaInvalida, d)
+		}
+	}
+	if _, _, err := parseHorario(agenda.Horario); err != nil {
+		return fmt.Errorf("%w: horário deve estar no formato HH:MM", ErrAgendaInvalida)
+	}
+	if _, err := time.LoadLocation(agenda.Fuso); err != nil {
+		return fmt.Errorf("%w: fuso horário %q desconhecido", ErrAgendaInvalida, agenda.Fuso)
+	}
+	if agenda.FechamentoAntes < 0 || agenda.AberturaAntes < 0 {
+		return fmt.Errorf("%w: antecedências não podem ser negativas", ErrAgendaInvalida)
+	}
+	if agenda.AberturaAntes > 0 && agenda.AberturaAntes <= agenda.FechamentoAntes {
+		return fmt.Errorf("%w: abertura deve ser anterior ao fechamento", ErrAgendaInvalida)
+	}
+	if agenda.TetoAcumulado < 0 {
+		return fmt.Errorf("%w: teto do acumulado não pode ser negativo", ErrAgendaInvalida)
+	}
+	if agenda.ValorAposta <= 0 {
+		return fmt.Errorf("%w: valor da aposta deve ser informado em centavos", ErrAgendaInvalida)
+	}
+	if err := rateio.ValidarTabela(agenda.TabelaPremios); err != nil {
+		return fmt.Errorf("%w: %s", ErrAgendaInvalida, err.Error())
+	}
+	if !strings.Contains(agenda.FonteEntropia, model.MarcadorDataFonte) {
+		return fmt.Errorf("%w: fonte da entropia deve conter %s para a data do sorteio", ErrAgendaInvalida, model.MarcadorDataFonte)
+	}
+	return nil
+}
+
+func parseHorario(horario string) (int, int, error) {
+	t, err := time.Parse("15:04", horario)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t.Hour(), t.Minute(), nil
+}
@@ -0,0 +1,234 @@
+package agenda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const lockScheduler = "lock_scheduler_sorteio"
+
+// Scheduler cria os sorteios das agendas ativas e abre/fecha as vendas no
+// horário. Várias réplicas podem rodar o Scheduler: um lease no Mongo elege
+// quem trabalha em cada ciclo, e as transições do sorteio são condicionadas ao
+// status lido, então uma réplica que perca o lease no meio do ciclo não
+// consegue aplicar de novo uma transição já feita por outra.
+type Scheduler struct {
+	mdb       mongodb.MongoDBInterface
+	agendas   AgendaServiceInterface
+	sorteios  sorteio.SorteioServiceInterface
+	intervalo time.Duration
+	instancia string
+}
+
+func NewScheduler(mongo_connection mongodb.MongoDBInterface, agendas AgendaServiceInterface, sorteios sorteio.SorteioServiceInterface, intervalo time.Duration) *Scheduler {
+	hostname, _ := os.Hostname()
+
+	return &Scheduler{
+		mdb:       mongo_connection,
+		agendas:   agendas,
+		sorteios:  sorteios,
+		intervalo: intervalo,
+		instancia: fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), primitive.NewObjectID().Hex()),
+	}
+}
+
+// Run executa um ciclo a cada intervalo até o contexto ser cancelado.
+func (s *Scheduler) Run(ctx context.Context) {
+	if err := s.criarIndices(ctx); err != nil {
+		logger.Error("erro ao criar indices do scheduler", err)
+	}
+
+	ticker := time.NewTicker(s.intervalo)
+	defer ticker.Stop()
+
+	for {
+		s.ciclo(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) ciclo(ctx context.Context, agora time.Time) {
+	lider, err := s.adquirirLease(ctx, agora)
+	if err != nil {
+		logger.Error("erro ao adquirir lease do scheduler", err)
+		return
+	}
+	if !lider {
+		return
+	}
+
+	agendas, err := s.agendas.GetAtivas(ctx)
+	if err != nil {
+		return
+	}
+	for _, agd := range agendas {
+		if err := s.programar(ctx, agd, agora); err != nil {
+			logger.Error("erro ao programar sorteios da agenda", err, zap.String("agenda", agd.ID.Hex()))
+		}
+	}
+
+	s.transicionarVencidos(ctx, model.SorteioAgendado, agora, s.sorteios.Abrir)
+	s.transicionarVencidos(ctx, model.SorteioAberto, agora, s.sorteios.Fechar)
+}
+
+// programar garante que existam sorteios para todas as ocorrências da agenda
+// dentro da antecedência configurada.
+func (s *Scheduler) programar(ctx context.Context, agd *model.Agenda, agora time.Time) error {
+	datas, err := Ocorrencias(agd, agora, agora.AddDate(0, 0, agd.DiasAntecedencia))
+	if err != nil {
+		return err
+	}
+
+	// A primeira data é a última ocorrência antes de agora; serve só de
+	// abertura para a seguinte quando a agenda não define AberturaAntes.
+	for i := 1; i < len(datas); i++ {
+		dataSorteio := datas[i]
+		fechamento := dataSorteio.Add(-time.Duration(agd.FechamentoAntes) * time.Minute)
+		if !fechamento.After(agora) {
+			continue
+		}
+
+		abertura := datas[i-1]
+		if agd.AberturaAntes > 0 {
+			abertura = dataSorteio.Add(-time.Duration(agd.AberturaAntes) * time.Minute)
+		}
+
+		_, criado, err := s.sorteios.CreateRecorrente(ctx, model.Sorteio{
+			Nome:             fmt.Sprintf("%s %s", agd.Nome, dataSorteio.Format("02/01/2006 15:04")),
+			Jogo:             agd.Jogo,
+			AgendaID:         agd.ID,
+			ValorAposta:      agd.ValorAposta,
+			PercentualPremio: agd.PercentualPremio,
+			TabelaPremios:    agd.TabelaPremios,
+			TetoAcumulado:    agd.TetoAcumulado,
+			FonteEntropia:    agd.FonteDoSorteio(dataSorteio),
+			DataAbertura:     abertura,
+			DataFechamento:   fechamento,
+			DataSorteio:      dataSorteio,
+		})
+		if err != nil {
+			return err
+		}
+		if criado {
+			logger.Info("sorteio recorrente criado", zap.String("agenda", agd.ID.Hex()), zap.Time("data_sorteio", dataSorteio))
+		}
+	}
+
+	return nil
+}
+
+func (s *Scheduler) transicionarVencidos(ctx context.Context, status string, agora time.Time, transicao func(ctx context.Context, ID string) (*model.Sorteio, error)) {
+	vencidos, err := s.sorteios.GetVencidos(ctx, status, agora)
+	if err != nil {
+		return
+	}
+
+	for _, str := range vencidos {
+		_, err := transicao(ctx, str.ID.Hex())
+		if err != nil && !errors.Is(err, sorteio.ErrTransicaoConcorrente) {
+			logger.Error("erro na transição automática do sorteio", err, zap.String("sorteio", str.ID.Hex()), zap.String("status", status))
+		}
+	}
+}
+
+// adquirirLease renova ou toma o lease do scheduler. O lease dura três
+// intervalos, de modo que uma réplica parada é substituída rapidamente.
+func (s *Scheduler) adquirirLease(ctx context.Context, agora time.Time) (bool, error) {
+	collection := s.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "_id", Value: lockScheduler},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "dono", Value: s.instancia}},
+			bson.D{{Key: "expira_em", Value: bson.D{{Key: "$lt", Value: agora}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "data_type", Value: "lock"},
+		{Key: "dono", Value: s.instancia},
+		{Key: "expira_em", Value: agora.Add(3 * s.intervalo)},
+	}}}
+
+	_, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		// Outra réplica detém o lease: o upsert colide com o _id existente.
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Scheduler) criarIndices(ctx context.Context) error {
+	collection := s.mdb.GetCollection("cfStore")
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "agenda_id", Value: 1},
+			{Key: "data_sorteio", Value: 1},
+		},
+		Options: options.Index().
+			SetName("sorteio_agenda_data").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.D{
+				{Key: "data_type", Value: "sorteio"},
+				{Key: "agenda_id", Value: bson.D{{Key: "$exists", Value: true}}},
+			}),
+	})
+	return err
+}
+
+// Ocorrencias devolve, no fuso da agenda, a última ocorrência anterior a
+// inicio seguida de todas as ocorrências em (inicio, fim].
+func Ocorrencias(agd *model.Agenda, inicio, fim time.Time) ([]time.Time, error) {
+	loc, err := time.LoadLocation(agd.Fuso)
+	if err != nil {
+		return nil, err
+	}
+	hora, minuto, err := parseHorario(agd.Horario)
+	if err != nil {
+		return nil, err
+	}
+
+	inicio = inicio.In(loc)
+	datas := make([]time.Time, 0)
+
+	// Começa uma semana antes para sempre encontrar a ocorrência anterior.
+	dia := time.Date(inicio.Year(), inicio.Month(), inicio.Day()-7, hora, minuto, 0, 0, loc)
+	for !dia.After(fim) {
+		if slices.Contains(agd.DiasSemana, dia.Weekday()) {
+			switch {
+			case dia.After(inicio):
+				datas = append(datas, dia)
+			case len(datas) == 0:
+				datas = append(datas, dia)
+			default:
+				datas[0] = dia
+			}
+		}
+		dia = time.Date(dia.Year(), dia.Month(), dia.Day()+1, hora, minuto, 0, 0, loc)
+	}
+
+	return datas, nil
+}
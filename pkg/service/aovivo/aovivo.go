@@ -0,0 +1,250 @@
+package aovivo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/redisdb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/rateio"
+)
+
+// bufferCliente é quantos eventos um cliente lento pode acumular antes de
+// começar a perder os mais novos.
+const bufferCliente = 32
+
+var ErrAssinaturaIndisponivel = errors.New("canal ao vivo do sorteio indisponível")
+
+type AoVivoServiceInterface interface {
+	Estado(ctx context.Context, ID string) (*model.EventoAoVivo, error)
+	Assinar(ctx context.Context, ID string) (<-chan []byte, func(), error)
+	Publicar(ctx context.Context, evento *model.EventoAoVivo) error
+	VendaAceita(ctx context.Context, str *model.Sorteio, aposta *model.Aposta) error
+	Transicao(ctx context.Context, str *model.Sorteio) error
+}
+
+// AoVivoDataService publica os eventos de cada sorteio num canal do Redis e
+// repassa aos clientes conectados nesta réplica. Cada réplica mantém uma única
+// assinatura por sorteio, aberta no primeiro cliente e fechada com o último,
+// então todos os clientes veem os mesmos eventos atrás do balanceador.
+type AoVivoDataService struct {
+	rdb       redisdb.RedisClientInterface
+	sorteios  sorteio.SorteioServiceInterface
+	intervalo time.Duration
+
+	mu    sync.Mutex
+	salas map[string]*sala
+}
+
+type sala struct {
+	clientes map[chan []byte]struct{}
+	cancelar context.CancelFunc
+}
+
+func NewAoVivoService(rdb redisdb.RedisClientInterface, sorteios sorteio.SorteioServiceInterface, conf *config.Config) *AoVivoDataService {
+	return &AoVivoDataService{
+		rdb:       rdb,
+		sorteios:  sorteios,
+		intervalo: time.Duration(conf.AoVivoRevelacaoSegundos) * time.Second,
+		salas:     make(map[string]*sala),
+	}
+}
+
+func canal(ID string) string {
+	return "sorteio:aovivo:" + ID
+}
+
+// chaveRevelados guarda os números já mostrados enquanto a revelação corre,
+// para quem conecta no meio do sorteio não ver o resultado antes da hora.
+func chaveRevelados(ID string) string {
+	return "sorteio:aovivo:" + ID + ":revelados"
+}
+
+// Estado monta o retrato atual do sorteio enviado a quem acaba de conectar.
+func (avs *AoVivoDataService) Estado(ctx context.Context, ID string) (*model.EventoAoVivo, error) {
+	str, err := avs.sorteios.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	evento := retrato(model.EventoEstado, str)
+
+	if str.Status == model.SorteioSorteado || str.Status == model.SorteioLiquidado {
+		evento.Revelados = str.Resultado
+		if data, err := avs.rdb.ReadData(ctx, chaveRevelados(ID)); err == nil {
+			revelados := []int{}
+			if err := json.Unmarshal(data, &revelados); err == nil {
+				evento.Revelados = revelados
+			}
+		}
+	}
+
+	return evento, nil
+}
+
+// Assinar devolve os eventos do sorteio publicados a partir de agora e a
+// função que encerra a assinatura do cliente. O canal é fechado se a
+// assinatura do Redis cair.
+func (avs *AoVivoDataService) Assinar(ctx context.Context, ID string) (<-chan []byte, func(), error) {
+	avs.mu.Lock()
+	defer avs.mu.Unlock()
+
+	s, existe := avs.salas[ID]
+	if !existe {
+		ctxSala, cancelar := context.WithCancel(context.Background())
+		msgs, err := avs.rdb.Subscribe(ctxSala, canal(ID))
+		if err != nil {
+			cancelar()
+			logger.Error("erro ao assinar canal ao vivo do Sorteio", err)
+			return nil, nil, errors.Join(ErrAssinaturaIndisponivel, err)
+		}
+
+		s = &sala{clientes: make(map[chan []byte]struct{}), cancelar: cancelar}
+		avs.salas[ID] = s
+		go avs.distribuir(ID, s, msgs)
+	}
+
+	cliente := make(chan []byte, bufferCliente)
+	s.clientes[cliente] = struct{}{}
+
+	sair := func() {
+		avs.mu.Lock()
+		defer avs.mu.Unlock()
+
+		if _, ok := s.clientes[cliente]; !ok {
+			return
+		}
+		delete(s.clientes, cliente)
+		close(cliente)
+
+		if len(s.clientes) == 0 {
+			s.cancelar()
+			if avs.salas[ID] == s {
+				delete(avs.salas, ID)
+			}
+		}
+	}
+
+	return cliente, sair, nil
+}
+
+// distribuir repassa as mensagens do Redis a todos os clientes da sala até a
+// assinatura terminar, e então desconecta os clientes que restaram.
+func (avs *AoVivoDataService) distribuir(ID string, s *sala, msgs <-chan []byte) {
+	for msg := range msgs {
+		avs.mu.Lock()
+		for cliente := range s.clientes {
+			select {
+			case cliente <- msg:
+			default:
+				// Cliente lento perde o evento; o próximo traz os totais atualizados.
+			}
+		}
+		avs.mu.Unlock()
+	}
+
+	avs.mu.Lock()
+	defer avs.mu.Unlock()
+
+	for cliente := range s.clientes {
+		delete(s.clientes, cliente)
+		close(cliente)
+	}
+	s.cancelar()
+	if avs.salas[ID] == s {
+		delete(avs.salas, ID)
+	}
+}
+
+func (avs *AoVivoDataService) Publicar(ctx context.Context, evento *model.EventoAoVivo) error {
+	data, err := json.Marshal(evento)
+	if err != nil {
+		return err
+	}
+
+	return avs.rdb.Publish(ctx, canal(evento.SorteioID.Hex()), data)
+}
+
+// VendaAceita publica os totais do sorteio depois de uma aposta aceita.
+func (avs *AoVivoDataService) VendaAceita(ctx context.Context, str *model.Sorteio, aposta *model.Aposta) error {
+	return avs.Publicar(ctx, retrato(model.EventoVendas, str))
+}
+
+// Transicao publica a mudança de status e, quando o resultado acaba de ser
+// gerado, começa a revelar os números um a um.
+func (avs *AoVivoDataService) Transicao(ctx context.Context, str *model.Sorteio) error {
+	if str.Status == model.SorteioSorteado && len(str.Resultado) > 0 {
+		// Marca a revelação antes de anunciar o status, para o retrato de quem
+		// conectar agora não trazer o resultado inteiro.
+		prazo := time.Duration(len(str.Resultado)+1)*avs.intervalo + time.Minute
+		avs.rdb.SaveData(ctx, chaveRevelados(str.ID.Hex()), []byte("[]"), prazo)
+
+		if err := avs.Publicar(ctx, retrato(model.EventoStatus, str)); err != nil {
+			return err
+		}
+
+		go avs.revelar(str, prazo)
+		return nil
+	}
+
+	return avs.Publicar(ctx, retrato(model.EventoStatus, str))
+}
+
+// revelar publica cada número do resultado a cada intervalo, fora da
+// requisição que sorteou.
+func (avs *AoVivoDataService) revelar(str *model.Sorteio, prazo time.Duration) {
+	ctx := context.Background()
+	chave := chaveRevelados(str.ID.Hex())
+
+	for i := range str.Resultado {
+		time.Sleep(avs.intervalo)
+
+		revelados := str.Resultado[:i+1]
+		data, _ := json.Marshal(revelados)
+		avs.rdb.SaveData(ctx, chave, data, prazo)
+
+		evento := retrato(model.EventoNumero, str)
+		evento.Numero = &str.Resultado[i]
+		evento.Posicao = i + 1
+		evento.Revelados = revelados
+
+		if err := avs.Publicar(ctx, evento); err != nil {
+			logger.Error("erro ao publicar número do Sorteio", err)
+		}
+	}
+
+	if err := avs.rdb.DeleteData(ctx, chave); err != nil {
+		logger.Error("erro ao encerrar revelação do Sorteio", err)
+	}
+}
+
+// retrato copia do sorteio os totais e o prêmio estimado da faixa principal.
+func retrato(tipo string, str *model.Sorteio) *model.EventoAoVivo {
+	return &model.EventoAoVivo{
+		Tipo:            tipo,
+		SorteioID:       str.ID,
+		Status:          str.Status,
+		QtdApostas:      str.QtdApostas,
+		TotalArrecadado: str.TotalArrecadado,
+		PremioEstimado:  premioEstimado(str),
+		Data:            time.Now().Format(time.RFC3339),
+	}
+}
+
+// premioEstimado segue a Estimativa do sorteio: até a liquidação o rateio é
+// uma prévia com as vendas registradas, depois vale o rateio gravado.
+func premioEstimado(str *model.Sorteio) int64 {
+	if str.Status == model.SorteioLiquidado {
+		return rateio.ValorPrincipal(str.TabelaPremios, str.Rateio)
+	}
+
+	valorPremio := rateio.ValorPremio(str.TotalArrecadado, str.PercentualPremio)
+	res := rateio.Calcular(str.TabelaPremios, valorPremio, str.Acumulado, str.TetoAcumulado, nil)
+	return rateio.ValorPrincipal(str.TabelaPremios, res.Faixas)
+}
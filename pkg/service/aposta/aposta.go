@@ -0,0 +1,458 @@
+package aposta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/rabbitmq"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/comprovante"
+	"github.com/katana/fortuna/backend-go/pkg/service/regra"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/desdobramento"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/regras"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LimiteSurpresinha é a quantidade máxima de apostas num pedido de surpresinha.
+const LimiteSurpresinha = 50
+
+var (
+	ErrApostaNaoEncontrada = errors.New("aposta não encontrada")
+	ErrApostaInvalida      = errors.New("dados da aposta inválidos")
+	ErrFilaIndisponivel    = errors.New("fila de processamento de apostas indisponível")
+	ErrSemComprovante      = errors.New("comprovante disponível só para apostas aceitas")
+	ErrComprovanteInvalido = errors.New("comprovante não encontrado ou assinatura inválida")
+
+	// errVendasEncerradas e errJaDecidida desfazem a transação do aceite.
+	errVendasEncerradas = errors.New("vendas do sorteio encerradas")
+	errJaDecidida       = errors.New("aposta já decidida")
+)
+
+type ApostaServiceInterface interface {
+	Create(ctx context.Context, aposta model.Aposta) (*model.Aposta, error)
+	Surpresinha(ctx context.Context, pedido model.PedidoSurpresinha) ([]*model.Aposta, error)
+	GetByID(ctx context.Context, ID string) (*model.Aposta, error)
+	Processar(ctx context.Context, ID string) (*model.Aposta, error)
+	ProcessarMensagem(ctx context.Context, corpo []byte) (*model.Aposta, error)
+	Comprovante(ctx context.Context, ID string) (*model.Comprovante, error)
+	ComprovantePDF(ctx context.Context, ID string) ([]byte, error)
+	Verificar(ctx context.Context, codigo string) (*model.Comprovante, error)
+}
+
+type ApostaDataService struct {
+	mdb       mongodb.MongoDBInterface
+	rbt       rabbitmq.RabbitInterface
+	sorteios  sorteio.SorteioServiceInterface
+	regras    regra.RegraServiceInterface
+	carteira  carteira.CarteiraServiceInterface
+	conf      *config.Config
+	aoAceitar []func(ctx context.Context, sorteio *model.Sorteio, aposta *model.Aposta) error
+}
+
+func NewApostaService(rabbit_connection rabbitmq.RabbitInterface, mongo_connection mongodb.MongoDBInterface, sorteios sorteio.SorteioServiceInterface, regras_jogo regra.RegraServiceInterface, carteira_cliente carteira.CarteiraServiceInterface, conf *config.Config) *ApostaDataService {
+	return &ApostaDataService{
+		mdb:      mongo_connection,
+		rbt:      rabbit_connection,
+		sorteios: sorteios,
+		regras:   regras_jogo,
+		carteira: carteira_cliente,
+		conf:     conf,
+	}
+}
+
+// Create valida a aposta, grava como pendente e publica na fila de
+// processamento. O aceite acontece de forma assíncrona em Processar. Com mais
+// números que a aposta simples ela é desdobrada e custa uma aposta por linha.
+func (aps *ApostaDataService) Create(ctx context.Context, aposta model.Aposta) (*model.Aposta, error) {
+	collection := aps.mdb.GetCollection("cfStore")
+
+	str, err := aps.sorteios.GetByID(ctx, aposta.SorteioID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := aps.desdobrar(str, &aposta); err != nil {
+		return nil, err
+	}
+
+	if err := aps.validar(ctx, str, aposta); err != nil {
+		return nil, err
+	}
+
+	apt := model.NewAposta(aposta)
+	apt.Linhas = aposta.Linhas
+	apt.Valor = str.ValorAposta
+	if len(apt.Linhas) > 0 {
+		apt.Valor *= int64(len(apt.Linhas))
+	}
+
+	if _, err := collection.InsertOne(ctx, apt); err != nil {
+		logger.Error("erro salvar Aposta", err)
+		return nil, err
+	}
+
+	msg := &rabbitmq.Message{
+		Data:        []byte(apt.ApostaConvet()),
+		ContentType: "application/json",
+	}
+	if err := aps.rbt.SenderRb(ctx, aps.conf.ConsumerConfig.QueueName, msg); err != nil {
+		// Sem a mensagem na fila a aposta ficaria pendente para sempre.
+		aps.rejeitar(ctx, apt, "falha ao enfileirar a aposta", nil)
+		return nil, fmt.Errorf("%w: %s", ErrFilaIndisponivel, err.Error())
+	}
+
+	return apt, nil
+}
+
+// Surpresinha gera as apostas do pedido com crypto/rand e registra cada uma
+// por Create, como se o cliente tivesse escolhido os números. Os limites do
+// cliente são conferidos para o pedido inteiro antes da primeira aposta; se
+// uma falha no meio, as já registradas são devolvidas junto com o erro.
+func (aps *ApostaDataService) Surpresinha(ctx context.Context, pedido model.PedidoSurpresinha) ([]*model.Aposta, error) {
+	if pedido.Quantidade < 1 || pedido.Quantidade > LimiteSurpresinha {
+		return nil, invalida([]model.ErroCampo{{
+			Campo:    "quantidade",
+			Codigo:   regras.CodigoForaDoIntervalo,
+			Mensagem: fmt.Sprintf("peça de 1 a %d apostas", LimiteSurpresinha),
+		}})
+	}
+
+	str, err := aps.sorteios.GetByID(ctx, pedido.SorteioID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := jogo.Obter(str.Jogo)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrApostaInvalida, err.Error())
+	}
+
+	numeros := make([][]int, pedido.Quantidade)
+	for i := range numeros {
+		numeros[i], err = jogo.Surpresinha(game.Formato(), pedido.Fixos, pedido.Excluidos, jogo.RandSeguro{})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrApostaInvalida, err.Error())
+		}
+	}
+
+	if !pedido.ClienteID.IsZero() {
+		qtd := int64(pedido.Quantidade)
+		erros, err := aps.regras.AvaliarLimites(ctx, str, pedido.ClienteID, qtd, qtd*str.ValorAposta)
+		if err != nil {
+			return nil, err
+		}
+		if err := invalida(erros); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*model.Aposta, 0, pedido.Quantidade)
+	for _, n := range numeros {
+		apt, err := aps.Create(ctx, model.Aposta{
+			SorteioID: pedido.SorteioID,
+			ClienteID: pedido.ClienteID,
+			Numeros:   n,
+		})
+		if err != nil {
+			return result, err
+		}
+		result = append(result, apt)
+	}
+
+	return result, nil
+}
+
+func (aps *ApostaDataService) GetByID(ctx context.Context, ID string) (*model.Aposta, error) {
+	collection := aps.mdb.GetCollection("cfStore")
+
+	aposta := &model.Aposta{}
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		logger.Error("Error to parse ObjectIDFromHex", err)
+		return nil, ErrApostaNaoEncontrada
+	}
+
+	filter := bson.D{
+		{Key: "data_type", Value: "aposta"},
+		{Key: "_id", Value: objectID},
+	}
+
+	err = collection.FindOne(ctx, filter).Decode(aposta)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrApostaNaoEncontrada
+		}
+		logger.Error("erro ao consultar Aposta", err)
+		return nil, err
+	}
+
+	return aposta, nil
+}
+
+// Processar decide uma aposta pendente: aceita se ela ainda cumpre as regras
+// do jogo e rejeita com o motivo e os erros por campo caso contrário. Apostas já
+// decididas são devolvidas sem alteração, o que torna a reentrega da
+// mensagem inofensiva. Erros devolvidos são transitórios.
+func (aps *ApostaDataService) Processar(ctx context.Context, ID string) (*model.Aposta, error) {
+	apt, err := aps.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if apt.Status != model.ApostaPendente {
+		return apt, nil
+	}
+
+	str, err := aps.sorteios.GetByID(ctx, apt.SorteioID.Hex())
+	if err != nil {
+		if errors.Is(err, sorteio.ErrSorteioNaoEncontrado) {
+			return aps.rejeitar(ctx, apt, "sorteio não encontrado", nil)
+		}
+		return nil, err
+	}
+
+	if err := aps.validar(ctx, str, *apt); err != nil {
+		validacao := &regras.ErroValidacao{}
+		if errors.As(err, &validacao) {
+			return aps.rejeitar(ctx, apt, err.Error(), validacao.Erros)
+		}
+		return nil, err
+	}
+
+	aceita, atualizado, err := aps.aceitar(ctx, apt)
+	switch {
+	case errors.Is(err, carteira.ErrSaldoInsuficiente):
+		return aps.rejeitar(ctx, apt, "saldo insuficiente na carteira", []model.ErroCampo{{
+			Campo:    "valor",
+			Codigo:   regras.CodigoSaldoInsuficiente,
+			Mensagem: "saldo insuficiente na carteira do cliente",
+		}})
+	case errors.Is(err, errVendasEncerradas):
+		return aps.rejeitar(ctx, apt, "vendas do sorteio encerradas", []model.ErroCampo{{
+			Campo:    "sorteio_id",
+			Codigo:   regras.CodigoNaoPermitido,
+			Mensagem: "o sorteio não aceita mais apostas",
+		}})
+	case errors.Is(err, errJaDecidida):
+		return aps.GetByID(ctx, apt.ID.Hex())
+	case err != nil:
+		return nil, err
+	}
+
+	for _, etapa := range aps.aoAceitar {
+		if err := etapa(ctx, atualizado, aceita); err != nil {
+			logger.Error("erro na etapa posterior ao aceite da Aposta", err)
+		}
+	}
+
+	return aceita, nil
+}
+
+// aceitar soma a aposta aos totais do sorteio, debita o cliente e marca a
+// aposta como aceita numa única transação. Os totais só são somados com o
+// sorteio aberto, e essa escrita no sorteio conflita com a do fechamento: uma
+// aposta aceita antes do fechamento entra no compromisso das apostas, e uma
+// processada depois é rejeitada sem cobrança. Os totais alimentam a
+// estimativa de prêmio; a liquidação recalcula tudo a partir das apostas.
+func (aps *ApostaDataService) aceitar(ctx context.Context, apt *model.Aposta) (*model.Aposta, *model.Sorteio, error) {
+	var aceita *model.Aposta
+	atualizado := &model.Sorteio{}
+
+	_, err := aps.mdb.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		err := aps.mdb.GetCollection("cfStore").FindOneAndUpdate(sessCtx,
+			bson.D{
+				{Key: "_id", Value: apt.SorteioID},
+				{Key: "data_type", Value: "sorteio"},
+				{Key: "status", Value: model.SorteioAberto},
+			},
+			bson.D{{Key: "$inc", Value: bson.D{
+				{Key: "qtd_apostas", Value: int64(1)},
+				{Key: "total_arrecadado", Value: apt.Valor},
+			}}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(atualizado)
+		if err == mongo.ErrNoDocuments {
+			return nil, errVendasEncerradas
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := aps.carteira.DebitarAposta(sessCtx, apt); err != nil {
+			return nil, err
+		}
+
+		result, decidiu, err := aps.decidir(sessCtx, apt, model.ApostaAceita, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		if !decidiu {
+			return nil, errJaDecidida
+		}
+		aceita = result
+		return nil, nil
+	})
+	if err != nil {
+		if !errors.Is(err, carteira.ErrSaldoInsuficiente) && !errors.Is(err, errVendasEncerradas) && !errors.Is(err, errJaDecidida) {
+			logger.Error("erro ao aceitar Aposta", err)
+		}
+		return nil, nil, err
+	}
+
+	return aceita, atualizado, nil
+}
+
+// AoAceitar registra uma etapa executada depois que uma aposta é aceita, com
+// os totais do sorteio já atualizados. Falhas ficam só no log.
+func (aps *ApostaDataService) AoAceitar(etapa func(ctx context.Context, sorteio *model.Sorteio, aposta *model.Aposta) error) {
+	aps.aoAceitar = append(aps.aoAceitar, etapa)
+}
+
+// ProcessarMensagem extrai a aposta do corpo publicado por Create e a processa.
+func (aps *ApostaDataService) ProcessarMensagem(ctx context.Context, corpo []byte) (*model.Aposta, error) {
+	msg := model.Aposta{}
+	if err := json.Unmarshal(corpo, &msg); err != nil {
+		return nil, fmt.Errorf("%w: mensagem ilegível: %s", ErrApostaInvalida, err.Error())
+	}
+
+	return aps.Processar(ctx, msg.ID.Hex())
+}
+
+// validar reúne as regras do jogo e as do cliente numa única lista de erros
+// por campo, devolvida como ErrApostaInvalida envolvendo *regras.ErroValidacao.
+func (aps *ApostaDataService) validar(ctx context.Context, str *model.Sorteio, aposta model.Aposta) error {
+	game, err := jogo.Obter(str.Jogo)
+	if err == nil {
+		if _, ok := game.(*jogo.Rifa); ok {
+			return invalida([]model.ErroCampo{{
+				Campo:    "sorteio_id",
+				Codigo:   regras.CodigoNaoPermitido,
+				Mensagem: "números de rifa são vendidos por reserva",
+			}})
+		}
+	}
+
+	erros, err := aps.regras.Avaliar(ctx, str, aposta)
+	if err != nil {
+		return err
+	}
+
+	if aposta.ClienteID.IsZero() {
+		erros = append(erros, model.ErroCampo{
+			Campo:    "cliente_id",
+			Codigo:   regras.CodigoObrigatorio,
+			Mensagem: "cliente obrigatório",
+		})
+		return invalida(erros)
+	}
+
+	count, err := aps.mdb.GetCollection("cfStore").CountDocuments(ctx, bson.D{
+		{Key: "data_type", Value: "cliente"},
+		{Key: "_id", Value: aposta.ClienteID},
+		{Key: "enabled", Value: true},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar Cliente da Aposta", err)
+		return err
+	}
+	if count == 0 {
+		erros = append(erros, model.ErroCampo{
+			Campo:    "cliente_id",
+			Codigo:   regras.CodigoClienteIndisponivel,
+			Mensagem: "cliente não encontrado ou desativado",
+		})
+	}
+
+	return invalida(erros)
+}
+
+// desdobrar gera as linhas quando o cliente escolhe mais números que a aposta
+// simples de um jogo desdobrável. Quantos números são aceitos fica com as regras.
+func (aps *ApostaDataService) desdobrar(str *model.Sorteio, aposta *model.Aposta) error {
+	aposta.Linhas = nil
+
+	game, err := jogo.Obter(str.Jogo)
+	if err != nil {
+		// validar devolve o erro do jogo junto com os demais.
+		return nil
+	}
+
+	formato := game.Formato()
+	if !formato.Desdobravel || len(aposta.Numeros) <= formato.Tamanhos[0] {
+		aposta.Garantia = 0
+		return nil
+	}
+
+	linhas, err := desdobramento.Gerar(aposta.Numeros, formato.Tamanhos[0], aposta.Garantia)
+	if err != nil {
+		return invalida([]model.ErroCampo{{
+			Campo:    "numeros",
+			Codigo:   regras.CodigoDesdobramento,
+			Mensagem: err.Error(),
+		}})
+	}
+	aposta.Linhas = linhas
+
+	return nil
+}
+
+func invalida(erros []model.ErroCampo) error {
+	if err := regras.Erro(erros); err != nil {
+		return fmt.Errorf("%w: %w", ErrApostaInvalida, err)
+	}
+	return nil
+}
+
+func (aps *ApostaDataService) rejeitar(ctx context.Context, apt *model.Aposta, motivo string, erros []model.ErroCampo) (*model.Aposta, error) {
+	result, _, err := aps.decidir(ctx, apt, model.ApostaRejeitada, motivo, erros)
+	return result, err
+}
+
+// decidir tira a aposta de pendente. Se outra entrega já decidiu, devolve a
+// aposta como está e false.
+func (aps *ApostaDataService) decidir(ctx context.Context, apt *model.Aposta, status, motivo string, erros []model.ErroCampo) (*model.Aposta, bool, error) {
+	collection := aps.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "_id", Value: apt.ID},
+		{Key: "data_type", Value: "aposta"},
+		{Key: "status", Value: model.ApostaPendente},
+	}
+	campos := bson.D{
+		{Key: "status", Value: status},
+		{Key: "motivo", Value: motivo},
+		{Key: "erros", Value: erros},
+		{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+	}
+	if status == model.ApostaAceita {
+		campos = append(campos, bson.E{Key: "codigo", Value: comprovante.Codigo(aps.conf.ComprovanteSegredo, apt.ID)})
+	}
+	update := bson.D{{Key: "$set", Value: campos}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	result := &model.Aposta{}
+	err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			atual, err := aps.GetByID(ctx, apt.ID.Hex())
+			return atual, false, err
+		}
+		logger.Error("erro ao atualizar status da Aposta", err)
+		return nil, false, err
+	}
+
+	return result, true, nil
+}
@@ -0,0 +1,116 @@
+package aposta
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/comprovante"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Comprovante monta o recibo de uma aposta aceita, com o QR assinado.
+func (aps *ApostaDataService) Comprovante(ctx context.Context, ID string) (*model.Comprovante, error) {
+	apt, err := aps.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if apt.Status != model.ApostaAceita || apt.Codigo == "" {
+		return nil, ErrSemComprovante
+	}
+
+	result, err := aps.montarComprovante(ctx, apt)
+	if err != nil {
+		return nil, err
+	}
+	result.QR = comprovante.GerarQR(aps.conf.ComprovanteSegredo, apt)
+
+	return result, nil
+}
+
+func (aps *ApostaDataService) ComprovantePDF(ctx context.Context, ID string) ([]byte, error) {
+	result, err := aps.Comprovante(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return comprovante.PDF(result)
+}
+
+// Verificar confere um bilhete pelo código digitado ou pelo payload do QR. O
+// código só vale se a assinatura recalculada para a aposta encontrada for a
+// mesma; o QR precisa ter assinatura válida e os dados da aposta gravada.
+func (aps *ApostaDataService) Verificar(ctx context.Context, codigo string) (*model.Comprovante, error) {
+	var apt *model.Aposta
+
+	if strings.HasPrefix(codigo, comprovante.PrefixoQR+".") {
+		dados, err := comprovante.LerQR(aps.conf.ComprovanteSegredo, codigo)
+		if err != nil {
+			return nil, ErrComprovanteInvalido
+		}
+
+		apt, err = aps.GetByID(ctx, dados.ApostaID)
+		if err != nil {
+			if errors.Is(err, ErrApostaNaoEncontrada) {
+				return nil, ErrComprovanteInvalido
+			}
+			return nil, err
+		}
+		if !dados.Confere(apt) {
+			return nil, ErrComprovanteInvalido
+		}
+	} else {
+		codigo = comprovante.NormalizarCodigo(codigo)
+
+		apt = &model.Aposta{}
+		err := aps.mdb.GetCollection("cfStore").FindOne(ctx, bson.D{
+			{Key: "data_type", Value: "aposta"},
+			{Key: "codigo", Value: codigo},
+		}).Decode(apt)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, ErrComprovanteInvalido
+			}
+			logger.Error("erro ao consultar Aposta pelo código", err)
+			return nil, err
+		}
+		if comprovante.Codigo(aps.conf.ComprovanteSegredo, apt.ID) != codigo {
+			return nil, ErrComprovanteInvalido
+		}
+	}
+
+	return aps.montarComprovante(ctx, apt)
+}
+
+func (aps *ApostaDataService) montarComprovante(ctx context.Context, apt *model.Aposta) (*model.Comprovante, error) {
+	str, err := aps.sorteios.GetByID(ctx, apt.SorteioID.Hex())
+	if err != nil && !errors.Is(err, sorteio.ErrSorteioNaoEncontrado) {
+		return nil, err
+	}
+
+	result := &model.Comprovante{
+		ApostaID:  apt.ID,
+		SorteioID: apt.SorteioID,
+		Numeros:   apt.Numeros,
+		Valor:     apt.Valor,
+		Status:    apt.Status,
+		Acertos:   apt.Acertos,
+		Premio:    apt.Premio,
+		Codigo:    apt.Codigo,
+		CriadaEm:  apt.CreatedAt,
+	}
+	if str != nil {
+		result.Sorteio = str.Nome
+		result.Concurso = str.Concurso
+		result.Jogo = str.Jogo
+		result.DataSorteio = str.DataSorteio
+		result.StatusSorteio = str.Status
+	}
+
+	return result, nil
+}
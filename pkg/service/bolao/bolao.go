@@ -0,0 +1,527 @@
+package bolao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/aposta"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/rateio"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Limites de um bolão.
+const (
+	LimiteJogos = 50
+	LimiteCotas = 1000
+)
+
+var (
+	ErrBolaoNaoEncontrado  = errors.New("bolão não encontrado")
+	ErrBolaoInvalido       = errors.New("dados do bolão inválidos")
+	ErrBolaoEncerrado      = errors.New("bolão não está aberto para venda")
+	ErrCotasEsgotadas      = errors.New("cotas do bolão insuficientes")
+	ErrSorteioNaoLiquidado = errors.New("sorteio do bolão ainda não liquidado")
+)
+
+type BolaoServiceInterface interface {
+	Create(ctx context.Context, bolao model.Bolao) (*model.Bolao, error)
+	GetByID(ctx context.Context, ID string) (*model.Bolao, error)
+	GetAll(ctx context.Context, filters model.FilterBolao, limit, page int64) (*model.Paginate, error)
+	ComprarCotas(ctx context.Context, ID string, cota model.CotaBolao) (*model.CotaBolao, error)
+	GetCotas(ctx context.Context, ID string) ([]*model.CotaBolao, error)
+	Liquidar(ctx context.Context, ID string) (*model.Bolao, error)
+	LiquidarSorteio(ctx context.Context, str *model.Sorteio) error
+}
+
+type BolaoDataService struct {
+	mdb      mongodb.MongoDBInterface
+	sorteios sorteio.SorteioServiceInterface
+	apostas  aposta.ApostaServiceInterface
+	carteira carteira.CarteiraServiceInterface
+}
+
+func NewBolaoService(mongo_connection mongodb.MongoDBInterface, sorteios sorteio.SorteioServiceInterface, apostas aposta.ApostaServiceInterface, carteira_cliente carteira.CarteiraServiceInterface) *BolaoDataService {
+	return &BolaoDataService{
+		mdb:      mongo_connection,
+		sorteios: sorteios,
+		apostas:  apostas,
+		carteira: carteira_cliente,
+	}
+}
+
+// Create grava o bolão e registra os jogos como apostas do organizador pelo
+// fluxo normal de apostas. O valor da cota é o custo dos jogos dividido pelas
+// cotas, arredondado para cima. Se um jogo for recusado o bolão é cancelado;
+// as apostas já registradas continuam valendo para o organizador.
+func (bds *BolaoDataService) Create(ctx context.Context, bolao model.Bolao) (*model.Bolao, error) {
+	collection := bds.mdb.GetCollection("cfStore")
+
+	str, err := bds.sorteios.GetByID(ctx, bolao.SorteioID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if str.Status != model.SorteioAberto || !str.JanelaAberta(time.Now()) {
+		return nil, ErrBolaoEncerrado
+	}
+
+	blo := model.NewBolao(bolao)
+	if err := validarBolao(blo, str); err != nil {
+		return nil, err
+	}
+
+	custo := int64(len(blo.Jogos)) * str.ValorAposta
+	blo.ValorCota = (custo + blo.Cotas - 1) / blo.Cotas
+
+	if _, err := collection.InsertOne(ctx, blo); err != nil {
+		logger.Error("erro salvar Bolao", err)
+		return nil, err
+	}
+
+	for _, numeros := range blo.Jogos {
+		apt, err := bds.apostas.Create(ctx, model.Aposta{
+			SorteioID: blo.SorteioID,
+			ClienteID: blo.OrganizadorID,
+			BolaoID:   blo.ID,
+			Numeros:   numeros,
+		})
+		if err != nil {
+			bds.cancelar(ctx, blo, "jogo recusado: "+err.Error())
+			return nil, err
+		}
+		blo.ApostaIDs = append(blo.ApostaIDs, apt.ID)
+	}
+
+	_, err = collection.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: blo.ID}, {Key: "data_type", Value: "bolao"}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "aposta_ids", Value: blo.ApostaIDs}}}},
+	)
+	if err != nil {
+		logger.Error("erro ao gravar Apostas do Bolao", err)
+		return nil, err
+	}
+
+	return blo, nil
+}
+
+func (bds *BolaoDataService) GetByID(ctx context.Context, ID string) (*model.Bolao, error) {
+	collection := bds.mdb.GetCollection("cfStore")
+
+	bolao := &model.Bolao{}
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		logger.Error("Error to parse ObjectIDFromHex", err)
+		return nil, ErrBolaoNaoEncontrado
+	}
+
+	filter := bson.D{
+		{Key: "data_type", Value: "bolao"},
+		{Key: "_id", Value: objectID},
+	}
+
+	err = collection.FindOne(ctx, filter).Decode(bolao)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrBolaoNaoEncontrado
+		}
+		logger.Error("erro ao consultar Bolao", err)
+		return nil, err
+	}
+
+	return bolao, nil
+}
+
+func (bds *BolaoDataService) GetAll(ctx context.Context, filters model.FilterBolao, limit, page int64) (*model.Paginate, error) {
+	collection := bds.mdb.GetCollection("cfStore")
+
+	query := bson.M{"data_type": "bolao"}
+
+	if filters.SorteioID != "" {
+		sorteioID, err := primitive.ObjectIDFromHex(filters.SorteioID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: sorteio_id", ErrBolaoInvalido)
+		}
+		query["sorteio_id"] = sorteioID
+	}
+	if filters.OrganizadorID != "" {
+		organizadorID, err := primitive.ObjectIDFromHex(filters.OrganizadorID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: organizador_id", ErrBolaoInvalido)
+		}
+		query["organizador_id"] = organizadorID
+	}
+	if filters.Status != "" {
+		query["status"] = filters.Status
+	}
+
+	count, err := collection.CountDocuments(ctx, query, &options.CountOptions{})
+	if err != nil {
+		logger.Error("erro ao consultar todos os Boloes", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+
+	curr, err := collection.Find(ctx, query, pagination.GetPaginatedOpts())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Bolao, 0)
+	for curr.Next(ctx) {
+		blo := &model.Bolao{}
+		if err := curr.Decode(blo); err != nil {
+			logger.Error("erro ao consulta todos os Boloes", err)
+		}
+		result = append(result, blo)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+// ComprarCotas reserva as cotas no bolão com um incremento condicional, de
+// modo que compras concorrentes nunca passam do total, cobra o cliente na
+// carteira em favor do organizador e então grava a compra.
+func (bds *BolaoDataService) ComprarCotas(ctx context.Context, ID string, cota model.CotaBolao) (*model.CotaBolao, error) {
+	collection := bds.mdb.GetCollection("cfStore")
+
+	blo, err := bds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cota.Quantidade < 1 {
+		return nil, fmt.Errorf("%w: compre ao menos uma cota", ErrBolaoInvalido)
+	}
+	if cota.ClienteID.IsZero() {
+		return nil, fmt.Errorf("%w: cliente obrigatório", ErrBolaoInvalido)
+	}
+
+	str, err := bds.sorteios.GetByID(ctx, blo.SorteioID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if blo.Status != model.BolaoAberto || str.Status != model.SorteioAberto || !str.JanelaAberta(time.Now()) {
+		return nil, ErrBolaoEncerrado
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.D{
+		{Key: "data_type", Value: "cliente"},
+		{Key: "_id", Value: cota.ClienteID},
+		{Key: "enabled", Value: true},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar Cliente da Cota", err)
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: cliente não encontrado ou desativado", ErrBolaoInvalido)
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: blo.ID},
+		{Key: "data_type", Value: "bolao"},
+		{Key: "status", Value: model.BolaoAberto},
+		{Key: "$expr", Value: bson.D{{Key: "$lte", Value: bson.A{
+			bson.D{{Key: "$add", Value: bson.A{"$cotas_vendidas", cota.Quantidade}}},
+			"$cotas",
+		}}}},
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "cotas_vendidas", Value: cota.Quantidade}}}}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("erro ao reservar Cotas do Bolao", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("%w: restam %d", ErrCotasEsgotadas, blo.Cotas-blo.CotasVendidas)
+	}
+
+	// Devolve as cotas reservadas para não ficarem presas.
+	devolver := func() {
+		collection.UpdateOne(ctx,
+			bson.D{{Key: "_id", Value: blo.ID}, {Key: "data_type", Value: "bolao"}},
+			bson.D{{Key: "$inc", Value: bson.D{{Key: "cotas_vendidas", Value: -cota.Quantidade}}}},
+		)
+	}
+
+	cta := model.NewCotaBolao(model.CotaBolao{
+		BolaoID:    blo.ID,
+		ClienteID:  cota.ClienteID,
+		Quantidade: cota.Quantidade,
+		Valor:      cota.Quantidade * blo.ValorCota,
+	})
+
+	if _, err := bds.carteira.TransferirCota(ctx, cta, blo); err != nil {
+		devolver()
+		return nil, err
+	}
+
+	if _, err := collection.InsertOne(ctx, cta); err != nil {
+		logger.Error("erro salvar Cota do Bolao", err)
+		if _, err := bds.carteira.Estornar(ctx, model.LancamentoCota, cta.ID.Hex()); err != nil {
+			logger.Error("erro ao estornar pagamento da Cota do Bolao", err)
+		}
+		devolver()
+		return nil, err
+	}
+
+	return cta, nil
+}
+
+func (bds *BolaoDataService) GetCotas(ctx context.Context, ID string) ([]*model.CotaBolao, error) {
+	blo, err := bds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return bds.cotas(ctx, blo.ID)
+}
+
+// Liquidar divide o prêmio das apostas do bolão depois que o sorteio foi
+// liquidado. A taxa do organizador sai primeiro; o restante é dividido entre
+// as compras de cotas e as cotas não vendidas pelos maiores restos, de modo
+// que nenhum centavo se perde. Repetir a liquidação devolve o bolão como está.
+func (bds *BolaoDataService) Liquidar(ctx context.Context, ID string) (*model.Bolao, error) {
+	collection := bds.mdb.GetCollection("cfStore")
+
+	blo, err := bds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch blo.Status {
+	case model.BolaoLiquidado:
+		return blo, nil
+	case model.BolaoCancelado:
+		return nil, ErrBolaoEncerrado
+	}
+
+	str, err := bds.sorteios.GetByID(ctx, blo.SorteioID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if str.Status != model.SorteioLiquidado {
+		return nil, ErrSorteioNaoLiquidado
+	}
+
+	premio, err := bds.premioApostas(ctx, blo)
+	if err != nil {
+		return nil, err
+	}
+
+	cotas, err := bds.cotas(ctx, blo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	taxa := premio * blo.TaxaOrganizador / model.BaseCalculo
+	distribuir := premio - taxa
+
+	// O último peso são as cotas que ficaram com o organizador.
+	pesos := make([]int64, 0, len(cotas)+1)
+	var vendidas int64
+	for _, c := range cotas {
+		pesos = append(pesos, c.Quantidade)
+		vendidas += c.Quantidade
+	}
+	pesos = append(pesos, blo.Cotas-vendidas)
+	partes := rateio.Proporcional(distribuir, pesos)
+
+	if len(cotas) > 0 {
+		lote := make([]mongo.WriteModel, 0, len(cotas))
+		for i, c := range cotas {
+			lote = append(lote, mongo.NewUpdateOneModel().
+				SetFilter(bson.D{{Key: "_id", Value: c.ID}, {Key: "data_type", Value: "cota_bolao"}}).
+				SetUpdate(bson.D{{Key: "$set", Value: bson.D{
+					{Key: "premio", Value: partes[i]},
+					{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+				}}}))
+		}
+		if _, err := collection.BulkWrite(ctx, lote); err != nil {
+			logger.Error("erro ao gravar prêmios das Cotas", err)
+			return nil, err
+		}
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: blo.ID},
+		{Key: "data_type", Value: "bolao"},
+		{Key: "status", Value: model.BolaoAberto},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: model.BolaoLiquidado},
+		{Key: "premio", Value: premio},
+		{Key: "taxa", Value: taxa},
+		{Key: "premio_por_cota", Value: distribuir / blo.Cotas},
+		{Key: "premio_organizador", Value: taxa + partes[len(partes)-1]},
+		{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	result := &model.Bolao{}
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return bds.GetByID(ctx, ID)
+		}
+		logger.Error("erro ao liquidar Bolao", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// LiquidarSorteio liquida os bolões abertos do sorteio. É registrada como
+// etapa posterior à liquidação do sorteio.
+func (bds *BolaoDataService) LiquidarSorteio(ctx context.Context, str *model.Sorteio) error {
+	collection := bds.mdb.GetCollection("cfStore")
+
+	curr, err := collection.Find(ctx, bson.D{
+		{Key: "data_type", Value: "bolao"},
+		{Key: "sorteio_id", Value: str.ID},
+		{Key: "status", Value: model.BolaoAberto},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar Boloes do Sorteio", err)
+		return err
+	}
+	defer curr.Close(ctx)
+
+	var erros []error
+	for curr.Next(ctx) {
+		blo := &model.Bolao{}
+		if err := curr.Decode(blo); err != nil {
+			erros = append(erros, err)
+			continue
+		}
+		if _, err := bds.Liquidar(ctx, blo.ID.Hex()); err != nil {
+			erros = append(erros, fmt.Errorf("bolão %s: %w", blo.ID.Hex(), err))
+		}
+	}
+
+	return errors.Join(erros...)
+}
+
+// premioApostas soma o prêmio gravado nas apostas aceitas do bolão.
+func (bds *BolaoDataService) premioApostas(ctx context.Context, blo *model.Bolao) (int64, error) {
+	collection := bds.mdb.GetCollection("cfStore")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "data_type", Value: "aposta"},
+			{Key: "sorteio_id", Value: blo.SorteioID},
+			{Key: "bolao_id", Value: blo.ID},
+			{Key: "status", Value: model.ApostaAceita},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "premio", Value: bson.D{{Key: "$sum", Value: "$premio"}}},
+		}}},
+	}
+
+	curr, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("erro ao somar prêmios do Bolao", err)
+		return 0, err
+	}
+	defer curr.Close(ctx)
+
+	var total struct {
+		Premio int64 `bson:"premio"`
+	}
+	if curr.Next(ctx) {
+		if err := curr.Decode(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total.Premio, nil
+}
+
+// cotas devolve as compras do bolão em ordem de compra.
+func (bds *BolaoDataService) cotas(ctx context.Context, bolaoID primitive.ObjectID) ([]*model.CotaBolao, error) {
+	collection := bds.mdb.GetCollection("cfStore")
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	curr, err := collection.Find(ctx, bson.D{
+		{Key: "data_type", Value: "cota_bolao"},
+		{Key: "bolao_id", Value: bolaoID},
+	}, opts)
+	if err != nil {
+		logger.Error("erro ao consultar Cotas do Bolao", err)
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]*model.CotaBolao, 0)
+	for curr.Next(ctx) {
+		cta := &model.CotaBolao{}
+		if err := curr.Decode(cta); err != nil {
+			return nil, err
+		}
+		result = append(result, cta)
+	}
+
+	return result, curr.Err()
+}
+
+func (bds *BolaoDataService) cancelar(ctx context.Context, blo *model.Bolao, motivo string) {
+	_, err := bds.mdb.GetCollection("cfStore").UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: blo.ID}, {Key: "data_type", Value: "bolao"}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: model.BolaoCancelado},
+			{Key: "motivo", Value: motivo},
+			{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+		}}},
+	)
+	if err != nil {
+		logger.Error("erro ao cancelar Bolao", err)
+	}
+}
+
+func validarBolao(bolao *model.Bolao, str *model.Sorteio) error {
+	if bolao.Nome == "" {
+		return fmt.Errorf("%w: nome obrigatório", ErrBolaoInvalido)
+	}
+	if bolao.OrganizadorID.IsZero() {
+		return fmt.Errorf("%w: organizador obrigatório", ErrBolaoInvalido)
+	}
+	if len(bolao.Jogos) == 0 || len(bolao.Jogos) > LimiteJogos {
+		return fmt.Errorf("%w: informe de 1 a %d jogos", ErrBolaoInvalido, LimiteJogos)
+	}
+	if bolao.Cotas < 1 || bolao.Cotas > LimiteCotas {
+		return fmt.Errorf("%w: informe de 1 a %d cotas", ErrBolaoInvalido, LimiteCotas)
+	}
+	if bolao.TaxaOrganizador < 0 || bolao.TaxaOrganizador >= model.BaseCalculo {
+		return fmt.Errorf("%w: taxa do organizador deve estar entre 0 e %d", ErrBolaoInvalido, model.BaseCalculo-1)
+	}
+
+	game, err := jogo.Obter(str.Jogo)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBolaoInvalido, err.Error())
+	}
+	if _, ok := game.(*jogo.Rifa); ok {
+		return fmt.Errorf("%w: números de rifa são vendidos por reserva", ErrBolaoInvalido)
+	}
+	for i, numeros := range bolao.Jogos {
+		if err := game.ValidarAposta(numeros); err != nil {
+			return fmt.Errorf("%w: jogo %d: %s", ErrBolaoInvalido, i+1, err.Error())
+		}
+	}
+
+	return nil
+}
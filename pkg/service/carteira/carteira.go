@@ -0,0 +1,622 @@
+package carteira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrSaldoInsuficiente       = errors.New("saldo insuficiente na carteira")
+	ErrLancamentoInvalido      = errors.New("lançamento da carteira inválido")
+	ErrLancamentoNaoEncontrado = errors.New("lançamento não encontrado")
+	ErrLancamentoDivergente    = errors.New("referência já lançada com outras partidas")
+)
+
+type CarteiraServiceInterface interface {
+	Lancar(ctx context.Context, lancamento model.Lancamento) (*model.Lancamento, error)
+	Depositar(ctx context.Context, clienteID primitive.ObjectID, pedido model.PedidoDeposito) (*model.Lancamento, error)
+	DebitarAposta(ctx context.Context, aposta *model.Aposta) (*model.Lancamento, error)
+	TransferirCota(ctx context.Context, cota *model.CotaBolao, bolao *model.Bolao) (*model.Lancamento, error)
+	ProvisionarPremio(ctx context.Context, sorteioID primitive.ObjectID, bruto int64, referencia string) (*model.Lancamento, error)
+	CreditarPremio(ctx context.Context, clienteID primitive.ObjectID, bruto, imposto int64, referencia string) (*model.Lancamento, error)
+	BloquearSaque(ctx context.Context, saque *model.Saque) (*model.Lancamento, error)
+	LiberarSaque(ctx context.Context, saque *model.Saque) (*model.Lancamento, error)
+	Sacar(ctx context.Context, saque *model.Saque) (*model.Lancamento, error)
+	Estornar(ctx context.Context, tipo, referencia string) (*model.Lancamento, error)
+	EstornarSorteio(ctx context.Context, str *model.Sorteio) error
+	Saldo(ctx context.Context, conta string) (*model.SaldoCarteira, error)
+	Extrato(ctx context.Context, conta string, limit, page int64) (*model.Paginate, error)
+	Recalcular(ctx context.Context, conta string) (*model.SaldoCarteira, error)
+}
+
+// CarteiraDataService mantém o livro-razão de partidas dobradas da carteira.
+// Cada movimento é um lançamento imutável gravado na mesma transação que
+// atualiza o saldo guardado de cada conta envolvida.
+type CarteiraDataService struct {
+	mdb mongodb.MongoDBInterface
+}
+
+func NewCarteiraService(mongo_connection mongodb.MongoDBInterface) *CarteiraDataService {
+	cds := &CarteiraDataService{
+		mdb: mongo_connection,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := cds.criarIndices(ctx); err != nil {
+		logger.Error("erro ao criar indices da carteira", err)
+	}
+
+	return cds
+}
+
+// Lancar grava o lançamento e atualiza o saldo das contas numa única
+// transação. Débitos em conta de cliente sem saldo desfazem tudo com
+// ErrSaldoInsuficiente. Um lançamento com o mesmo Tipo e Referencia de outro
+// já gravado devolve o existente sem movimentar nada, se as partidas (contas
+// e valores) forem as mesmas; se não, ErrLancamentoDivergente. Chamado com o
+// contexto de uma transação aberta (mongo.SessionContext), o lançamento entra
+// nela e é desfeito junto se ela abortar; na corrida com outro lançamento
+// igual, a transação do chamador é repetida pelo WithTransaction.
+func (cds *CarteiraDataService) Lancar(ctx context.Context, lancamento model.Lancamento) (*model.Lancamento, error) {
+	if err := validarLancamento(lancamento); err != nil {
+		return nil, err
+	}
+
+	existente, err := cds.lancamento(ctx, lancamento.Tipo, lancamento.Referencia)
+	if err == nil {
+		return conferir(existente, lancamento)
+	}
+	if !errors.Is(err, ErrLancamentoNaoEncontrado) {
+		return nil, err
+	}
+
+	lct := model.NewLancamento(lancamento)
+
+	err = cds.gravar(ctx, lct)
+	if mongo.IsDuplicateKeyError(err) {
+		// Na transação do chamador a chave duplicada já abortou a transação;
+		// reler ou gravar de novo aqui usaria a sessão abortada.
+		if mongo.SessionFromContext(ctx) != nil {
+			return nil, corridaLancamento{err}
+		}
+		// Outra requisição gravou o mesmo movimento ou abriu a mesma conta primeiro.
+		if existente, err := cds.lancamento(ctx, lct.Tipo, lct.Referencia); err == nil {
+			return conferir(existente, lancamento)
+		}
+		err = cds.gravar(ctx, lct)
+	}
+	if err != nil {
+		if !errors.Is(err, ErrSaldoInsuficiente) {
+			logger.Error("erro ao gravar Lancamento da carteira", err)
+		}
+		return nil, err
+	}
+
+	return lct, nil
+}
+
+// corridaLancamento marca como transitória a chave duplicada de um lançamento
+// feito na transação do chamador: o WithTransaction dele refaz a transação e,
+// na nova tentativa, o Lancar encontra o lançamento já gravado.
+type corridaLancamento struct{ error }
+
+func (c corridaLancamento) Unwrap() error { return c.error }
+
+func (c corridaLancamento) HasErrorLabel(label string) bool {
+	return label == "TransientTransactionError"
+}
+
+func (cds *CarteiraDataService) gravar(ctx context.Context, lct *model.Lancamento) error {
+	grava := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		for _, p := range lct.Partidas {
+			if err := cds.movimentar(sessCtx, lct, p); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err := cds.mdb.GetCollection("cfStore").InsertOne(sessCtx, lct)
+		return nil, err
+	}
+
+	if sess := mongo.SessionFromContext(ctx); sess != nil {
+		_, err := grava(mongo.NewSessionContext(ctx, sess))
+		return err
+	}
+
+	_, err := cds.mdb.WithTransaction(ctx, grava)
+	return err
+}
+
+// movimentar aplica a partida ao saldo guardado da conta. A conta da casa é
+// criada no primeiro movimento; a do cliente só pode ser debitada até o saldo.
+func (cds *CarteiraDataService) movimentar(sessCtx mongo.SessionContext, lct *model.Lancamento, p model.Partida) error {
+	collection := cds.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "data_type", Value: "conta_carteira"},
+		{Key: "conta", Value: p.Conta},
+	}
+
+	debito := p.Valor < 0 && model.ContaDeCliente(p.Conta)
+	if debito {
+		filter = append(filter, bson.E{Key: "saldo", Value: bson.D{{Key: "$gte", Value: -p.Valor}}})
+	}
+
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "saldo", Value: p.Valor}}},
+		{Key: "$set", Value: bson.D{
+			{Key: "ultimo_lancamento", Value: lct.ID},
+			{Key: "updated_at", Value: lct.CreatedAt},
+		}},
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "created_at", Value: lct.CreatedAt},
+		}},
+	}
+
+	result, err := collection.UpdateOne(sessCtx, filter, update, options.Update().SetUpsert(!debito))
+	if err != nil {
+		return err
+	}
+	if debito && result.MatchedCount == 0 {
+		return fmt.Errorf("%w: conta %s", ErrSaldoInsuficiente, p.Conta)
+	}
+
+	return nil
+}
+
+func (cds *CarteiraDataService) Depositar(ctx context.Context, clienteID primitive.ObjectID, pedido model.PedidoDeposito) (*model.Lancamento, error) {
+	if pedido.Valor <= 0 {
+		return nil, fmt.Errorf("%w: valor do depósito deve ser positivo", ErrLancamentoInvalido)
+	}
+	if pedido.Taxa < 0 || pedido.Taxa >= pedido.Valor {
+		return nil, fmt.Errorf("%w: taxa do depósito deve ser menor que o valor", ErrLancamentoInvalido)
+	}
+	if pedido.Referencia == "" {
+		return nil, fmt.Errorf("%w: referência do depósito obrigatória", ErrLancamentoInvalido)
+	}
+	if err := cds.clienteAtivo(ctx, clienteID); err != nil {
+		return nil, err
+	}
+
+	partidas := []model.Partida{
+		{Conta: model.ContaCompensacaoDeposito, Valor: -pedido.Valor},
+		{Conta: model.ContaCliente(clienteID), Valor: pedido.Valor - pedido.Taxa},
+	}
+	if pedido.Taxa > 0 {
+		partidas = append(partidas, model.Partida{Conta: model.ContaReceita, Valor: pedido.Taxa})
+	}
+
+	return cds.Lancar(ctx, model.Lancamento{
+		Tipo:       model.LancamentoDeposito,
+		Referencia: pedido.Referencia,
+		Descricao:  pedido.Descricao,
+		Partidas:   partidas,
+	})
+}
+
+// DebitarAposta cobra o valor da aposta do cliente como receita da casa.
+func (cds *CarteiraDataService) DebitarAposta(ctx context.Context, aposta *model.Aposta) (*model.Lancamento, error) {
+	return cds.Lancar(ctx, model.Lancamento{
+		Tipo:       model.LancamentoAposta,
+		Referencia: aposta.ID.Hex(),
+		SorteioID:  aposta.SorteioID,
+		Partidas: []model.Partida{
+			{Conta: model.ContaCliente(aposta.ClienteID), Valor: -aposta.Valor},
+			{Conta: model.ContaReceita, Valor: aposta.Valor},
+		},
+	})
+}
+
+// TransferirCota paga as cotas ao organizador, que já pagou os jogos do bolão.
+func (cds *CarteiraDataService) TransferirCota(ctx context.Context, cota *model.CotaBolao, bolao *model.Bolao) (*model.Lancamento, error) {
+	return cds.Lancar(ctx, model.Lancamento{
+		Tipo:       model.LancamentoCota,
+		Referencia: cota.ID.Hex(),
+		SorteioID:  bolao.SorteioID,
+		Descricao:  bolao.Nome,
+		Partidas: []model.Partida{
+			{Conta: model.ContaCliente(cota.ClienteID), Valor: -cota.Valor},
+			{Conta: model.ContaCliente(bolao.OrganizadorID), Valor: cota.Valor},
+		},
+	})
+}
+
+// ProvisionarPremio reconhece o prêmio devido: sai da receita e vai para os
+// prêmios a pagar, de onde CreditarPremio o paga ao cliente.
+func (cds *CarteiraDataService) ProvisionarPremio(ctx context.Context, sorteioID primitive.ObjectID, bruto int64, referencia string) (*model.Lancamento, error) {
+	if bruto <= 0 {
+		return nil, fmt.Errorf("%w: prêmio deve ser positivo", ErrLancamentoInvalido)
+	}
+
+	return cds.Lancar(ctx, model.Lancamento{
+		Tipo:       model.LancamentoProvisao,
+		Referencia: referencia,
+		SorteioID:  sorteioID,
+		Partidas: []model.Partida{
+			{Conta: model.ContaReceita, Valor: -bruto},
+			{Conta: model.ContaPremiosAPagar, Valor: bruto},
+		},
+	})
+}
+
+// CreditarPremio credita o prêmio líquido ao cliente e separa o imposto
+// retido, ambos saindo dos prêmios a pagar.
+func (cds *CarteiraDataService) CreditarPremio(ctx context.Context, clienteID primitive.ObjectID, bruto, imposto int64, referencia string) (*model.Lancamento, error) {
+	if imposto < 0 || imposto > bruto {
+		return nil, fmt.Errorf("%w: imposto fora do prêmio", ErrLancamentoInvalido)
+	}
+
+	partidas := []model.Partida{
+		{Conta: model.ContaPremiosAPagar, Valor: -bruto},
+		{Conta: model.ContaCliente(clienteID), Valor: bruto - imposto},
+	}
+	if imposto > 0 {
+		partidas = append(partidas, model.Partida{Conta: model.ContaImpostoRetido, Valor: imposto})
+	}
+
+	return cds.Lancar(ctx, model.Lancamento{
+		Tipo:       model.LancamentoPremio,
+		Referencia: referencia,
+		Partidas:   partidas,
+	})
+}
+
+// BloquearSaque tira o valor do saque do saldo do cliente e o segura em
+// ContaSaquesPendentes até o pagamento ou a rejeição.
+func (cds *CarteiraDataService) BloquearSaque(ctx context.Context, saque *model.Saque) (*model.Lancamento, error) {
+	if saque.Valor <= 0 {
+		return nil, fmt.Errorf("%w: valor do saque deve ser positivo", ErrLancamentoInvalido)
+	}
+	if saque.Taxa < 0 || saque.Taxa >= saque.Valor {
+		return nil, fmt.Errorf("%w: taxa do saque deve ser menor que o valor", ErrLancamentoInvalido)
+	}
+
+	return cds.Lancar(ctx, model.Lancamento{
+		Tipo:       model.LancamentoBloqueio,
+		Referencia: saque.ID.Hex(),
+		Descricao:  "Saque Pix para " + saque.Chave,
+		Partidas: []model.Partida{
+			{Conta: model.ContaCliente(saque.ClienteID), Valor: -saque.Valor},
+			{Conta: model.ContaSaquesPendentes, Valor: saque.Valor},
+		},
+	})
+}
+
+// LiberarSaque devolve ao cliente o valor bloqueado de um saque não pago.
+func (cds *CarteiraDataService) LiberarSaque(ctx context.Context, saque *model.Saque) (*model.Lancamento, error) {
+	return cds.Estornar(ctx, model.LancamentoBloqueio, saque.ID.Hex())
+}
+
+// Sacar baixa o valor bloqueado do saque pago para a compensação dos meios
+// de pagamento. A taxa do meio fica com a casa.
+func (cds *CarteiraDataService) Sacar(ctx context.Context, saque *model.Saque) (*model.Lancamento, error) {
+	partidas := []model.Partida{
+		{Conta: model.ContaSaquesPendentes, Valor: -saque.Valor},
+		{Conta: model.ContaCompensacaoDeposito, Valor: saque.Valor - saque.Taxa},
+	}
+	if saque.Taxa > 0 {
+		partidas = append(partidas, model.Partida{Conta: model.ContaReceita, Valor: saque.Taxa})
+	}
+
+	return cds.Lancar(ctx, model.Lancamento{
+		Tipo:       model.LancamentoSaque,
+		Referencia: saque.ID.Hex(),
+		Descricao:  "Saque Pix " + saque.EndToEndID,
+		Partidas:   partidas,
+	})
+}
+
+// Estornar lança as partidas invertidas do lançamento de origem. O estorno
+// referencia o tipo e a referência da origem, então só acontece uma vez.
+func (cds *CarteiraDataService) Estornar(ctx context.Context, tipo, referencia string) (*model.Lancamento, error) {
+	origem, err := cds.lancamento(ctx, tipo, referencia)
+	if err != nil {
+		return nil, err
+	}
+
+	partidas := make([]model.Partida, 0, len(origem.Partidas))
+	for _, p := range origem.Partidas {
+		partidas = append(partidas, model.Partida{Conta: p.Conta, Valor: -p.Valor})
+	}
+
+	return cds.Lancar(ctx, model.Lancamento{
+		Tipo:       model.LancamentoEstorno,
+		Referencia: tipo + ":" + referencia,
+		SorteioID:  origem.SorteioID,
+		Descricao:  "estorno de " + tipo,
+		Partidas:   partidas,
+	})
+}
+
+// EstornarSorteio devolve as apostas e as cotas de bolão de um sorteio
+// cancelado. As apostas vêm primeiro para o organizador do bolão ter saldo ao
+// devolver as cotas. Falhas de um estorno ficam no log e não impedem os demais;
+// repetir a etapa completa só o que faltou.
+func (cds *CarteiraDataService) EstornarSorteio(ctx context.Context, str *model.Sorteio) error {
+	if str.Status != model.SorteioCancelado {
+		return nil
+	}
+
+	collection := cds.mdb.GetCollection("cfStore")
+
+	var falhas int
+	for _, tipo := range []string{model.LancamentoAposta, model.LancamentoCota} {
+		filter := bson.D{
+			{Key: "data_type", Value: "lancamento"},
+			{Key: "sorteio_id", Value: str.ID},
+			{Key: "tipo", Value: tipo},
+		}
+
+		curr, err := collection.Find(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		origens := make([]*model.Lancamento, 0)
+		if err := curr.All(ctx, &origens); err != nil {
+			return err
+		}
+
+		for _, origem := range origens {
+			if _, err := cds.Estornar(ctx, origem.Tipo, origem.Referencia); err != nil {
+				logger.Error("erro ao estornar "+origem.Tipo+" "+origem.Referencia, err)
+				falhas++
+			}
+		}
+	}
+
+	if falhas > 0 {
+		return fmt.Errorf("%d estornos do sorteio %s falharam", falhas, str.ID.Hex())
+	}
+	return nil
+}
+
+// Saldo devolve o saldo guardado da conta; conta sem movimento tem saldo zero.
+func (cds *CarteiraDataService) Saldo(ctx context.Context, conta string) (*model.SaldoCarteira, error) {
+	collection := cds.mdb.GetCollection("cfStore")
+
+	cnt := &model.ContaCarteira{}
+	err := collection.FindOne(ctx, bson.D{
+		{Key: "data_type", Value: "conta_carteira"},
+		{Key: "conta", Value: conta},
+	}).Decode(cnt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &model.SaldoCarteira{Conta: conta}, nil
+		}
+		logger.Error("erro ao consultar Conta da carteira", err)
+		return nil, err
+	}
+
+	return &model.SaldoCarteira{Conta: conta, Saldo: cnt.Saldo}, nil
+}
+
+// Extrato lista os lançamentos da conta, do mais recente para o mais antigo.
+func (cds *CarteiraDataService) Extrato(ctx context.Context, conta string, limit, page int64) (*model.Paginate, error) {
+	collection := cds.mdb.GetCollection("cfStore")
+
+	query := bson.D{
+		{Key: "data_type", Value: "lancamento"},
+		{Key: "partidas.conta", Value: conta},
+	}
+
+	count, err := collection.CountDocuments(ctx, query, &options.CountOptions{})
+	if err != nil {
+		logger.Error("erro ao consultar extrato da carteira", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+	opts := pagination.GetPaginatedOpts().SetSort(bson.D{{Key: "_id", Value: -1}})
+
+	curr, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]model.MovimentoCarteira, 0)
+	for curr.Next(ctx) {
+		lct := &model.Lancamento{}
+		if err := curr.Decode(lct); err != nil {
+			logger.Error("erro ao consultar extrato da carteira", err)
+			continue
+		}
+
+		mov := model.MovimentoCarteira{
+			LancamentoID: lct.ID,
+			Tipo:         lct.Tipo,
+			Referencia:   lct.Referencia,
+			Descricao:    lct.Descricao,
+			CreatedAt:    lct.CreatedAt,
+		}
+		for _, p := range lct.Partidas {
+			if p.Conta == conta {
+				mov.Valor += p.Valor
+			}
+		}
+		result = append(result, mov)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+// Recalcular refaz o saldo guardado da conta somando os lançamentos, na mesma
+// transação, e devolve o saldo anterior para conferência.
+func (cds *CarteiraDataService) Recalcular(ctx context.Context, conta string) (*model.SaldoCarteira, error) {
+	collection := cds.mdb.GetCollection("cfStore")
+
+	result, err := cds.mdb.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		anterior, err := cds.Saldo(sessCtx, conta)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: bson.D{
+				{Key: "data_type", Value: "lancamento"},
+				{Key: "partidas.conta", Value: conta},
+			}}},
+			{{Key: "$unwind", Value: "$partidas"}},
+			{{Key: "$match", Value: bson.D{{Key: "partidas.conta", Value: conta}}}},
+			{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: nil},
+				{Key: "saldo", Value: bson.D{{Key: "$sum", Value: "$partidas.valor"}}},
+			}}},
+		}
+
+		curr, err := collection.Aggregate(sessCtx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+
+		somas := make([]struct {
+			Saldo int64 `bson:"saldo"`
+		}, 0, 1)
+		if err := curr.All(sessCtx, &somas); err != nil {
+			return nil, err
+		}
+
+		saldo := &model.SaldoCarteira{Conta: conta, Anterior: &anterior.Saldo}
+		if len(somas) > 0 {
+			saldo.Saldo = somas[0].Saldo
+		}
+
+		_, err = collection.UpdateOne(sessCtx,
+			bson.D{
+				{Key: "data_type", Value: "conta_carteira"},
+				{Key: "conta", Value: conta},
+			},
+			bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "saldo", Value: saldo.Saldo},
+					{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+				}},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return saldo, nil
+	})
+	if err != nil {
+		logger.Error("erro ao recalcular saldo da carteira", err)
+		return nil, err
+	}
+
+	return result.(*model.SaldoCarteira), nil
+}
+
+func (cds *CarteiraDataService) lancamento(ctx context.Context, tipo, referencia string) (*model.Lancamento, error) {
+	collection := cds.mdb.GetCollection("cfStore")
+
+	lct := &model.Lancamento{}
+	err := collection.FindOne(ctx, bson.D{
+		{Key: "data_type", Value: "lancamento"},
+		{Key: "tipo", Value: tipo},
+		{Key: "referencia", Value: referencia},
+	}).Decode(lct)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrLancamentoNaoEncontrado
+		}
+		logger.Error("erro ao consultar Lancamento da carteira", err)
+		return nil, err
+	}
+
+	return lct, nil
+}
+
+func (cds *CarteiraDataService) clienteAtivo(ctx context.Context, clienteID primitive.ObjectID) error {
+	count, err := cds.mdb.GetCollection("cfStore").CountDocuments(ctx, bson.D{
+		{Key: "data_type", Value: "cliente"},
+		{Key: "_id", Value: clienteID},
+		{Key: "enabled", Value: true},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar Cliente da carteira", err)
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: cliente não encontrado ou desativado", ErrLancamentoInvalido)
+	}
+	return nil
+}
+
+// criarIndices garante uma conta por chave e um lançamento por movimento de
+// origem, o que resolve as corridas entre transações concorrentes.
+func (cds *CarteiraDataService) criarIndices(ctx context.Context) error {
+	collection := cds.mdb.GetCollection("cfStore")
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "conta", Value: 1}},
+			Options: options.Index().
+				SetName("carteira_conta").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "data_type", Value: "conta_carteira"}}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "tipo", Value: 1},
+				{Key: "referencia", Value: 1},
+			},
+			Options: options.Index().
+				SetName("carteira_lancamento_origem").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "data_type", Value: "lancamento"}}),
+		},
+	})
+	return err
+}
+
+// conferir devolve o lançamento já gravado com a mesma origem só se ele
+// movimentou as mesmas contas pelos mesmos valores que o novo pedido.
+func conferir(existente *model.Lancamento, lancamento model.Lancamento) (*model.Lancamento, error) {
+	if !slices.Equal(existente.Partidas, lancamento.Partidas) {
+		logger.Error("lancamento divergente da carteira "+lancamento.Tipo+" "+lancamento.Referencia, ErrLancamentoDivergente)
+		return nil, fmt.Errorf("%w: %s %s", ErrLancamentoDivergente, lancamento.Tipo, lancamento.Referencia)
+	}
+	return existente, nil
+}
+
+func validarLancamento(lancamento model.Lancamento) error {
+	if lancamento.Tipo == "" || lancamento.Referencia == "" {
+		return fmt.Errorf("%w: tipo e referência obrigatórios", ErrLancamentoInvalido)
+	}
+	if len(lancamento.Partidas) < 2 {
+		return fmt.Errorf("%w: ao menos duas partidas", ErrLancamentoInvalido)
+	}
+
+	var soma int64
+	for _, p := range lancamento.Partidas {
+		if p.Conta == "" {
+			return fmt.Errorf("%w: partida sem conta", ErrLancamentoInvalido)
+		}
+		if p.Valor == 0 {
+			return fmt.Errorf("%w: partida de valor zero na conta %s", ErrLancamentoInvalido, p.Conta)
+		}
+		soma += p.Valor
+	}
+	if soma != 0 {
+		return fmt.Errorf("%w: partidas não fecham, diferença de %d centavos", ErrLancamentoInvalido, soma)
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+package carteira
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// mongoTeste entrega a coleção do mtest em modo mock. O WithTransaction só
+// abre uma sessão: as respostas do mock já decidem o resultado de cada escrita.
+type mongoTeste struct {
+	mt *mtest.T
+}
+
+func (m mongoTeste) GetCollection(string) *mongo.Collection       { return m.mt.Coll }
+func (m mongoTeste) GetCollectionByName(string) *mongo.Collection { return m.mt.Coll }
+
+func (m mongoTeste) WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) (interface{}, error)) (interface{}, error) {
+	sess, err := m.mt.Client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.EndSession(ctx)
+	return fn(mongo.NewSessionContext(ctx, sess))
+}
+
+var (
+	clienteTeste = primitive.NewObjectID()
+	naoAchou     = mtest.CreateCursorResponse(0, "fortuna.cfStore", mtest.FirstBatch)
+	escreveu     = mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1})
+	semSaldo     = mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0})
+	inseriu      = mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1})
+	duplicado    = mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key"})
+)
+
+func aposta100() model.Lancamento {
+	return model.Lancamento{
+		Tipo:       model.LancamentoAposta,
+		Referencia: "apt-1",
+		Partidas: []model.Partida{
+			{Conta: model.ContaCliente(clienteTeste), Valor: -100},
+			{Conta: model.ContaReceita, Valor: 100},
+		},
+	}
+}
+
+// achou responde ao FindOne do lançamento com um já gravado.
+func achou(id primitive.ObjectID, lct model.Lancamento) bson.D {
+	partidas := bson.A{}
+	for _, p := range lct.Partidas {
+		partidas = append(partidas, bson.D{{Key: "conta", Value: p.Conta}, {Key: "valor", Value: p.Valor}})
+	}
+	return mtest.CreateCursorResponse(0, "fortuna.cfStore", mtest.FirstBatch, bson.D{
+		{Key: "_id", Value: id},
+		{Key: "data_type", Value: "lancamento"},
+		{Key: "tipo", Value: lct.Tipo},
+		{Key: "referencia", Value: lct.Referencia},
+		{Key: "partidas", Value: partidas},
+	})
+}
+
+func TestLancar(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	gravado := primitive.NewObjectID()
+	outroValor := aposta100()
+	outroValor.Partidas = []model.Partida{
+		{Conta: model.ContaCliente(clienteTeste), Valor: -150},
+		{Conta: model.ContaReceita, Valor: 150},
+	}
+
+	tests := []struct {
+		name       string
+		lancamento func() model.Lancamento
+		respostas  []bson.D
+		naSessao   bool
+		wantErr    error
+		wantID     primitive.ObjectID
+	}{
+		{
+			name: "partidas que não fecham",
+			lancamento: func() model.Lancamento {
+				lct := aposta100()
+				lct.Partidas[1].Valor = 90
+				return lct
+			},
+			wantErr: ErrLancamentoInvalido,
+		},
+		{
+			name: "partida de valor zero",
+			lancamento: func() model.Lancamento {
+				lct := aposta100()
+				lct.Partidas = append(lct.Partidas, model.Partida{Conta: model.ContaImpostoRetido})
+				return lct
+			},
+			wantErr: ErrLancamentoInvalido,
+		},
+		{
+			name: "uma partida só",
+			lancamento: func() model.Lancamento {
+				lct := aposta100()
+				lct.Partidas = lct.Partidas[:1]
+				return lct
+			},
+			wantErr: ErrLancamentoInvalido,
+		},
+		{
+			name: "sem referência",
+			lancamento: func() model.Lancamento {
+				lct := aposta100()
+				lct.Referencia = ""
+				return lct
+			},
+			wantErr: ErrLancamentoInvalido,
+		},
+		{
+			name:       "novo lançamento movimenta as contas",
+			lancamento: aposta100,
+			respostas:  []bson.D{naoAchou, escreveu, escreveu, inseriu},
+		},
+		{
+			name:       "débito acima do saldo do cliente",
+			lancamento: aposta100,
+			respostas:  []bson.D{naoAchou, semSaldo},
+			wantErr:    ErrSaldoInsuficiente,
+		},
+		{
+			name:       "repetição com as mesmas partidas devolve o gravado",
+			lancamento: aposta100,
+			respostas:  []bson.D{achou(gravado, aposta100())},
+			wantID:     gravado,
+		},
+		{
+			name:       "repetição com outras partidas",
+			lancamento: aposta100,
+			respostas:  []bson.D{achou(gravado, outroValor)},
+			wantErr:    ErrLancamentoDivergente,
+		},
+		{
+			name:       "corrida fora de transação relê o gravado",
+			lancamento: aposta100,
+			respostas:  []bson.D{naoAchou, escreveu, escreveu, duplicado, achou(gravado, aposta100())},
+			wantID:     gravado,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tt.respostas...)
+			cds := &CarteiraDataService{mdb: mongoTeste{mt: mt}}
+
+			got, err := cds.Lancar(context.Background(), tt.lancamento())
+			if !errors.Is(err, tt.wantErr) {
+				mt.Fatalf("erro = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !tt.wantID.IsZero() && got.ID != tt.wantID {
+				mt.Errorf("ID = %s, want o lançamento já gravado %s", got.ID.Hex(), tt.wantID.Hex())
+			}
+			if tt.wantID.IsZero() && (got.ID.IsZero() || got.Referencia != "apt-1") {
+				mt.Errorf("Lancar = %+v, want um lançamento novo de apt-1", got)
+			}
+		})
+	}
+}
+
+// TestLancarCorridaNaTransacao confere que a chave duplicada dentro da
+// transação do chamador volta como erro transitório, sem reler nem gravar de
+// novo na sessão abortada, para o WithTransaction dele repetir tudo.
+func TestLancarCorridaNaTransacao(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("chave duplicada", func(mt *mtest.T) {
+		mt.AddMockResponses(naoAchou, escreveu, escreveu, duplicado)
+		cds := &CarteiraDataService{mdb: mongoTeste{mt: mt}}
+
+		sess, err := mt.Client.StartSession()
+		if err != nil {
+			mt.Fatalf("StartSession: %v", err)
+		}
+		defer sess.EndSession(context.Background())
+
+		_, err = cds.Lancar(mongo.NewSessionContext(context.Background(), sess), aposta100())
+		if !mongo.IsDuplicateKeyError(err) {
+			mt.Fatalf("erro = %v, want chave duplicada", err)
+		}
+		var rotulado mongo.LabeledError
+		if !errors.As(err, &rotulado) || !rotulado.HasErrorLabel("TransientTransactionError") {
+			mt.Errorf("erro %v sem o rótulo TransientTransactionError", err)
+		}
+
+		var comandos []string
+		for ev := mt.GetStartedEvent(); ev != nil; ev = mt.GetStartedEvent() {
+			comandos = append(comandos, ev.CommandName)
+		}
+		if want := []string{"find", "update", "update", "insert"}; !slices.Equal(comandos, want) {
+			mt.Errorf("comandos = %v, want %v", comandos, want)
+		}
+	})
+}
+
+// TestMovimentar confere o filtro do débito: a conta do cliente só é
+// debitada até o saldo e nunca criada por um débito; as da casa aceitam
+// saldo negativo e são criadas no primeiro movimento.
+func TestMovimentar(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tests := []struct {
+		name       string
+		partida    model.Partida
+		wantGuarda bool
+		wantUpsert bool
+	}{
+		{"débito do cliente", model.Partida{Conta: model.ContaCliente(clienteTeste), Valor: -100}, true, false},
+		{"crédito do cliente", model.Partida{Conta: model.ContaCliente(clienteTeste), Valor: 100}, false, true},
+		{"débito da casa", model.Partida{Conta: model.ContaReceita, Valor: -100}, false, true},
+		{"crédito da casa", model.Partida{Conta: model.ContaSaquesPendentes, Valor: 100}, false, true},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(escreveu)
+			cds := &CarteiraDataService{mdb: mongoTeste{mt: mt}}
+			lct := model.NewLancamento(aposta100())
+
+			_, err := cds.mdb.WithTransaction(context.Background(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+				return nil, cds.movimentar(sessCtx, lct, tt.partida)
+			})
+			if err != nil {
+				mt.Fatalf("movimentar: %v", err)
+			}
+
+			ev := mt.GetStartedEvent()
+			if ev == nil || ev.CommandName != "update" {
+				mt.Fatalf("comando = %v, want update", ev)
+			}
+			update := ev.Command.Lookup("updates", "0").Document()
+
+			_, err = update.LookupErr("q", "saldo", "$gte")
+			if guarda := err == nil; guarda != tt.wantGuarda {
+				mt.Errorf("filtro com saldo $gte = %v, want %v", guarda, tt.wantGuarda)
+			}
+			if gte, ok := update.Lookup("q", "saldo", "$gte").AsInt64OK(); ok && gte != -tt.partida.Valor {
+				mt.Errorf("saldo $gte = %d, want %d", gte, -tt.partida.Valor)
+			}
+			upsert, _ := update.Lookup("upsert").BooleanOK()
+			if upsert != tt.wantUpsert {
+				mt.Errorf("upsert = %v, want %v", upsert, tt.wantUpsert)
+			}
+			if inc := update.Lookup("u", "$inc", "saldo").Int64(); inc != tt.partida.Valor {
+				mt.Errorf("$inc saldo = %d, want %d", inc, tt.partida.Valor)
+			}
+		})
+	}
+}
+
+func TestPartidasDosLancamentos(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	saque := &model.Saque{ID: primitive.NewObjectID(), ClienteID: clienteTeste, Valor: 5000, Taxa: 150, Chave: "12345678909"}
+	cliente := model.ContaCliente(clienteTeste)
+
+	tests := []struct {
+		name   string
+		lancar func(cds *CarteiraDataService) (*model.Lancamento, error)
+		want   []model.Partida
+	}{
+		{
+			name: "prêmio com IRRF retido",
+			lancar: func(cds *CarteiraDataService) (*model.Lancamento, error) {
+				return cds.CreditarPremio(context.Background(), clienteTeste, 1_000_000, 300_000, "prm-1")
+			},
+			want: []model.Partida{
+				{Conta: model.ContaPremiosAPagar, Valor: -1_000_000},
+				{Conta: cliente, Valor: 700_000},
+				{Conta: model.ContaImpostoRetido, Valor: 300_000},
+			},
+		},
+		{
+			name: "prêmio isento",
+			lancar: func(cds *CarteiraDataService) (*model.Lancamento, error) {
+				return cds.CreditarPremio(context.Background(), clienteTeste, 1000, 0, "prm-2")
+			},
+			want: []model.Partida{
+				{Conta: model.ContaPremiosAPagar, Valor: -1000},
+				{Conta: cliente, Valor: 1000},
+			},
+		},
+		{
+			name: "bloqueio do saque segura o valor cheio",
+			lancar: func(cds *CarteiraDataService) (*model.Lancamento, error) {
+				return cds.BloquearSaque(context.Background(), saque)
+			},
+			want: []model.Partida{
+				{Conta: cliente, Valor: -5000},
+				{Conta: model.ContaSaquesPendentes, Valor: 5000},
+			},
+		},
+		{
+			name: "saque pago deixa a taxa com a casa",
+			lancar: func(cds *CarteiraDataService) (*model.Lancamento, error) {
+				return cds.Sacar(context.Background(), saque)
+			},
+			want: []model.Partida{
+				{Conta: model.ContaSaquesPendentes, Valor: -5000},
+				{Conta: model.ContaCompensacaoDeposito, Valor: 4850},
+				{Conta: model.ContaReceita, Valor: 150},
+			},
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			respostas := []bson.D{naoAchou}
+			for range tt.want {
+				respostas = append(respostas, escreveu)
+			}
+			mt.AddMockResponses(append(respostas, inseriu)...)
+			cds := &CarteiraDataService{mdb: mongoTeste{mt: mt}}
+
+			got, err := tt.lancar(cds)
+			if err != nil {
+				mt.Fatalf("lançamento: %v", err)
+			}
+			if len(got.Partidas) != len(tt.want) {
+				mt.Fatalf("partidas = %v, want %v", got.Partidas, tt.want)
+			}
+			for i := range tt.want {
+				if got.Partidas[i] != tt.want[i] {
+					mt.Errorf("partida %d = %v, want %v", i, got.Partidas[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLancamentosInvalidos(t *testing.T) {
+	cds := &CarteiraDataService{}
+	saque := func(valor, taxa int64) *model.Saque {
+		return &model.Saque{ID: primitive.NewObjectID(), ClienteID: clienteTeste, Valor: valor, Taxa: taxa}
+	}
+
+	tests := []struct {
+		name   string
+		lancar func() (*model.Lancamento, error)
+	}{
+		{"imposto maior que o prêmio", func() (*model.Lancamento, error) {
+			return cds.CreditarPremio(context.Background(), clienteTeste, 100, 101, "prm")
+		}},
+		{"imposto negativo", func() (*model.Lancamento, error) {
+			return cds.CreditarPremio(context.Background(), clienteTeste, 100, -1, "prm")
+		}},
+		{"provisão sem valor", func() (*model.Lancamento, error) {
+			return cds.ProvisionarPremio(context.Background(), primitive.NewObjectID(), 0, "prov")
+		}},
+		{"saque sem valor", func() (*model.Lancamento, error) {
+			return cds.BloquearSaque(context.Background(), saque(0, 0))
+		}},
+		{"taxa do saque igual ao valor", func() (*model.Lancamento, error) {
+			return cds.BloquearSaque(context.Background(), saque(100, 100))
+		}},
+		{"taxa do saque negativa", func() (*model.Lancamento, error) {
+			return cds.BloquearSaque(context.Background(), saque(100, -1))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.lancar(); !errors.Is(err, ErrLancamentoInvalido) {
+				t.Errorf("erro = %v, want %v", err, ErrLancamentoInvalido)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package cliente
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -9,17 +10,22 @@ import (
 	"github.com/katana/fortuna/backend-go/internal/config/logger"
 	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
 	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/validation"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var ErrClienteNaoEncontrado = errors.New("cliente não encontrado")
+
 type ClienteServiceInterface interface {
 	Create(ctx context.Context, Cliente model.Cliente) (*model.Cliente, error)
 	Update(ctx context.Context, ID string, clienteToChange *model.Cliente) (bool, error)
 	GetByID(ctx context.Context, ID string) (*model.Cliente, error)
 	GetAll(ctx context.Context, filters model.FilterCliente, limit, page int64) (*model.Paginate, error)
 	GetByDocumento(ctx context.Context, Documento string) bool
+	GetByUsuario(ctx context.Context, usuarioID primitive.ObjectID) (*model.Cliente, error)
 }
 
 type ClienteDataService struct {
@@ -161,7 +167,7 @@ func (cat *ClienteDataService) GetByDocumento(ctx context.Context, Doc string) b
 
 	// Utilizando o método CountDocuments para verificar a existência
 	filter := bson.D{
-		{Key: "cpf_cnpj", Value: Doc},
+		{Key: "cpf_cnpj", Value: validation.ExtractNumbers(Doc)},
 		{Key: "data_type", Value: "cliente"},
 	}
 	count, err := collection.CountDocuments(ctx, filter)
@@ -173,3 +179,28 @@ func (cat *ClienteDataService) GetByDocumento(ctx context.Context, Doc string) b
 	// Se count for maior que zero, o fornecedor existe
 	return count > 0
 }
+
+// GetByUsuario devolve o cliente ativo ligado ao usuário; havendo mais de um,
+// vale o cadastrado primeiro.
+func (cat *ClienteDataService) GetByUsuario(ctx context.Context, usuarioID primitive.ObjectID) (*model.Cliente, error) {
+	collection := cat.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "data_type", Value: "cliente"},
+		{Key: "user_id", Value: usuarioID},
+		{Key: "enabled", Value: true},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	Cliente := &model.Cliente{}
+	err := collection.FindOne(ctx, filter, opts).Decode(Cliente)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrClienteNaoEncontrado
+		}
+		logger.Error("erro ao consultar Cliente pelo usuario", err)
+		return nil, err
+	}
+
+	return Cliente, nil
+}
@@ -0,0 +1,118 @@
+// Package comprovante emite e confere os comprovantes de aposta. O código de
+// verificação e o payload do QR são assinados com HMAC-SHA256 sobre o
+// segredo do servidor, de modo que um bilhete impresso não pode ser forjado
+// sem ele; a conferência recalcula a assinatura e compara com a aposta gravada.
+package comprovante
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PrefixoQR identifica a versão do payload do QR.
+const PrefixoQR = "FF1"
+
+// alfabeto é o base32 de Crockford, sem I, L, O e U para não confundir na leitura.
+const alfabeto = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var ErrAssinaturaInvalida = errors.New("assinatura do comprovante inválida")
+
+// DadosQR é o conteúdo assinado do QR, com nomes curtos para caber num QR pequeno.
+type DadosQR struct {
+	ApostaID  string `json:"a"`
+	SorteioID string `json:"s"`
+	Numeros   []int  `json:"n"`
+	Valor     int64  `json:"v"`
+	Codigo    string `json:"c"`
+}
+
+// Codigo devolve o código de verificação da aposta, no formato "XXXXX-XXXXX":
+// os primeiros 50 bits do HMAC do id em base32 de Crockford.
+func Codigo(segredo string, apostaID primitive.ObjectID) string {
+	soma := assinar(segredo, "codigo|"+apostaID.Hex())
+	bits := binary.BigEndian.Uint64(soma[:8]) >> 14
+
+	codigo := make([]byte, 10)
+	for i := 9; i >= 0; i-- {
+		codigo[i] = alfabeto[bits&31]
+		bits >>= 5
+	}
+	return string(codigo[:5]) + "-" + string(codigo[5:])
+}
+
+// NormalizarCodigo aceita o código digitado sem hífen, em minúsculas ou com
+// as letras que o base32 de Crockford troca por dígitos.
+func NormalizarCodigo(codigo string) string {
+	codigo = strings.ToUpper(strings.TrimSpace(codigo))
+	codigo = strings.NewReplacer("-", "", " ", "", "O", "0", "I", "1", "L", "1").Replace(codigo)
+	if len(codigo) != 10 {
+		return codigo
+	}
+	return codigo[:5] + "-" + codigo[5:]
+}
+
+// GerarQR monta o payload "FF1.<dados>.<assinatura>", ambos em base64url.
+func GerarQR(segredo string, apt *model.Aposta) string {
+	dados, _ := json.Marshal(DadosQR{
+		ApostaID:  apt.ID.Hex(),
+		SorteioID: apt.SorteioID.Hex(),
+		Numeros:   apt.Numeros,
+		Valor:     apt.Valor,
+		Codigo:    apt.Codigo,
+	})
+
+	corpo := PrefixoQR + "." + base64.RawURLEncoding.EncodeToString(dados)
+	return corpo + "." + base64.RawURLEncoding.EncodeToString(assinar(segredo, corpo))
+}
+
+// LerQR confere a assinatura do payload e devolve os dados.
+func LerQR(segredo, payload string) (*DadosQR, error) {
+	partes := strings.Split(payload, ".")
+	if len(partes) != 3 || partes[0] != PrefixoQR {
+		return nil, ErrAssinaturaInvalida
+	}
+
+	assinatura, err := base64.RawURLEncoding.DecodeString(partes[2])
+	if err != nil || !hmac.Equal(assinatura, assinar(segredo, partes[0]+"."+partes[1])) {
+		return nil, ErrAssinaturaInvalida
+	}
+
+	dados, err := base64.RawURLEncoding.DecodeString(partes[1])
+	if err != nil {
+		return nil, ErrAssinaturaInvalida
+	}
+
+	result := &DadosQR{}
+	if err := json.Unmarshal(dados, result); err != nil {
+		return nil, ErrAssinaturaInvalida
+	}
+	return result, nil
+}
+
+// Confere diz se os dados do QR são os da aposta gravada.
+func (d *DadosQR) Confere(apt *model.Aposta) bool {
+	if d.ApostaID != apt.ID.Hex() || d.SorteioID != apt.SorteioID.Hex() ||
+		d.Valor != apt.Valor || d.Codigo != apt.Codigo || len(d.Numeros) != len(apt.Numeros) {
+		return false
+	}
+	for i, n := range d.Numeros {
+		if apt.Numeros[i] != n {
+			return false
+		}
+	}
+	return true
+}
+
+func assinar(segredo, mensagem string) []byte {
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write([]byte(mensagem))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,86 @@
+package comprovante
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/skip2/go-qrcode"
+)
+
+// PDF desenha o comprovante numa bobina de 80 mm, o papel das impressoras
+// térmicas dos revendedores, com o QR ao final.
+func PDF(c *model.Comprovante) ([]byte, error) {
+	png, err := qrcode.Encode(c.QR, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "mm",
+		Size:    fpdf.SizeType{Wd: 80, Ht: 160},
+	})
+	pdf.SetMargins(5, 5, 5)
+	pdf.SetAutoPageBreak(false, 5)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 7, tr("Comprovante de aposta"), "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	linha := func(rotulo, valor string) {
+		pdf.CellFormat(22, 5, tr(rotulo), "", 0, "L", false, 0, "")
+		pdf.MultiCell(0, 5, tr(valor), "", "L", false)
+	}
+	linha("Sorteio", fmt.Sprintf("%s nº %d", c.Sorteio, c.Concurso))
+	linha("Jogo", c.Jogo)
+	if !c.DataSorteio.IsZero() {
+		linha("Data", c.DataSorteio.Format("02/01/2006 15:04"))
+	}
+	linha("Aposta", c.ApostaID.Hex())
+	linha("Emitida", c.CriadaEm)
+	linha("Valor", Reais(c.Valor))
+
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "B", 14)
+	numeros := make([]string, len(c.Numeros))
+	for i, n := range c.Numeros {
+		numeros[i] = fmt.Sprintf("%02d", n)
+	}
+	pdf.MultiCell(0, 7, strings.Join(numeros, "  "), "", "C", false)
+
+	pdf.Ln(2)
+	pdf.SetFont("Courier", "B", 14)
+	pdf.CellFormat(0, 7, c.Codigo, "", 1, "C", false, 0, "")
+
+	pdf.RegisterImageOptionsReader("qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	pdf.ImageOptions("qr", 17.5, pdf.GetY()+2, 45, 45, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetY(pdf.GetY() + 49)
+
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.MultiCell(0, 4, tr("Confira em /api/v1/aposta/verify/<código>. Guarde este comprovante para receber o prêmio."), "", "C", false)
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Reais formata centavos como "R$ 1.234,56".
+func Reais(centavos int64) string {
+	sinal := ""
+	if centavos < 0 {
+		sinal = "-"
+		centavos = -centavos
+	}
+
+	inteiro := fmt.Sprint(centavos / 100)
+	for i := len(inteiro) - 3; i > 0; i -= 3 {
+		inteiro = inteiro[:i] + "." + inteiro[i:]
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sinal, inteiro, centavos%100)
+}
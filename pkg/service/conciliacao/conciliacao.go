@@ -0,0 +1,401 @@
+package conciliacao
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/conciliacao/extrato"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrExtratoVazio             = errors.New("extrato sem lançamentos")
+	ErrConciliacaoNaoEncontrada = errors.New("conciliação não encontrada")
+)
+
+// folga é quanto a busca dos registros se estende além do período do
+// extrato, para o PSP que liquida no dia seguinte ao do sistema.
+const folga = 24 * time.Hour
+
+type ConciliacaoServiceInterface interface {
+	Conciliar(ctx context.Context, arquivo, formato string, r io.Reader, usuario string) (*model.Conciliacao, error)
+	GetByID(ctx context.Context, ID string) (*model.Conciliacao, error)
+	GetAll(ctx context.Context, filters model.FilterConciliacao, limit, page int64) (*model.Paginate, error)
+}
+
+// ConciliacaoDataService confere o extrato de liquidação do PSP com os
+// pagamentos das cobranças Pix (créditos) e os saques pagos (débitos).
+type ConciliacaoDataService struct {
+	mdb mongodb.MongoDBInterface
+}
+
+func NewConciliacaoService(mongo_connection mongodb.MongoDBInterface) *ConciliacaoDataService {
+	return &ConciliacaoDataService{
+		mdb: mongo_connection,
+	}
+}
+
+// registro é um depósito ou saque do sistema candidato a conciliação.
+type registro struct {
+	tipo       string
+	id         string
+	endToEndID string
+	txid       string
+	valor      int64
+	data       time.Time
+	usado      bool
+}
+
+// Conciliar lê o extrato e casa cada linha com um registro do sistema, pelo
+// EndToEndID, pelo txid ou, na falta deles, pelo valor no mesmo dia. A
+// execução é gravada com o resultado de cada item.
+func (cds *ConciliacaoDataService) Conciliar(ctx context.Context, arquivo, formato string, r io.Reader, usuario string) (*model.Conciliacao, error) {
+	if formato == "" {
+		formato = extrato.Formato(arquivo)
+	}
+
+	linhas, err := extrato.Ler(formato, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(linhas) == 0 {
+		return nil, ErrExtratoVazio
+	}
+
+	inicio, fim := periodo(linhas)
+
+	registros, err := cds.depositos(ctx, inicio.Add(-folga), fim.Add(folga))
+	if err != nil {
+		return nil, err
+	}
+	saques, err := cds.saques(ctx, inicio.Add(-folga), fim.Add(folga))
+	if err != nil {
+		return nil, err
+	}
+	registros = append(registros, saques...)
+
+	itens := conciliar(linhas, registros)
+	for _, reg := range registros {
+		if reg.usado || reg.data.Before(inicio) || reg.data.After(fim) {
+			continue
+		}
+		itens = append(itens, model.ItemConciliacao{
+			Status:       model.ItemAusente,
+			Tipo:         reg.tipo,
+			EndToEndID:   reg.endToEndID,
+			TxID:         reg.txid,
+			Registro:     reg.id,
+			ValorSistema: reg.valor,
+			Data:         reg.data,
+		})
+	}
+
+	cnc := model.NewConciliacao(model.Conciliacao{
+		Arquivo: arquivo,
+		Formato: formato,
+		Usuario: usuario,
+		Inicio:  inicio,
+		Fim:     fim,
+		Linhas:  len(linhas),
+		Itens:   itens,
+	})
+
+	if _, err := cds.mdb.GetCollection("cfStore").InsertOne(ctx, cnc); err != nil {
+		logger.Error("erro ao gravar conciliacao", err)
+		return nil, err
+	}
+
+	return cnc, nil
+}
+
+func (cds *ConciliacaoDataService) GetByID(ctx context.Context, ID string) (*model.Conciliacao, error) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return nil, ErrConciliacaoNaoEncontrada
+	}
+
+	cnc := &model.Conciliacao{}
+	err = cds.mdb.GetCollection("cfStore").FindOne(ctx, bson.D{
+		{Key: "data_type", Value: "conciliacao"},
+		{Key: "_id", Value: objectID},
+	}).Decode(cnc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrConciliacaoNaoEncontrada
+		}
+		logger.Error("erro ao consultar conciliacao", err)
+		return nil, err
+	}
+
+	return cnc, nil
+}
+
+// GetAll lista as execuções da mais nova para a mais antiga, só com o
+// resumo; os itens ficam no GetByID.
+func (cds *ConciliacaoDataService) GetAll(ctx context.Context, filters model.FilterConciliacao, limit, page int64) (*model.Paginate, error) {
+	collection := cds.mdb.GetCollection("cfStore")
+
+	query := bson.M{"data_type": "conciliacao"}
+	if filters.Usuario != "" {
+		query["usuario"] = filters.Usuario
+	}
+
+	count, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		logger.Error("erro ao consultar todas as conciliacoes", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+
+	opts := pagination.GetPaginatedOpts().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetProjection(bson.D{{Key: "itens", Value: 0}})
+	curr, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]*model.Conciliacao, 0)
+	for curr.Next(ctx) {
+		cnc := &model.Conciliacao{}
+		if err := curr.Decode(cnc); err != nil {
+			logger.Error("erro ao consultar todas as conciliacoes", err)
+			continue
+		}
+		result = append(result, cnc)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+// depositos devolve cada pagamento de cobrança Pix confirmado no período.
+func (cds *ConciliacaoDataService) depositos(ctx context.Context, de, ate time.Time) ([]*registro, error) {
+	filter := bson.D{
+		{Key: "data_type", Value: "cobranca_pix"},
+		{Key: "pagamentos.horario", Value: bson.D{{Key: "$gte", Value: de}, {Key: "$lte", Value: ate}}},
+	}
+
+	curr, err := cds.mdb.GetCollection("cfStore").Find(ctx, filter)
+	if err != nil {
+		logger.Error("erro ao consultar depositos da conciliacao", err)
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	registros := make([]*registro, 0)
+	for curr.Next(ctx) {
+		cob := &model.CobrancaPix{}
+		if err := curr.Decode(cob); err != nil {
+			logger.Error("erro ao consultar depositos da conciliacao", err)
+			continue
+		}
+		for _, pag := range cob.Pagamentos {
+			if pag.Horario.Before(de) || pag.Horario.After(ate) {
+				continue
+			}
+			registros = append(registros, &registro{
+				tipo:       model.ConciliacaoDeposito,
+				id:         cob.ID.Hex(),
+				endToEndID: pag.EndToEndID,
+				txid:       cob.TxID,
+				valor:      pag.Valor,
+				data:       pag.Horario,
+			})
+		}
+	}
+
+	return registros, curr.Err()
+}
+
+// saques devolve os saques pagos no período, pelo valor enviado ao
+// recebedor, que é o que sai no extrato.
+func (cds *ConciliacaoDataService) saques(ctx context.Context, de, ate time.Time) ([]*registro, error) {
+	filter := bson.D{
+		{Key: "data_type", Value: "saque"},
+		{Key: "status", Value: model.SaquePago},
+		{Key: "pago_em", Value: bson.D{{Key: "$gte", Value: de}, {Key: "$lte", Value: ate}}},
+	}
+
+	curr, err := cds.mdb.GetCollection("cfStore").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "pago_em", Value: 1}}))
+	if err != nil {
+		logger.Error("erro ao consultar saques da conciliacao", err)
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	registros := make([]*registro, 0)
+	for curr.Next(ctx) {
+		saq := &model.Saque{}
+		if err := curr.Decode(saq); err != nil {
+			logger.Error("erro ao consultar saques da conciliacao", err)
+			continue
+		}
+		registros = append(registros, &registro{
+			tipo:       model.ConciliacaoSaque,
+			id:         saq.ID.Hex(),
+			endToEndID: saq.EndToEndID,
+			txid:       saq.ID.Hex(),
+			valor:      saq.Valor - saq.Taxa,
+			data:       saq.PagoEm,
+		})
+	}
+
+	return registros, curr.Err()
+}
+
+// conciliar casa as linhas com os registros, na ordem do extrato, marcando
+// os registros usados. Um EndToEndID repetido no extrato, ou um txid cujos
+// pagamentos já foram todos casados, é duplicidade.
+func conciliar(linhas []extrato.Linha, registros []*registro) []model.ItemConciliacao {
+	vistos := make(map[string]*registro)
+	itens := make([]model.ItemConciliacao, 0, len(linhas))
+
+	for _, linha := range linhas {
+		tipo := model.ConciliacaoDeposito
+		if linha.Tipo == extrato.Debito {
+			tipo = model.ConciliacaoSaque
+		}
+
+		item := model.ItemConciliacao{
+			Status:       model.ItemSemRegistro,
+			Tipo:         tipo,
+			Linha:        linha.Numero,
+			EndToEndID:   linha.EndToEndID,
+			TxID:         linha.TxID,
+			ValorExtrato: linha.Valor,
+			Data:         linha.Data,
+		}
+
+		if anterior, ok := vistos[linha.EndToEndID]; ok && linha.EndToEndID != "" {
+			item.Status = model.ItemDuplicado
+			if anterior != nil {
+				item.Registro = anterior.id
+			}
+			itens = append(itens, item)
+			continue
+		}
+
+		reg, duplicado := casar(linha, tipo, registros)
+		if linha.EndToEndID != "" {
+			vistos[linha.EndToEndID] = reg
+		}
+
+		switch {
+		case duplicado:
+			item.Status = model.ItemDuplicado
+			item.Registro = reg.id
+		case reg != nil:
+			reg.usado = true
+			item.Status = model.ItemConciliado
+			if reg.valor != linha.Valor {
+				item.Status = model.ItemDivergente
+			}
+			item.Registro = reg.id
+			item.ValorSistema = reg.valor
+			if item.EndToEndID == "" {
+				item.EndToEndID = reg.endToEndID
+			}
+			if item.TxID == "" {
+				item.TxID = reg.txid
+			}
+		}
+
+		itens = append(itens, item)
+	}
+
+	return itens
+}
+
+// casar procura o registro da linha. Informa duplicidade quando o
+// EndToEndID ou o txid aponta só para registros já casados.
+func casar(linha extrato.Linha, tipo string, registros []*registro) (*registro, bool) {
+	if linha.EndToEndID != "" {
+		for _, reg := range registros {
+			if reg.tipo == tipo && reg.endToEndID == linha.EndToEndID {
+				return reg, reg.usado
+			}
+		}
+	}
+
+	if linha.TxID != "" {
+		var usado, livre *registro
+		for _, reg := range registros {
+			if reg.tipo != tipo || reg.txid != linha.TxID {
+				continue
+			}
+			if reg.usado {
+				usado = reg
+				continue
+			}
+			// O QR estático tem vários pagamentos; prefere o de mesmo valor.
+			if livre == nil || (livre.valor != linha.Valor && reg.valor == linha.Valor) {
+				livre = reg
+			}
+		}
+		if livre != nil {
+			return livre, false
+		}
+		if usado != nil {
+			return usado, true
+		}
+	}
+
+	// Sem identificador que case, vale o mesmo valor no mesmo dia, o mais
+	// próximo do horário do extrato.
+	candidatos := make([]*registro, 0)
+	for _, reg := range registros {
+		if reg.tipo == tipo && !reg.usado && reg.valor == linha.Valor && mesmoDia(reg.data, linha.Data) {
+			candidatos = append(candidatos, reg)
+		}
+	}
+	if len(candidatos) == 0 {
+		return nil, false
+	}
+	sort.Slice(candidatos, func(i, j int) bool {
+		return distancia(candidatos[i].data, linha.Data) < distancia(candidatos[j].data, linha.Data)
+	})
+	return candidatos[0], false
+}
+
+// periodo vai do início do dia da primeira linha ao fim do dia da última.
+func periodo(linhas []extrato.Linha) (time.Time, time.Time) {
+	inicio, fim := linhas[0].Data, linhas[0].Data
+	for _, linha := range linhas[1:] {
+		if linha.Data.Before(inicio) {
+			inicio = linha.Data
+		}
+		if linha.Data.After(fim) {
+			fim = linha.Data
+		}
+	}
+
+	inicio = time.Date(inicio.Year(), inicio.Month(), inicio.Day(), 0, 0, 0, 0, inicio.Location())
+	fim = time.Date(fim.Year(), fim.Month(), fim.Day(), 23, 59, 59, 0, fim.Location())
+	return inicio, fim
+}
+
+// mesmoDia compara as datas no fuso do extrato.
+func mesmoDia(sistema, linha time.Time) bool {
+	a, b := sistema.In(linha.Location()), linha
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+func distancia(a, b time.Time) time.Duration {
+	if d := a.Sub(b); d >= 0 {
+		return d
+	}
+	return b.Sub(a)
+}
@@ -0,0 +1,189 @@
+package conciliacao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/conciliacao/extrato"
+)
+
+var (
+	brt   = time.FixedZone("BRT", -3*3600)
+	manha = time.Date(2024, 1, 2, 9, 0, 0, 0, brt)
+)
+
+// registrosTeste monta um conjunto novo a cada caso, já que os casos e o
+// conciliar marcam registros como usados.
+func registrosTeste() []*registro {
+	return []*registro{
+		{tipo: model.ConciliacaoDeposito, id: "dep-e2e", endToEndID: "E0001", txid: "DEP1", valor: 1000, data: manha},
+		{tipo: model.ConciliacaoDeposito, id: "qr-500", txid: "QR", valor: 500, data: manha},
+		{tipo: model.ConciliacaoDeposito, id: "qr-1000", txid: "QR", valor: 1000, data: manha},
+		{tipo: model.ConciliacaoDeposito, id: "dep-10h", valor: 2500, data: manha.Add(time.Hour)},
+		{tipo: model.ConciliacaoDeposito, id: "dep-15h", valor: 2500, data: manha.Add(6 * time.Hour)},
+		{tipo: model.ConciliacaoSaque, id: "saq-e2e", endToEndID: "E0009", txid: "SAQ1", valor: 3000, data: manha},
+	}
+}
+
+func TestCasar(t *testing.T) {
+	tests := []struct {
+		name          string
+		linha         extrato.Linha
+		tipo          string
+		usados        []string
+		wantID        string
+		wantDuplicado bool
+	}{
+		{
+			name:   "pelo EndToEndID",
+			linha:  extrato.Linha{EndToEndID: "E0001", Valor: 1000, Data: manha},
+			tipo:   model.ConciliacaoDeposito,
+			wantID: "dep-e2e",
+		},
+		{
+			name:   "EndToEndID casa mesmo com valor diferente",
+			linha:  extrato.Linha{EndToEndID: "E0001", Valor: 999, Data: manha},
+			tipo:   model.ConciliacaoDeposito,
+			wantID: "dep-e2e",
+		},
+		{
+			name:          "EndToEndID já casado é duplicidade",
+			linha:         extrato.Linha{EndToEndID: "E0001", Valor: 1000, Data: manha},
+			tipo:          model.ConciliacaoDeposito,
+			usados:        []string{"dep-e2e"},
+			wantID:        "dep-e2e",
+			wantDuplicado: true,
+		},
+		{
+			name:  "EndToEndID de outro sentido não casa",
+			linha: extrato.Linha{EndToEndID: "E0009", Valor: 3000, Data: manha},
+			tipo:  model.ConciliacaoDeposito,
+		},
+		{
+			name:   "EndToEndID desconhecido cai no txid",
+			linha:  extrato.Linha{EndToEndID: "E7777", TxID: "DEP1", Valor: 1000, Data: manha},
+			tipo:   model.ConciliacaoDeposito,
+			wantID: "dep-e2e",
+		},
+		{
+			name:   "txid do QR estático prefere o pagamento de mesmo valor",
+			linha:  extrato.Linha{TxID: "QR", Valor: 1000, Data: manha},
+			tipo:   model.ConciliacaoDeposito,
+			wantID: "qr-1000",
+		},
+		{
+			name:   "txid sem pagamento de mesmo valor fica com o primeiro livre",
+			linha:  extrato.Linha{TxID: "QR", Valor: 700, Data: manha},
+			tipo:   model.ConciliacaoDeposito,
+			wantID: "qr-500",
+		},
+		{
+			name:   "txid pula os pagamentos já casados",
+			linha:  extrato.Linha{TxID: "QR", Valor: 1000, Data: manha},
+			tipo:   model.ConciliacaoDeposito,
+			usados: []string{"qr-1000"},
+			wantID: "qr-500",
+		},
+		{
+			name:          "txid com todos os pagamentos casados é duplicidade",
+			linha:         extrato.Linha{TxID: "QR", Valor: 1000, Data: manha},
+			tipo:          model.ConciliacaoDeposito,
+			usados:        []string{"qr-500", "qr-1000"},
+			wantID:        "qr-1000",
+			wantDuplicado: true,
+		},
+		{
+			name:   "saque pelo txid",
+			linha:  extrato.Linha{TxID: "SAQ1", Valor: 3000, Data: manha},
+			tipo:   model.ConciliacaoSaque,
+			wantID: "saq-e2e",
+		},
+		{
+			name:   "sem identificador, mesmo valor no horário mais próximo",
+			linha:  extrato.Linha{Valor: 2500, Data: manha.Add(5 * time.Hour)},
+			tipo:   model.ConciliacaoDeposito,
+			wantID: "dep-15h",
+		},
+		{
+			name:   "sem identificador, pula o já casado",
+			linha:  extrato.Linha{Valor: 2500, Data: manha.Add(5 * time.Hour)},
+			tipo:   model.ConciliacaoDeposito,
+			usados: []string{"dep-15h"},
+			wantID: "dep-10h",
+		},
+		{
+			name:   "mesmo dia no fuso do extrato",
+			linha:  extrato.Linha{Valor: 2500, Data: time.Date(2024, 1, 2, 23, 0, 0, 0, brt)},
+			tipo:   model.ConciliacaoDeposito,
+			wantID: "dep-15h",
+		},
+		{
+			name:  "sem identificador em outro dia não casa",
+			linha: extrato.Linha{Valor: 2500, Data: manha.AddDate(0, 0, 1)},
+			tipo:  model.ConciliacaoDeposito,
+		},
+		{
+			name:  "sem identificador com outro valor não casa",
+			linha: extrato.Linha{Valor: 2501, Data: manha},
+			tipo:  model.ConciliacaoDeposito,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registros := registrosTeste()
+			for _, reg := range registros {
+				for _, id := range tt.usados {
+					if reg.id == id {
+						reg.usado = true
+					}
+				}
+			}
+
+			reg, duplicado := casar(tt.linha, tt.tipo, registros)
+			gotID := ""
+			if reg != nil {
+				gotID = reg.id
+			}
+			if gotID != tt.wantID || duplicado != tt.wantDuplicado {
+				t.Errorf("casar = %q, %v, want %q, %v", gotID, duplicado, tt.wantID, tt.wantDuplicado)
+			}
+		})
+	}
+}
+
+func TestConciliar(t *testing.T) {
+	linhas := []extrato.Linha{
+		{Numero: 2, EndToEndID: "E0001", Tipo: extrato.Credito, Valor: 1000, Data: manha},
+		{Numero: 3, EndToEndID: "E0001", Tipo: extrato.Credito, Valor: 1000, Data: manha},
+		{Numero: 4, TxID: "QR", Tipo: extrato.Credito, Valor: 700, Data: manha},
+		{Numero: 5, EndToEndID: "E0009", Tipo: extrato.Debito, Valor: 3000, Data: manha},
+		{Numero: 6, EndToEndID: "E8888", Tipo: extrato.Credito, Valor: 4200, Data: manha},
+	}
+
+	tests := []struct {
+		linha        int
+		status       string
+		registro     string
+		valorSistema int64
+	}{
+		{2, model.ItemConciliado, "dep-e2e", 1000},
+		{3, model.ItemDuplicado, "dep-e2e", 0},
+		{4, model.ItemDivergente, "qr-500", 500},
+		{5, model.ItemConciliado, "saq-e2e", 3000},
+		{6, model.ItemSemRegistro, "", 0},
+	}
+
+	itens := conciliar(linhas, registrosTeste())
+	if len(itens) != len(tests) {
+		t.Fatalf("%d itens, want %d", len(itens), len(tests))
+	}
+	for i, tt := range tests {
+		item := itens[i]
+		if item.Linha != tt.linha || item.Status != tt.status || item.Registro != tt.registro || item.ValorSistema != tt.valorSistema {
+			t.Errorf("item %d = linha %d %s %q %d, want linha %d %s %q %d", i,
+				item.Linha, item.Status, item.Registro, item.ValorSistema,
+				tt.linha, tt.status, tt.registro, tt.valorSistema)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+// Package extrato lê os arquivos de liquidação do PSP, em CSV ou OFX, como
+// uma lista de lançamentos para a conciliação.
+package extrato
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatos de arquivo aceitos.
+const (
+	FormatoCSV = "csv"
+	FormatoOFX = "ofx"
+)
+
+// Sentido do lançamento no extrato do PSP.
+const (
+	Credito = "credito"
+	Debito  = "debito"
+)
+
+var (
+	ErrFormatoDesconhecido = errors.New("formato de extrato não suportado")
+	ErrExtratoInvalido     = errors.New("extrato inválido")
+)
+
+// Linha é um lançamento do extrato. Numero é a linha no CSV ou a ordem da
+// transação no OFX, a partir de 1. Valor em centavos, sempre positivo; o
+// sentido fica em Tipo.
+type Linha struct {
+	Numero     int       `json:"numero"`
+	EndToEndID string    `json:"end_to_end_id,omitempty"`
+	TxID       string    `json:"txid,omitempty"`
+	Tipo       string    `json:"tipo"`
+	Valor      int64     `json:"valor"`
+	Data       time.Time `json:"data"`
+	Descricao  string    `json:"descricao,omitempty"`
+}
+
+// Formato deduz o formato pela extensão do arquivo.
+func Formato(arquivo string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(arquivo)), ".")
+}
+
+// Ler lê o extrato no formato informado.
+func Ler(formato string, r io.Reader) ([]Linha, error) {
+	switch strings.ToLower(formato) {
+	case FormatoCSV:
+		return LerCSV(r)
+	case FormatoOFX:
+		return LerOFX(r)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrFormatoDesconhecido, formato)
+}
+
+// colunasCSV mapeia os cabeçalhos aceitos para cada campo.
+var colunasCSV = map[string]string{
+	"end_to_end_id": "e2e",
+	"endtoendid":    "e2e",
+	"e2e":           "e2e",
+	"e2e_id":        "e2e",
+	"txid":          "txid",
+	"tipo":          "tipo",
+	"valor":         "valor",
+	"data":          "data",
+	"horario":       "data",
+	"descricao":     "descricao",
+}
+
+// LerCSV lê um CSV com cabeçalho, separado por vírgula ou ponto e vírgula.
+// Colunas: valor e data obrigatórias; end_to_end_id, txid, tipo e descricao
+// opcionais. Sem tipo, valor negativo é débito. Valores aceitam "10.50" ou
+// "10,50"; datas em RFC3339, "2006-01-02" ou "02/01/2006".
+func LerCSV(r io.Reader) ([]Linha, error) {
+	br := bufio.NewReader(r)
+	cabecalho, err := br.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	primeira, _, _ := bytes.Cut(cabecalho, []byte("\n"))
+
+	leitor := csv.NewReader(br)
+	leitor.FieldsPerRecord = -1
+	leitor.TrimLeadingSpace = true
+	if bytes.Count(primeira, []byte(";")) > bytes.Count(primeira, []byte(",")) {
+		leitor.Comma = ';'
+	}
+
+	// O csv pula linhas em branco; a linha de cada registro vem do próprio
+	// leitor para o número reportado bater com o arquivo.
+	registros := make([][]string, 0)
+	numeros := make([]int, 0)
+	for {
+		registro, err := leitor.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrExtratoInvalido, err)
+		}
+		linha, _ := leitor.FieldPos(0)
+		registros = append(registros, registro)
+		numeros = append(numeros, linha)
+	}
+	if len(registros) == 0 {
+		return nil, fmt.Errorf("%w: arquivo vazio", ErrExtratoInvalido)
+	}
+
+	indice := make(map[string]int)
+	for i, nome := range registros[0] {
+		nome = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(nome, "\ufeff")))
+		if campo, ok := colunasCSV[nome]; ok {
+			indice[campo] = i
+		}
+	}
+	if _, ok := indice["valor"]; !ok {
+		return nil, fmt.Errorf("%w: coluna valor obrigatória", ErrExtratoInvalido)
+	}
+	if _, ok := indice["data"]; !ok {
+		return nil, fmt.Errorf("%w: coluna data obrigatória", ErrExtratoInvalido)
+	}
+
+	campo := func(registro []string, nome string) string {
+		i, ok := indice[nome]
+		if !ok || i >= len(registro) {
+			return ""
+		}
+		return strings.TrimSpace(registro[i])
+	}
+
+	linhas := make([]Linha, 0, len(registros)-1)
+	for n, registro := range registros[1:] {
+		numero := numeros[n+1]
+		if len(registro) == 1 && strings.TrimSpace(registro[0]) == "" {
+			continue
+		}
+
+		valor, negativo, err := lerValor(campo(registro, "valor"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: linha %d: %v", ErrExtratoInvalido, numero, err)
+		}
+		data, err := lerData(campo(registro, "data"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: linha %d: %v", ErrExtratoInvalido, numero, err)
+		}
+
+		tipo := Credito
+		switch strings.ToLower(campo(registro, "tipo")) {
+		case "", "credito", "crédito", "c", "credit":
+			if negativo {
+				tipo = Debito
+			}
+		case "debito", "débito", "d", "debit":
+			tipo = Debito
+		default:
+			return nil, fmt.Errorf("%w: linha %d: tipo %q", ErrExtratoInvalido, numero, campo(registro, "tipo"))
+		}
+
+		linhas = append(linhas, Linha{
+			Numero:     numero,
+			EndToEndID: campo(registro, "e2e"),
+			TxID:       campo(registro, "txid"),
+			Tipo:       tipo,
+			Valor:      valor,
+			Data:       data,
+			Descricao:  campo(registro, "descricao"),
+		})
+	}
+
+	return linhas, nil
+}
+
+var (
+	transacaoOFX = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	// No OFX 1.x (SGML) as tags de valor não fecham; o valor vai até a próxima tag.
+	tagOFX = regexp.MustCompile(`(?is)<([A-Z0-9.]+)>([^<]*)`)
+)
+
+// LerOFX lê as transações (STMTTRN) de um OFX 1.x ou 2.x. FITID é o
+// EndToEndID e REFNUM, quando presente, o txid.
+func LerOFX(r io.Reader) ([]Linha, error) {
+	conteudo, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	blocos := transacaoOFX.FindAllSubmatch(conteudo, -1)
+	if len(blocos) == 0 && !bytes.Contains(bytes.ToUpper(conteudo), []byte("<OFX>")) {
+		return nil, fmt.Errorf("%w: não é um OFX", ErrExtratoInvalido)
+	}
+
+	linhas := make([]Linha, 0, len(blocos))
+	for n, bloco := range blocos {
+		numero := n + 1
+		tags := make(map[string]string)
+		for _, t := range tagOFX.FindAllSubmatch(bloco[1], -1) {
+			tags[strings.ToUpper(string(t[1]))] = strings.TrimSpace(string(t[2]))
+		}
+
+		valor, negativo, err := lerValor(tags["TRNAMT"])
+		if err != nil {
+			return nil, fmt.Errorf("%w: transação %d: %v", ErrExtratoInvalido, numero, err)
+		}
+		data, err := lerDataOFX(tags["DTPOSTED"])
+		if err != nil {
+			return nil, fmt.Errorf("%w: transação %d: %v", ErrExtratoInvalido, numero, err)
+		}
+
+		tipo := Credito
+		if negativo || strings.EqualFold(tags["TRNTYPE"], "DEBIT") {
+			tipo = Debito
+		}
+
+		descricao := tags["MEMO"]
+		if descricao == "" {
+			descricao = tags["NAME"]
+		}
+
+		linhas = append(linhas, Linha{
+			Numero:     numero,
+			EndToEndID: tags["FITID"],
+			TxID:       tags["REFNUM"],
+			Tipo:       tipo,
+			Valor:      valor,
+			Data:       data,
+			Descricao:  descricao,
+		})
+	}
+
+	return linhas, nil
+}
+
+// lerValor converte "1.234,56", "1234.56" ou "-10,5" em centavos positivos
+// e informa se o valor era negativo.
+func lerValor(s string) (int64, bool, error) {
+	s = strings.TrimSpace(s)
+	negativo := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	if s == "" {
+		return 0, false, errors.New("valor vazio")
+	}
+
+	// O último separador é o decimal; os anteriores agrupam milhares.
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 && len(s)-i-1 <= 2 {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s[:i]) + "." + s[i+1:]
+	} else {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s)
+	}
+
+	inteiro, fracao, _ := strings.Cut(s, ".")
+	for len(fracao) < 2 {
+		fracao += "0"
+	}
+	if inteiro == "" {
+		inteiro = "0"
+	}
+
+	centavos, err := strconv.ParseInt(inteiro+fracao, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("valor %q", s)
+	}
+	return centavos, negativo, nil
+}
+
+var layoutsData = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02", "02/01/2006 15:04:05", "02/01/2006"}
+
+func lerData(s string) (time.Time, error) {
+	for _, layout := range layoutsData {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data %q", s)
+}
+
+// lerDataOFX lê "AAAAMMDD[HHMMSS[.XXX]][[-3:BRT]]".
+func lerDataOFX(s string) (time.Time, error) {
+	s, fuso, _ := strings.Cut(s, "[")
+	s, _, _ = strings.Cut(s, ".")
+
+	local := time.Local
+	if fuso != "" {
+		deslocamento, _, _ := strings.Cut(strings.TrimSuffix(fuso, "]"), ":")
+		if horas, err := strconv.ParseFloat(deslocamento, 64); err == nil {
+			local = time.FixedZone("", int(horas*3600))
+		}
+	}
+
+	layouts := map[int]string{8: "20060102", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[len(s)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("data %q", s)
+	}
+	t, err := time.ParseInLocation(layout, s, local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data %q", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,192 @@
+package extrato
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func conferirLinhas(t *testing.T, got, want []Linha) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%d linhas, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if !g.Data.Equal(w.Data) {
+			t.Errorf("linha %d: Data = %v, want %v", i, g.Data, w.Data)
+		}
+		g.Data, w.Data = time.Time{}, time.Time{}
+		if g != w {
+			t.Errorf("linha %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestLerCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []Linha
+		wantErr error
+	}{
+		{
+			name: "vírgula com todas as colunas",
+			csv: "end_to_end_id,txid,tipo,valor,data,descricao\n" +
+				"E0001,DEP1,credito,10.50,2024-01-02T15:30:00-03:00,Pix recebido\n" +
+				"E0002,,debito,25.00,2024-01-02 16:00:00,Saque\n",
+			want: []Linha{
+				{Numero: 2, EndToEndID: "E0001", TxID: "DEP1", Tipo: Credito, Valor: 1050,
+					Data: time.Date(2024, 1, 2, 15, 30, 0, 0, time.FixedZone("", -3*3600)), Descricao: "Pix recebido"},
+				{Numero: 3, EndToEndID: "E0002", Tipo: Debito, Valor: 2500,
+					Data: time.Date(2024, 1, 2, 16, 0, 0, 0, time.Local), Descricao: "Saque"},
+			},
+		},
+		{
+			name: "ponto e vírgula, BOM e valor negativo sem tipo",
+			csv: "\ufeffE2E;Valor;Data\n" +
+				"E0003;-1.234,56;02/01/2024\n" +
+				"\n" +
+				"E0004;7,5;03/01/2024 08:15:00\n",
+			want: []Linha{
+				{Numero: 2, EndToEndID: "E0003", Tipo: Debito, Valor: 123456, Data: time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+				{Numero: 4, EndToEndID: "E0004", Tipo: Credito, Valor: 750, Data: time.Date(2024, 1, 3, 8, 15, 0, 0, time.Local)},
+			},
+		},
+		{
+			name: "tipo abreviado vale sobre o sinal",
+			csv:  "txid,tipo,valor,data\nDEP9,D,10,2024-01-05\n",
+			want: []Linha{
+				{Numero: 2, TxID: "DEP9", Tipo: Debito, Valor: 1000, Data: time.Date(2024, 1, 5, 0, 0, 0, 0, time.Local)},
+			},
+		},
+		{name: "arquivo vazio", csv: "", wantErr: ErrExtratoInvalido},
+		{name: "sem coluna valor", csv: "e2e,data\nE1,2024-01-02\n", wantErr: ErrExtratoInvalido},
+		{name: "sem coluna data", csv: "e2e,valor\nE1,10.00\n", wantErr: ErrExtratoInvalido},
+		{name: "valor ilegível", csv: "valor,data\nabc,2024-01-02\n", wantErr: ErrExtratoInvalido},
+		{name: "data ilegível", csv: "valor,data\n10.00,ontem\n", wantErr: ErrExtratoInvalido},
+		{name: "tipo desconhecido", csv: "tipo,valor,data\nestorno,10.00,2024-01-02\n", wantErr: ErrExtratoInvalido},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LerCSV(strings.NewReader(tt.csv))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				conferirLinhas(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLerOFX(t *testing.T) {
+	tests := []struct {
+		name    string
+		ofx     string
+		want    []Linha
+		wantErr error
+	}{
+		{
+			name: "OFX 1.x sem tags de fechamento",
+			ofx: "OFXHEADER:100\nDATA:OFXSGML\n\n<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>\n" +
+				"<STMTTRN>\n<TRNTYPE>CREDIT\n<DTPOSTED>20240102153000[-3:BRT]\n<TRNAMT>10.50\n<FITID>E0001\n<REFNUM>DEP1\n<MEMO>Pix recebido\n</STMTTRN>\n" +
+				"<STMTTRN>\n<TRNTYPE>DEBIT\n<DTPOSTED>20240103\n<TRNAMT>-25.00\n<FITID>E0002\n<NAME>Saque\n</STMTTRN>\n" +
+				"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>",
+			want: []Linha{
+				{Numero: 1, EndToEndID: "E0001", TxID: "DEP1", Tipo: Credito, Valor: 1050,
+					Data: time.Date(2024, 1, 2, 15, 30, 0, 0, time.FixedZone("", -3*3600)), Descricao: "Pix recebido"},
+				{Numero: 2, EndToEndID: "E0002", Tipo: Debito, Valor: 2500,
+					Data: time.Date(2024, 1, 3, 0, 0, 0, 0, time.Local), Descricao: "Saque"},
+			},
+		},
+		{
+			name: "OFX 2.x em XML",
+			ofx: `<?xml version="1.0"?><OFX><BANKTRANLIST>` +
+				`<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>202401051200.000</DTPOSTED><TRNAMT>5</TRNAMT><FITID>E0003</FITID></STMTTRN>` +
+				`</BANKTRANLIST></OFX>`,
+			want: []Linha{
+				{Numero: 1, EndToEndID: "E0003", Tipo: Debito, Valor: 500, Data: time.Date(2024, 1, 5, 12, 0, 0, 0, time.Local)},
+			},
+		},
+		{name: "OFX sem transações", ofx: "<OFX></OFX>", want: []Linha{}},
+		{name: "não é OFX", ofx: "valor,data\n10.00,2024-01-02\n", wantErr: ErrExtratoInvalido},
+		{name: "data ilegível", ofx: "<OFX><STMTTRN><TRNAMT>1.00<DTPOSTED>2024</STMTTRN></OFX>", wantErr: ErrExtratoInvalido},
+		{name: "sem valor", ofx: "<OFX><STMTTRN><DTPOSTED>20240102</STMTTRN></OFX>", wantErr: ErrExtratoInvalido},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LerOFX(strings.NewReader(tt.ofx))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				conferirLinhas(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLerValor(t *testing.T) {
+	tests := []struct {
+		valor    string
+		want     int64
+		negativo bool
+		wantErr  bool
+	}{
+		{"10.50", 1050, false, false},
+		{"10,50", 1050, false, false},
+		{"10,5", 1050, false, false},
+		{"1.234,56", 123456, false, false},
+		{"1,234.56", 123456, false, false},
+		{"1.234", 123400, false, false},
+		{"1234", 123400, false, false},
+		{"-10,5", 1050, true, false},
+		{"+3.00", 300, false, false},
+		{" 0.01 ", 1, false, false},
+		{"", 0, false, true},
+		{"-", 0, false, true},
+		{"abc", 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.valor, func(t *testing.T) {
+			got, negativo, err := lerValor(tt.valor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lerValor(%q) erro = %v, wantErr %v", tt.valor, err, tt.wantErr)
+			}
+			if got != tt.want || negativo != tt.negativo {
+				t.Errorf("lerValor(%q) = %d, %v, want %d, %v", tt.valor, got, negativo, tt.want, tt.negativo)
+			}
+		})
+	}
+}
+
+func TestLer(t *testing.T) {
+	tests := []struct {
+		name    string
+		arquivo string
+		wantErr error
+	}{
+		{"csv pela extensão", "extrato.CSV", nil},
+		{"ofx pela extensão", "liquidacao.ofx", nil},
+		{"formato desconhecido", "extrato.xlsx", ErrFormatoDesconhecido},
+		{"sem extensão", "extrato", ErrFormatoDesconhecido},
+	}
+	conteudo := map[string]string{
+		FormatoCSV: "valor,data\n10.00,2024-01-02\n",
+		FormatoOFX: "<OFX><STMTTRN><TRNAMT>10.00<DTPOSTED>20240102</STMTTRN></OFX>",
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formato := Formato(tt.arquivo)
+			got, err := Ler(formato, strings.NewReader(conteudo[formato]))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (len(got) != 1 || got[0].Valor != 1000) {
+				t.Errorf("Ler = %+v, want uma linha de 1000", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package meiopag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrMeioPagamentoInvalido      = errors.New("dados do meio de pagamento inválidos")
+	ErrMeioPagamentoNaoEncontrado = errors.New("meio de pagamento não encontrado")
+	ErrMeioPagamentoDuplicado     = errors.New("meio de pagamento já cadastrado")
+	ErrOperacaoNaoPermitida       = errors.New("operação não permitida para o meio de pagamento")
+	ErrValorForaDoLimite          = errors.New("valor fora dos limites do meio de pagamento")
+)
+
+type MeioPagServiceInterface interface {
+	Create(ctx context.Context, nome string, regras model.RegrasMeioPagamento) (*model.MeioPagamento, error)
+	Update(ctx context.Context, ID string, nome string, regras model.RegrasMeioPagamento) (*model.MeioPagamento, error)
+	GetByID(ctx context.Context, ID string) (*model.MeioPagamento, error)
+	GetByNome(ctx context.Context, nome string) (*model.MeioPagamento, error)
+	GetAll(ctx context.Context, filters model.FilterMeioPagamento, limit, page int64) (*model.Paginate, error)
+	Autorizar(ctx context.Context, nome, operacao string, valor int64) (*model.MeioPagamento, error)
+}
+
+type MeioPagDataService struct {
+	mdb mongodb.MongoDBInterface
+}
+
+func NewMeioPagService(mongo_connection mongodb.MongoDBInterface) *MeioPagDataService {
+	mds := &MeioPagDataService{
+		mdb: mongo_connection,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := mds.criarIndices(ctx); err != nil {
+		logger.Error("erro ao criar indices do meio de pagamento", err)
+	}
+
+	return mds
+}
+
+func (mds *MeioPagDataService) Create(ctx context.Context, nome string, regras model.RegrasMeioPagamento) (*model.MeioPagamento, error) {
+	meio := model.NewMeioPagamento(nome, regras)
+	if err := validarMeio(meio); err != nil {
+		return nil, err
+	}
+
+	_, err := mds.mdb.GetCollection("cfStore").InsertOne(ctx, meio)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("%w: %s", ErrMeioPagamentoDuplicado, meio.MeioPg)
+		}
+		logger.Error("erro salvar meio de pagamento", err)
+		return nil, err
+	}
+
+	return meio, nil
+}
+
+// Update renomeia o meio e aplica as regras informadas, mantendo as demais.
+func (mds *MeioPagDataService) Update(ctx context.Context, ID string, nome string, regras model.RegrasMeioPagamento) (*model.MeioPagamento, error) {
+	meio, err := mds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if nome != "" {
+		meio.MeioPg = nome
+	}
+	meio.Aplicar(regras)
+	if err := validarMeio(meio); err != nil {
+		return nil, err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: meio.ID},
+		{Key: "data_type", Value: "meio_pagamento"},
+	}
+
+	meio.UpdatedAt = time.Now().Format(time.RFC3339)
+	update := bson.D{{Key: "$set",
+		Value: bson.D{
+			{Key: "meio_pg", Value: meio.MeioPg},
+			{Key: "valor_minimo", Value: meio.ValorMinimo},
+			{Key: "valor_maximo", Value: meio.ValorMaximo},
+			{Key: "taxa", Value: meio.Taxa},
+			{Key: "operacoes", Value: meio.Operacoes},
+			{Key: "enabled", Value: meio.Enabled},
+			{Key: "updated_at", Value: meio.UpdatedAt},
+		},
+	}}
+
+	_, err = mds.mdb.GetCollection("cfStore").UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("%w: %s", ErrMeioPagamentoDuplicado, meio.MeioPg)
+		}
+		logger.Error("Error while updating data", err)
+		return nil, err
+	}
+
+	return meio, nil
+}
+
+func (mds *MeioPagDataService) GetByID(ctx context.Context, ID string) (*model.MeioPagamento, error) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return nil, ErrMeioPagamentoNaoEncontrado
+	}
+
+	return mds.buscar(ctx, bson.D{
+		{Key: "data_type", Value: "meio_pagamento"},
+		{Key: "_id", Value: objectID},
+	})
+}
+
+func (mds *MeioPagDataService) GetByNome(ctx context.Context, nome string) (*model.MeioPagamento, error) {
+	return mds.buscar(ctx, bson.D{
+		{Key: "data_type", Value: "meio_pagamento"},
+		{Key: "meio_pg", Value: nome},
+	})
+}
+
+func (mds *MeioPagDataService) GetAll(ctx context.Context, filters model.FilterMeioPagamento, limit, page int64) (*model.Paginate, error) {
+	collection := mds.mdb.GetCollection("cfStore")
+
+	query := bson.M{"data_type": "meio_pagamento"}
+	if filters.MeioPg != "" {
+		query["meio_pg"] = bson.M{"$regex": regexp.QuoteMeta(filters.MeioPg), "$options": "i"}
+	}
+	if filters.Operacao != "" {
+		query["operacoes"] = filters.Operacao
+	}
+	if filters.Enabled != "" {
+		enable, err := strconv.ParseBool(filters.Enabled)
+		if err != nil {
+			return nil, fmt.Errorf("%w: enabled %q", ErrMeioPagamentoInvalido, filters.Enabled)
+		}
+		query["enabled"] = enable
+	}
+
+	count, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		logger.Error("erro ao consultar todos os meios de pagamento", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+
+	opts := pagination.GetPaginatedOpts().SetSort(bson.D{{Key: "meio_pg", Value: 1}})
+	curr, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]*model.MeioPagamento, 0)
+	for curr.Next(ctx) {
+		meio := &model.MeioPagamento{}
+		if err := curr.Decode(meio); err != nil {
+			logger.Error("erro ao consultar todos os meios de pagamento", err)
+			continue
+		}
+		result = append(result, meio)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+// Autorizar confere se o meio está ativo, atende a operação e aceita o
+// valor. Valor zero (QR sem valor definido) só confere o meio e a operação.
+func (mds *MeioPagDataService) Autorizar(ctx context.Context, nome, operacao string, valor int64) (*model.MeioPagamento, error) {
+	meio, err := mds.GetByNome(ctx, nome)
+	if err != nil {
+		return nil, err
+	}
+
+	if !meio.Permite(operacao) {
+		return nil, fmt.Errorf("%w: %s não aceita %s", ErrOperacaoNaoPermitida, meio.MeioPg, operacao)
+	}
+	if valor != 0 && !meio.NoLimite(valor) {
+		if meio.ValorMaximo > 0 {
+			return nil, fmt.Errorf("%w: %s aceita de %d a %d centavos", ErrValorForaDoLimite, meio.MeioPg, meio.ValorMinimo, meio.ValorMaximo)
+		}
+		return nil, fmt.Errorf("%w: %s aceita a partir de %d centavos", ErrValorForaDoLimite, meio.MeioPg, meio.ValorMinimo)
+	}
+
+	return meio, nil
+}
+
+func (mds *MeioPagDataService) buscar(ctx context.Context, filter bson.D) (*model.MeioPagamento, error) {
+	meio := &model.MeioPagamento{}
+	err := mds.mdb.GetCollection("cfStore").FindOne(ctx, filter).Decode(meio)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrMeioPagamentoNaoEncontrado
+		}
+		logger.Error("erro ao consultar meio de pagamento", err)
+		return nil, err
+	}
+
+	return meio, nil
+}
+
+// criarIndices garante um cadastro por nome, que é como os fluxos de
+// depósito e saque encontram o meio.
+func (mds *MeioPagDataService) criarIndices(ctx context.Context) error {
+	_, err := mds.mdb.GetCollection("cfStore").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "meio_pg", Value: 1}},
+		Options: options.Index().
+			SetName("meio_pagamento_nome").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.D{{Key: "data_type", Value: "meio_pagamento"}}),
+	})
+	return err
+}
+
+func validarMeio(meio *model.MeioPagamento) error {
+	if meio.MeioPg == "" {
+		return fmt.Errorf("%w: nome obrigatório", ErrMeioPagamentoInvalido)
+	}
+	if meio.ValorMinimo < 0 || meio.ValorMaximo < 0 {
+		return fmt.Errorf("%w: limites não podem ser negativos", ErrMeioPagamentoInvalido)
+	}
+	if meio.ValorMaximo > 0 && meio.ValorMaximo < meio.ValorMinimo {
+		return fmt.Errorf("%w: valor máximo menor que o mínimo", ErrMeioPagamentoInvalido)
+	}
+	if meio.Taxa < 0 || meio.Taxa >= model.BaseCalculo {
+		return fmt.Errorf("%w: taxa deve ficar entre 0 e %d pontos-base", ErrMeioPagamentoInvalido, model.BaseCalculo-1)
+	}
+	for _, operacao := range meio.Operacoes {
+		if operacao != model.OperacaoDeposito && operacao != model.OperacaoSaque {
+			return fmt.Errorf("%w: operação %q", ErrMeioPagamentoInvalido, operacao)
+		}
+	}
+
+	return nil
+}
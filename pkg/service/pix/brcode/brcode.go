@@ -0,0 +1,265 @@
+// Package brcode monta e lê o payload do BR Code do Pix, o QR no padrão EMV
+// MPM (Merchant Presented Mode) definido pelo Banco Central.
+package brcode
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// IDs dos campos EMV usados pelo Pix.
+const (
+	idFormato          = "00"
+	idIniciacao        = "01"
+	idContaPix         = "26"
+	idCategoria        = "52"
+	idMoeda            = "53"
+	idValor            = "54"
+	idPais             = "58"
+	idNome             = "59"
+	idCidade           = "60"
+	idDadosAdicionais  = "62"
+	idCRC              = "63"
+	idGUI              = "00"
+	idChave            = "01"
+	idDescricao        = "02"
+	idURL              = "25"
+	idTxID             = "05"
+	gui                = "br.gov.bcb.pix"
+	iniciacaoEstatico  = "11"
+	iniciacaoDinamico  = "12"
+	moedaReal          = "986"
+	txidSemIdentificar = "***"
+)
+
+// Limites de tamanho do manual do BR Code.
+const (
+	TamanhoNome      = 25
+	TamanhoCidade    = 15
+	TamanhoTxIDPix   = 25
+	tamanhoCampo     = 99
+	tamanhoDescricao = 72
+)
+
+var (
+	ErrPayloadInvalido = errors.New("payload do BR Code inválido")
+	ErrCRCInvalido     = errors.New("CRC do BR Code não confere")
+
+	txidValido = regexp.MustCompile(`^[A-Za-z0-9]{1,25}$`)
+	digitos    = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// BRCode reúne os dados do QR. Com Chave é um QR estático, que pode ser pago
+// várias vezes e identifica o pagamento pelo TxID; com URL é um QR dinâmico,
+// cujos dados ficam na cobrança do PSP apontada pela URL. Valor em centavos;
+// zero deixa o pagador escolher.
+type BRCode struct {
+	Chave     string
+	URL       string
+	Nome      string
+	Cidade    string
+	Valor     int64
+	TxID      string
+	Descricao string
+}
+
+// Payload devolve o "Pix copia e cola" com o CRC16 no final.
+func (b BRCode) Payload() (string, error) {
+	if (b.Chave == "") == (b.URL == "") {
+		return "", fmt.Errorf("%w: informe a chave ou a URL", ErrPayloadInvalido)
+	}
+	if b.Valor < 0 {
+		return "", fmt.Errorf("%w: valor negativo", ErrPayloadInvalido)
+	}
+
+	nome := ascii(b.Nome, TamanhoNome)
+	cidade := ascii(b.Cidade, TamanhoCidade)
+	if nome == "" || cidade == "" {
+		return "", fmt.Errorf("%w: nome e cidade do recebedor obrigatórios", ErrPayloadInvalido)
+	}
+
+	txid := txidSemIdentificar
+	if b.TxID != "" {
+		if !txidValido.MatchString(b.TxID) {
+			return "", fmt.Errorf("%w: txid deve ter até 25 letras ou números", ErrPayloadInvalido)
+		}
+		txid = b.TxID
+	}
+
+	iniciacao := iniciacaoEstatico
+	conta := campo(idGUI, gui)
+	if b.URL != "" {
+		iniciacao = iniciacaoDinamico
+		// O QR dinâmico leva a URL sem o esquema; o txid fica na cobrança.
+		conta += campo(idURL, strings.TrimPrefix(b.URL, "https://"))
+		txid = txidSemIdentificar
+	} else {
+		conta += campo(idChave, b.Chave)
+		if b.Descricao != "" {
+			conta += campo(idDescricao, ascii(b.Descricao, tamanhoDescricao))
+		}
+	}
+	if len(conta) > tamanhoCampo {
+		return "", fmt.Errorf("%w: chave, URL ou descrição longas demais", ErrPayloadInvalido)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(campo(idFormato, "01"))
+	sb.WriteString(campo(idIniciacao, iniciacao))
+	sb.WriteString(campo(idContaPix, conta))
+	sb.WriteString(campo(idCategoria, "0000"))
+	sb.WriteString(campo(idMoeda, moedaReal))
+	if b.Valor > 0 {
+		sb.WriteString(campo(idValor, Valor(b.Valor)))
+	}
+	sb.WriteString(campo(idPais, "BR"))
+	sb.WriteString(campo(idNome, nome))
+	sb.WriteString(campo(idCidade, cidade))
+	sb.WriteString(campo(idDadosAdicionais, campo(idTxID, txid)))
+	sb.WriteString(idCRC + "04")
+
+	return fmt.Sprintf("%s%04X", sb.String(), CRC16(sb.String())), nil
+}
+
+// Ler confere o CRC e devolve os dados do payload. O TxID "***" volta vazio.
+func Ler(payload string) (*BRCode, error) {
+	if len(payload) < 8 || payload[len(payload)-8:len(payload)-4] != idCRC+"04" {
+		return nil, fmt.Errorf("%w: sem CRC", ErrPayloadInvalido)
+	}
+	crc, err := strconv.ParseUint(payload[len(payload)-4:], 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("%w: CRC ilegível", ErrPayloadInvalido)
+	}
+	if uint16(crc) != CRC16(payload[:len(payload)-4]) {
+		return nil, ErrCRCInvalido
+	}
+
+	campos, err := lerCampos(payload[:len(payload)-8])
+	if err != nil {
+		return nil, err
+	}
+	if campos[idFormato] != "01" {
+		return nil, fmt.Errorf("%w: formato %q", ErrPayloadInvalido, campos[idFormato])
+	}
+
+	conta, err := lerCampos(campos[idContaPix])
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(conta[idGUI], gui) {
+		return nil, fmt.Errorf("%w: não é um BR Code Pix", ErrPayloadInvalido)
+	}
+
+	adicionais, err := lerCampos(campos[idDadosAdicionais])
+	if err != nil {
+		return nil, err
+	}
+
+	b := &BRCode{
+		Chave:     conta[idChave],
+		URL:       conta[idURL],
+		Descricao: conta[idDescricao],
+		Nome:      campos[idNome],
+		Cidade:    campos[idCidade],
+	}
+	if txid := adicionais[idTxID]; txid != txidSemIdentificar {
+		b.TxID = txid
+	}
+	if v := campos[idValor]; v != "" {
+		b.Valor, err = Centavos(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// CRC16 calcula o CRC-16/CCITT-FALSE (polinômio 0x1021, início 0xFFFF)
+// exigido no campo 63.
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Valor formata centavos como o campo 54 e a API Pix esperam, ex.: "10.50".
+func Valor(centavos int64) string {
+	return fmt.Sprintf("%d.%02d", centavos/100, centavos%100)
+}
+
+// Centavos lê um valor no formato "10.50" sem passar por ponto flutuante.
+// Só aceita dígitos antes e depois do ponto, sem sinal.
+func Centavos(valor string) (int64, error) {
+	inteiro, fracao, ponto := strings.Cut(valor, ".")
+	if !digitos.MatchString(inteiro) || len(fracao) > 2 || (ponto && !digitos.MatchString(fracao)) {
+		return 0, fmt.Errorf("%w: valor %q", ErrPayloadInvalido, valor)
+	}
+	for len(fracao) < 2 {
+		fracao += "0"
+	}
+
+	reais, err := strconv.ParseInt(inteiro, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: valor %q", ErrPayloadInvalido, valor)
+	}
+	cents, _ := strconv.ParseInt(fracao, 10, 64)
+
+	return reais*100 + cents, nil
+}
+
+func campo(id, valor string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(valor), valor)
+}
+
+func lerCampos(s string) (map[string]string, error) {
+	campos := make(map[string]string)
+	for len(s) > 0 {
+		if len(s) < 4 {
+			return nil, fmt.Errorf("%w: campo truncado", ErrPayloadInvalido)
+		}
+		tamanho, err := strconv.Atoi(s[2:4])
+		if err != nil || len(s) < 4+tamanho {
+			return nil, fmt.Errorf("%w: tamanho do campo %s", ErrPayloadInvalido, s[:2])
+		}
+		campos[s[:2]] = s[4 : 4+tamanho]
+		s = s[4+tamanho:]
+	}
+	return campos, nil
+}
+
+// ascii remove acentos e caracteres fora do ASCII imprimível, que o padrão
+// não aceita, e corta no tamanho máximo do campo.
+func ascii(s string, limite int) string {
+	semAcento, _, _ := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+
+	var sb strings.Builder
+	for _, r := range semAcento {
+		if r >= 0x20 && r < 0x7F {
+			sb.WriteRune(r)
+		}
+	}
+
+	result := strings.TrimSpace(sb.String())
+	if len(result) > limite {
+		result = strings.TrimSpace(result[:limite])
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package brcode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// payloadBCB é o exemplo de QR estático do Manual do BR Code do Banco Central.
+const payloadBCB = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want uint16
+	}{
+		{"valor de verificação do CRC-16/CCITT-FALSE", "123456789", 0x29B1},
+		{"vazio", "", 0xFFFF},
+		{"payload de referência do BCB", payloadBCB[:len(payloadBCB)-4], 0x1D3D},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CRC16(tt.data); got != tt.want {
+				t.Errorf("CRC16 = %04X, want %04X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLer(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    *BRCode
+		wantErr error
+	}{
+		{
+			name:    "payload de referência do BCB",
+			payload: payloadBCB,
+			want: &BRCode{
+				Chave:  "123e4567-e12b-12d1-a456-426655440000",
+				Nome:   "Fulano de Tal",
+				Cidade: "BRASILIA",
+			},
+		},
+		{"CRC trocado", payloadBCB[:len(payloadBCB)-4] + "1D3E", nil, ErrCRCInvalido},
+		{"CRC ilegível", payloadBCB[:len(payloadBCB)-4] + "ZZZZ", nil, ErrPayloadInvalido},
+		{"sem CRC", payloadBCB[:len(payloadBCB)-8], nil, ErrPayloadInvalido},
+		{"curto demais", "6304", nil, ErrPayloadInvalido},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Ler(tt.payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ler = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadIdaEVolta(t *testing.T) {
+	tests := []struct {
+		name string
+		in   BRCode
+		want BRCode
+	}{
+		{
+			name: "estático com valor e txid",
+			in:   BRCode{Chave: "pix@fortuna.example.com", Nome: "FORTUNA", Cidade: "SAO PAULO", Valor: 1050, TxID: "DEP123abc"},
+			want: BRCode{Chave: "pix@fortuna.example.com", Nome: "FORTUNA", Cidade: "SAO PAULO", Valor: 1050, TxID: "DEP123abc"},
+		},
+		{
+			name: "estático sem valor nem txid",
+			in:   BRCode{Chave: "12345678909", Nome: "Fulano de Tal", Cidade: "BRASILIA"},
+			want: BRCode{Chave: "12345678909", Nome: "Fulano de Tal", Cidade: "BRASILIA"},
+		},
+		{
+			name: "estático com descrição",
+			in:   BRCode{Chave: "+5511999990000", Nome: "FORTUNA", Cidade: "RECIFE", Valor: 5, Descricao: "Deposito"},
+			want: BRCode{Chave: "+5511999990000", Nome: "FORTUNA", Cidade: "RECIFE", Valor: 5, Descricao: "Deposito"},
+		},
+		{
+			name: "acentos removidos e nome cortado",
+			in:   BRCode{Chave: "pix@fortuna.example.com", Nome: "Loterias Fortuna São João Ltda", Cidade: "São Paulo", Valor: 100},
+			want: BRCode{Chave: "pix@fortuna.example.com", Nome: "Loterias Fortuna Sao Joao", Cidade: "Sao Paulo", Valor: 100},
+		},
+		{
+			name: "dinâmico leva a URL sem esquema e ignora o txid",
+			in:   BRCode{URL: "https://psp.example.com/cob/abc", Nome: "FORTUNA", Cidade: "SAO PAULO", Valor: 99999, TxID: "ignorado"},
+			want: BRCode{URL: "psp.example.com/cob/abc", Nome: "FORTUNA", Cidade: "SAO PAULO", Valor: 99999},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := tt.in.Payload()
+			if err != nil {
+				t.Fatalf("Payload: %v", err)
+			}
+			got, err := Ler(payload)
+			if err != nil {
+				t.Fatalf("Ler(%q): %v", payload, err)
+			}
+			if *got != tt.want {
+				t.Errorf("Ler(Payload()) = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadInvalido(t *testing.T) {
+	tests := []struct {
+		name string
+		in   BRCode
+	}{
+		{"sem chave nem URL", BRCode{Nome: "FORTUNA", Cidade: "RECIFE"}},
+		{"chave e URL", BRCode{Chave: "a@b.com", URL: "https://psp/cob", Nome: "FORTUNA", Cidade: "RECIFE"}},
+		{"valor negativo", BRCode{Chave: "a@b.com", Nome: "FORTUNA", Cidade: "RECIFE", Valor: -1}},
+		{"sem nome", BRCode{Chave: "a@b.com", Cidade: "RECIFE"}},
+		{"txid com símbolo", BRCode{Chave: "a@b.com", Nome: "FORTUNA", Cidade: "RECIFE", TxID: "dep-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.in.Payload(); !errors.Is(err, ErrPayloadInvalido) {
+				t.Errorf("Payload = %v, want %v", err, ErrPayloadInvalido)
+			}
+		})
+	}
+}
+
+func TestCentavos(t *testing.T) {
+	tests := []struct {
+		valor   string
+		want    int64
+		wantErr bool
+	}{
+		{"10.50", 1050, false},
+		{"10.5", 1050, false},
+		{"0.01", 1, false},
+		{"7", 700, false},
+		{"1234567.89", 123456789, false},
+		{"-0.50", 0, true},
+		{"+1.00", 0, true},
+		{"5.+5", 0, true},
+		{"5.-5", 0, true},
+		{"10.", 0, true},
+		{".50", 0, true},
+		{"1.234", 0, true},
+		{"1,50", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.valor, func(t *testing.T) {
+			got, err := Centavos(tt.valor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Centavos(%q) erro = %v, wantErr %v", tt.valor, err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrPayloadInvalido) {
+				t.Errorf("Centavos(%q) erro = %v, want %v", tt.valor, err, ErrPayloadInvalido)
+			}
+			if got != tt.want {
+				t.Errorf("Centavos(%q) = %d, want %d", tt.valor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValor(t *testing.T) {
+	tests := []struct {
+		centavos int64
+		want     string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{1050, "10.50"},
+		{123456789, "1234567.89"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Valor(tt.centavos); got != tt.want {
+				t.Errorf("Valor(%d) = %q, want %q", tt.centavos, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package pix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/psp"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/meiopag"
+	"github.com/katana/fortuna/backend-go/pkg/service/pix/brcode"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Tamanhos do txid: o QR estático aceita até 25 caracteres e a cobrança
+// dinâmica da API Pix exige de 26 a 35.
+const (
+	tamanhoTxIDEstatico = brcode.TamanhoTxIDPix
+	tamanhoTxIDDinamico = 32
+)
+
+var (
+	ErrCobrancaInvalida      = errors.New("dados da cobrança Pix inválidos")
+	ErrCobrancaNaoEncontrada = errors.New("cobrança Pix não encontrada")
+	ErrSimulacaoIndisponivel = errors.New("PSP configurado não simula pagamentos")
+)
+
+type PixServiceInterface interface {
+	CriarCobranca(ctx context.Context, pedido model.PedidoCobrancaPix) (*model.CobrancaPix, error)
+	GetByTxID(ctx context.Context, txid string) (*model.CobrancaPix, error)
+	ReceberWebhook(ctx context.Context, corpo []byte, assinatura string) ([]*model.CobrancaPix, error)
+	SimularPagamento(ctx context.Context, txid string, valor int64) ([]*model.CobrancaPix, error)
+}
+
+type PixDataService struct {
+	mdb      mongodb.MongoDBInterface
+	psp      psp.PSPInterface
+	carteira carteira.CarteiraServiceInterface
+	meios    meiopag.MeioPagServiceInterface
+	conf     *config.Config
+}
+
+func NewPixService(mongo_connection mongodb.MongoDBInterface, provedor psp.PSPInterface, carteira_cliente carteira.CarteiraServiceInterface, meios meiopag.MeioPagServiceInterface, conf *config.Config) *PixDataService {
+	pds := &PixDataService{
+		mdb:      mongo_connection,
+		psp:      provedor,
+		carteira: carteira_cliente,
+		meios:    meios,
+		conf:     conf,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := pds.criarIndices(ctx); err != nil {
+		logger.Error("erro ao criar indices do Pix", err)
+	}
+
+	return pds
+}
+
+// CriarCobranca gera o BR Code do depósito. O QR estático leva a chave Pix
+// da casa e o txid; o dinâmico é registrado no PSP e leva só a URL da cobrança.
+// O meio de pagamento Pix precisa aceitar depósito e o valor pedido.
+func (pds *PixDataService) CriarCobranca(ctx context.Context, pedido model.PedidoCobrancaPix) (*model.CobrancaPix, error) {
+	if pedido.ClienteID.IsZero() {
+		return nil, fmt.Errorf("%w: cliente obrigatório", ErrCobrancaInvalida)
+	}
+	if pedido.Valor < 0 || (pedido.Tipo == model.PixDinamico && pedido.Valor == 0) {
+		return nil, fmt.Errorf("%w: valor deve ser positivo", ErrCobrancaInvalida)
+	}
+
+	if _, err := pds.meios.Autorizar(ctx, model.MeioPagPix, model.OperacaoDeposito, pedido.Valor); err != nil {
+		return nil, err
+	}
+
+	count, err := pds.mdb.GetCollection("cfStore").CountDocuments(ctx, bson.D{
+		{Key: "data_type", Value: "cliente"},
+		{Key: "_id", Value: pedido.ClienteID},
+		{Key: "enabled", Value: true},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar Cliente da cobranca Pix", err)
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: cliente não encontrado ou desativado", ErrCobrancaInvalida)
+	}
+
+	cob := model.CobrancaPix{
+		ClienteID: pedido.ClienteID,
+		Tipo:      pedido.Tipo,
+		Valor:     pedido.Valor,
+	}
+	code := brcode.BRCode{
+		Nome:   pds.conf.PixNome,
+		Cidade: pds.conf.PixCidade,
+		Valor:  pedido.Valor,
+	}
+
+	switch pedido.Tipo {
+	case model.PixEstatico:
+		cob.TxID = psp.Aleatorio(tamanhoTxIDEstatico)
+		code.Chave = pds.conf.PixChave
+		code.TxID = cob.TxID
+	case model.PixDinamico:
+		cob.TxID = psp.Aleatorio(tamanhoTxIDDinamico)
+		criada, err := pds.psp.CriarCobranca(ctx, psp.Cobranca{
+			TxID:      cob.TxID,
+			Valor:     pedido.Valor,
+			Chave:     pds.conf.PixChave,
+			Descricao: "Depósito Fortuna",
+			Expiracao: time.Duration(pds.conf.PixExpiracaoMinutos) * time.Minute,
+		})
+		if err != nil {
+			logger.Error("erro ao criar cobranca Pix no PSP", err)
+			return nil, err
+		}
+		cob.Location = criada.Location
+		cob.ExpiraEm = criada.ExpiraEm
+		code.URL = criada.Location
+	default:
+		return nil, fmt.Errorf("%w: tipo deve ser %q ou %q", ErrCobrancaInvalida, model.PixEstatico, model.PixDinamico)
+	}
+
+	cob.Payload, err = code.Payload()
+	if err != nil {
+		return nil, errors.Join(ErrCobrancaInvalida, err)
+	}
+
+	novo := model.NewCobrancaPix(cob)
+	if _, err := pds.mdb.GetCollection("cfStore").InsertOne(ctx, novo); err != nil {
+		logger.Error("erro salvar cobranca Pix", err)
+		return nil, err
+	}
+
+	return novo, nil
+}
+
+// GetByTxID devolve a cobrança; a dinâmica vencida sem pagamento aparece como expirada.
+func (pds *PixDataService) GetByTxID(ctx context.Context, txid string) (*model.CobrancaPix, error) {
+	cob := &model.CobrancaPix{}
+	err := pds.mdb.GetCollection("cfStore").FindOne(ctx, bson.D{
+		{Key: "data_type", Value: "cobranca_pix"},
+		{Key: "txid", Value: txid},
+	}).Decode(cob)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCobrancaNaoEncontrada
+		}
+		logger.Error("erro ao consultar cobranca Pix", err)
+		return nil, err
+	}
+
+	if cob.Vencida(time.Now()) {
+		cob.Status = model.CobrancaExpirada
+	}
+
+	return cob, nil
+}
+
+// ReceberWebhook confere a assinatura do PSP e credita cada Pix notificado.
+// Um erro faz o PSP reenviar a notificação; os pagamentos já creditados são
+// reconhecidos pelo EndToEndID e não creditam de novo.
+func (pds *PixDataService) ReceberWebhook(ctx context.Context, corpo []byte, assinatura string) ([]*model.CobrancaPix, error) {
+	notificacao, err := pds.psp.VerificarWebhook(corpo, assinatura)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.CobrancaPix, 0, len(notificacao.Pix))
+	for _, pagamento := range notificacao.Pix {
+		cob, err := pds.confirmar(ctx, pagamento)
+		if err != nil {
+			// Pix sem cobrança nossa não tem a quem creditar; não adianta reenviar.
+			if errors.Is(err, ErrCobrancaNaoEncontrada) {
+				logger.Info("Pix recebido sem cobrança: txid " + pagamento.TxID + " e2e " + pagamento.EndToEndID)
+				continue
+			}
+			return nil, err
+		}
+		result = append(result, cob)
+	}
+
+	return result, nil
+}
+
+// SimularPagamento paga a cobrança pelo PSP de teste e processa a notificação
+// como se viesse do webhook. Valor zero paga o valor da cobrança.
+func (pds *PixDataService) SimularPagamento(ctx context.Context, txid string, valor int64) ([]*model.CobrancaPix, error) {
+	simulador, ok := pds.psp.(psp.Simulador)
+	if !ok {
+		return nil, ErrSimulacaoIndisponivel
+	}
+
+	cob, err := pds.GetByTxID(ctx, txid)
+	if err != nil {
+		return nil, err
+	}
+	if valor == 0 {
+		valor = cob.Valor
+	}
+	if valor <= 0 {
+		return nil, fmt.Errorf("%w: informe o valor pago", ErrCobrancaInvalida)
+	}
+
+	corpo, assinatura, err := simulador.SimularPagamento(ctx, txid, valor)
+	if err != nil {
+		return nil, err
+	}
+
+	return pds.ReceberWebhook(ctx, corpo, assinatura)
+}
+
+// confirmar deposita o valor pago na carteira e registra o pagamento na
+// cobrança. O depósito vem primeiro e é idempotente pelo EndToEndID, então
+// uma falha no registro é corrigida pela reentrega da notificação.
+func (pds *PixDataService) confirmar(ctx context.Context, pagamento psp.PagamentoPix) (*model.CobrancaPix, error) {
+	if pagamento.EndToEndID == "" {
+		return nil, fmt.Errorf("%w: pagamento sem EndToEndID", ErrCobrancaInvalida)
+	}
+
+	cob, err := pds.GetByTxID(ctx, pagamento.TxID)
+	if err != nil {
+		return nil, err
+	}
+
+	valor, err := brcode.Centavos(pagamento.Valor)
+	if err != nil || valor <= 0 {
+		return nil, fmt.Errorf("%w: valor pago %q", ErrCobrancaInvalida, pagamento.Valor)
+	}
+	if cob.Valor > 0 && valor != cob.Valor {
+		// O dinheiro já entrou: credita o que foi pago e deixa registrado.
+		logger.Info(fmt.Sprintf("Pix %s pago com %d centavos na cobrança %s de %d", pagamento.EndToEndID, valor, cob.TxID, cob.Valor))
+	}
+
+	taxa, err := pds.tarifa(ctx, valor)
+	if err != nil {
+		return nil, err
+	}
+
+	lct, err := pds.carteira.Depositar(ctx, cob.ClienteID, model.PedidoDeposito{
+		Valor:      valor,
+		Taxa:       taxa,
+		Referencia: model.ReferenciaPix + pagamento.EndToEndID,
+		Descricao:  "Pix " + cob.TxID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	registro := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "pagamentos", Value: model.PagamentoCobranca{
+			EndToEndID:   pagamento.EndToEndID,
+			Valor:        valor,
+			Taxa:         taxa,
+			Horario:      pagamento.Horario,
+			LancamentoID: lct.ID,
+		}}}},
+		{Key: "$inc", Value: bson.D{{Key: "valor_pago", Value: valor}}},
+	}
+	campos := bson.D{{Key: "updated_at", Value: time.Now().Format(time.RFC3339)}}
+	if cob.Tipo == model.PixDinamico {
+		campos = append(campos, bson.E{Key: "status", Value: model.CobrancaConcluida})
+	}
+	registro = append(registro, bson.E{Key: "$set", Value: campos})
+
+	filter := bson.D{
+		{Key: "_id", Value: cob.ID},
+		{Key: "data_type", Value: "cobranca_pix"},
+		{Key: "pagamentos.end_to_end_id", Value: bson.D{{Key: "$ne", Value: pagamento.EndToEndID}}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	atualizada := &model.CobrancaPix{}
+	err = pds.mdb.GetCollection("cfStore").FindOneAndUpdate(ctx, filter, registro, opts).Decode(atualizada)
+	if err != nil {
+		// Pagamento já registrado numa entrega anterior.
+		if err == mongo.ErrNoDocuments {
+			return pds.GetByTxID(ctx, pagamento.TxID)
+		}
+		logger.Error("erro ao registrar pagamento da cobranca Pix", err)
+		return nil, err
+	}
+
+	return atualizada, nil
+}
+
+// tarifa calcula a taxa do meio Pix sobre o valor recebido. O dinheiro já
+// entrou, então limites e meio desativado não recusam o crédito.
+func (pds *PixDataService) tarifa(ctx context.Context, valor int64) (int64, error) {
+	meio, err := pds.meios.GetByNome(ctx, model.MeioPagPix)
+	if err != nil {
+		if errors.Is(err, meiopag.ErrMeioPagamentoNaoEncontrado) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if !meio.NoLimite(valor) {
+		logger.Info(fmt.Sprintf("Pix de %d centavos fora dos limites do meio %s", valor, meio.MeioPg))
+	}
+
+	return meio.Tarifa(valor), nil
+}
+
+// criarIndices garante um txid por cobrança; é por ele que o webhook acha a cobrança.
+func (pds *PixDataService) criarIndices(ctx context.Context) error {
+	_, err := pds.mdb.GetCollection("cfStore").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "txid", Value: 1}},
+		Options: options.Index().
+			SetName("pix_cobranca_txid").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.D{{Key: "data_type", Value: "cobranca_pix"}}),
+	})
+	return err
+}
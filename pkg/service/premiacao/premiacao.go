@@ -0,0 +1,408 @@
+package premiacao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrPremioNaoEncontrado = errors.New("prêmio não encontrado")
+	ErrPremioJaPago        = errors.New("prêmio já creditado")
+	ErrSorteioNaoLiquidado = errors.New("sorteio ainda não liquidado")
+)
+
+type PremiacaoServiceInterface interface {
+	PagarSorteio(ctx context.Context, str *model.Sorteio) error
+	Reprocessar(ctx context.Context, sorteioID string) error
+	Resgatar(ctx context.Context, ID string, usuario string) (*model.PagamentoPremio, error)
+	GetByID(ctx context.Context, ID string) (*model.PagamentoPremio, error)
+	GetAll(ctx context.Context, filters model.FilterPagamentoPremio, limit, page int64) (*model.Paginate, error)
+}
+
+// PremiacaoDataService paga os prêmios dos sorteios liquidados na carteira dos
+// ganhadores, retendo o IRRF. Cada prêmio é provisionado da receita para os
+// prêmios a pagar e creditado em seguida, ou fica esperando resgate manual
+// quando passa de conf.PremioResgateManual.
+type PremiacaoDataService struct {
+	mdb      mongodb.MongoDBInterface
+	sorteios sorteio.SorteioServiceInterface
+	carteira carteira.CarteiraServiceInterface
+	conf     *config.Config
+}
+
+func NewPremiacaoService(mongo_connection mongodb.MongoDBInterface, sorteios sorteio.SorteioServiceInterface, carteira_cliente carteira.CarteiraServiceInterface, conf *config.Config) *PremiacaoDataService {
+	pds := &PremiacaoDataService{
+		mdb:      mongo_connection,
+		sorteios: sorteios,
+		carteira: carteira_cliente,
+		conf:     conf,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := pds.criarIndices(ctx); err != nil {
+		logger.Error("erro ao criar indices da premiacao", err)
+	}
+
+	return pds
+}
+
+// PagarSorteio registra os prêmios do sorteio e credita os pendentes. É
+// registrada como etapa posterior à liquidação, depois dos bolões, e pode ser
+// repetida: prêmios já registrados ou creditados não se repetem.
+func (pds *PremiacaoDataService) PagarSorteio(ctx context.Context, str *model.Sorteio) error {
+	if str.Status != model.SorteioLiquidado {
+		return ErrSorteioNaoLiquidado
+	}
+
+	premios, err := pds.apurarPremios(ctx, str)
+	if err != nil {
+		return err
+	}
+
+	if err := pds.registrar(ctx, premios); err != nil {
+		return err
+	}
+
+	registrados, err := pds.premiosDoSorteio(ctx, str.ID)
+	if err != nil {
+		return err
+	}
+
+	var erros []error
+	for _, prm := range registrados {
+		if _, err := pds.carteira.ProvisionarPremio(ctx, prm.SorteioID, prm.Bruto, prm.ChaveLancamento()); err != nil {
+			erros = append(erros, fmt.Errorf("prêmio %s: %w", prm.ChaveLancamento(), err))
+			continue
+		}
+		if prm.Status != model.PremioPendente {
+			continue
+		}
+		if _, err := pds.creditar(ctx, prm, model.PremioPendente, ""); err != nil {
+			erros = append(erros, fmt.Errorf("prêmio %s: %w", prm.ChaveLancamento(), err))
+		}
+	}
+
+	return errors.Join(erros...)
+}
+
+// Reprocessar refaz o pagamento de um sorteio liquidado, para os prêmios que
+// falharam ou os bolões liquidados depois do sorteio.
+func (pds *PremiacaoDataService) Reprocessar(ctx context.Context, sorteioID string) error {
+	str, err := pds.sorteios.GetByID(ctx, sorteioID)
+	if err != nil {
+		return err
+	}
+
+	return pds.PagarSorteio(ctx, str)
+}
+
+// Resgatar credita um prêmio que esperava resgate manual, depois da
+// conferência feita pelo administrador.
+func (pds *PremiacaoDataService) Resgatar(ctx context.Context, ID string, usuario string) (*model.PagamentoPremio, error) {
+	prm, err := pds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if prm.Status == model.PremioCreditado {
+		return nil, ErrPremioJaPago
+	}
+
+	if _, err := pds.carteira.ProvisionarPremio(ctx, prm.SorteioID, prm.Bruto, prm.ChaveLancamento()); err != nil {
+		return nil, err
+	}
+
+	return pds.creditar(ctx, prm, model.PremioAguardandoResgate, usuario)
+}
+
+func (pds *PremiacaoDataService) GetByID(ctx context.Context, ID string) (*model.PagamentoPremio, error) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return nil, ErrPremioNaoEncontrado
+	}
+
+	prm := &model.PagamentoPremio{}
+	err = pds.mdb.GetCollection("cfStore").FindOne(ctx, bson.D{
+		{Key: "data_type", Value: "pagamento_premio"},
+		{Key: "_id", Value: objectID},
+	}).Decode(prm)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrPremioNaoEncontrado
+		}
+		logger.Error("erro ao consultar pagamento de premio", err)
+		return nil, err
+	}
+
+	return prm, nil
+}
+
+func (pds *PremiacaoDataService) GetAll(ctx context.Context, filters model.FilterPagamentoPremio, limit, page int64) (*model.Paginate, error) {
+	collection := pds.mdb.GetCollection("cfStore")
+
+	query := bson.M{"data_type": "pagamento_premio"}
+	if !filters.SorteioID.IsZero() {
+		query["sorteio_id"] = filters.SorteioID
+	}
+	if !filters.ClienteID.IsZero() {
+		query["cliente_id"] = filters.ClienteID
+	}
+	if filters.Status != "" {
+		query["status"] = filters.Status
+	}
+
+	count, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		logger.Error("erro ao consultar todos os pagamentos de premio", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+
+	opts := pagination.GetPaginatedOpts().SetSort(bson.D{{Key: "bruto", Value: -1}})
+	curr, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]*model.PagamentoPremio, 0)
+	for curr.Next(ctx) {
+		prm := &model.PagamentoPremio{}
+		if err := curr.Decode(prm); err != nil {
+			logger.Error("erro ao consultar todos os pagamentos de premio", err)
+			continue
+		}
+		result = append(result, prm)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+// apurarPremios monta os prêmios do sorteio. Apostas de bolão não pagam o
+// organizador direto: o prêmio delas vai para as cotas e a parte do
+// organizador quando o bolão é liquidado. Se o bolão foi cancelado, as
+// apostas continuam do organizador e pagam como apostas comuns.
+func (pds *PremiacaoDataService) apurarPremios(ctx context.Context, str *model.Sorteio) ([]*model.PagamentoPremio, error) {
+	collection := pds.mdb.GetCollection("cfStore")
+
+	boloes := make(map[primitive.ObjectID]*model.Bolao)
+	curr, err := collection.Find(ctx, bson.D{
+		{Key: "data_type", Value: "bolao"},
+		{Key: "sorteio_id", Value: str.ID},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar Boloes do Sorteio", err)
+		return nil, err
+	}
+	for curr.Next(ctx) {
+		blo := &model.Bolao{}
+		if err := curr.Decode(blo); err != nil {
+			curr.Close(ctx)
+			return nil, err
+		}
+		boloes[blo.ID] = blo
+	}
+	curr.Close(ctx)
+
+	result := make([]*model.PagamentoPremio, 0)
+
+	curr, err = collection.Find(ctx, bson.D{
+		{Key: "data_type", Value: "aposta"},
+		{Key: "sorteio_id", Value: str.ID},
+		{Key: "status", Value: model.ApostaAceita},
+		{Key: "premio", Value: bson.D{{Key: "$gt", Value: 0}}},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar Apostas premiadas do Sorteio", err)
+		return nil, err
+	}
+	for curr.Next(ctx) {
+		apt := &model.Aposta{}
+		if err := curr.Decode(apt); err != nil {
+			curr.Close(ctx)
+			return nil, err
+		}
+		if blo, ok := boloes[apt.BolaoID]; ok && blo.Status != model.BolaoCancelado {
+			continue
+		}
+		result = append(result, pds.novoPremio(str, apt.ClienteID, model.OrigemAposta, apt.ID.Hex(), apt.Premio, apt.Premio))
+	}
+	curr.Close(ctx)
+
+	for _, blo := range boloes {
+		if blo.Status != model.BolaoLiquidado || blo.Premio <= 0 {
+			continue
+		}
+
+		// A retenção é decidida pelo prêmio do bolão inteiro, como no bilhete.
+		curr, err := collection.Find(ctx, bson.D{
+			{Key: "data_type", Value: "cota_bolao"},
+			{Key: "bolao_id", Value: blo.ID},
+			{Key: "premio", Value: bson.D{{Key: "$gt", Value: 0}}},
+		})
+		if err != nil {
+			logger.Error("erro ao consultar Cotas premiadas do Bolao", err)
+			return nil, err
+		}
+		for curr.Next(ctx) {
+			cta := &model.CotaBolao{}
+			if err := curr.Decode(cta); err != nil {
+				curr.Close(ctx)
+				return nil, err
+			}
+			result = append(result, pds.novoPremio(str, cta.ClienteID, model.OrigemCota, cta.ID.Hex(), cta.Premio, blo.Premio))
+		}
+		curr.Close(ctx)
+
+		if blo.PremioOrganizador > 0 {
+			result = append(result, pds.novoPremio(str, blo.OrganizadorID, model.OrigemOrganizador, blo.ID.Hex(), blo.PremioOrganizador, blo.Premio))
+		}
+	}
+
+	return result, nil
+}
+
+// novoPremio calcula o IRRF do prêmio. base é o valor que decide a isenção:
+// o próprio prêmio ou, nas partes de bolão, o prêmio do bolão.
+func (pds *PremiacaoDataService) novoPremio(str *model.Sorteio, clienteID primitive.ObjectID, origem, referencia string, bruto, base int64) *model.PagamentoPremio {
+	var aliquota int64
+	if base > pds.conf.IRRFIsencao {
+		aliquota = pds.conf.IRRFAliquota
+	}
+
+	status := model.PremioPendente
+	if pds.conf.PremioResgateManual > 0 && bruto > pds.conf.PremioResgateManual {
+		status = model.PremioAguardandoResgate
+	}
+
+	return model.NewPagamentoPremio(model.PagamentoPremio{
+		SorteioID:  str.ID,
+		ClienteID:  clienteID,
+		Origem:     origem,
+		Referencia: referencia,
+		Bruto:      bruto,
+		Aliquota:   aliquota,
+		Imposto:    bruto * aliquota / model.BaseCalculo,
+		Status:     status,
+	})
+}
+
+// registrar grava só os prêmios ainda não registrados; os existentes mantêm
+// valores e estado.
+func (pds *PremiacaoDataService) registrar(ctx context.Context, premios []*model.PagamentoPremio) error {
+	if len(premios) == 0 {
+		return nil
+	}
+
+	lote := make([]mongo.WriteModel, 0, len(premios))
+	for _, prm := range premios {
+		lote = append(lote, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{
+				{Key: "data_type", Value: "pagamento_premio"},
+				{Key: "origem", Value: prm.Origem},
+				{Key: "referencia", Value: prm.Referencia},
+			}).
+			SetUpdate(bson.D{{Key: "$setOnInsert", Value: prm}}).
+			SetUpsert(true))
+	}
+
+	_, err := pds.mdb.GetCollection("cfStore").BulkWrite(ctx, lote, options.BulkWrite().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		logger.Error("erro ao registrar premios do Sorteio", err)
+		return err
+	}
+
+	return nil
+}
+
+func (pds *PremiacaoDataService) premiosDoSorteio(ctx context.Context, sorteioID primitive.ObjectID) ([]*model.PagamentoPremio, error) {
+	curr, err := pds.mdb.GetCollection("cfStore").Find(ctx, bson.D{
+		{Key: "data_type", Value: "pagamento_premio"},
+		{Key: "sorteio_id", Value: sorteioID},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar premios do Sorteio", err)
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]*model.PagamentoPremio, 0)
+	for curr.Next(ctx) {
+		prm := &model.PagamentoPremio{}
+		if err := curr.Decode(prm); err != nil {
+			return nil, err
+		}
+		result = append(result, prm)
+	}
+
+	return result, curr.Err()
+}
+
+// creditar lança o prêmio líquido na carteira e marca o prêmio como
+// creditado, desde que ainda esteja no estado esperado. O lançamento não se
+// repete, então uma falha entre os dois passos é corrigida repetindo.
+func (pds *PremiacaoDataService) creditar(ctx context.Context, prm *model.PagamentoPremio, de string, usuario string) (*model.PagamentoPremio, error) {
+	lct, err := pds.carteira.CreditarPremio(ctx, prm.ClienteID, prm.Bruto, prm.Imposto, prm.ChaveLancamento())
+	if err != nil {
+		return nil, err
+	}
+
+	campos := bson.D{
+		{Key: "status", Value: model.PremioCreditado},
+		{Key: "lancamento_id", Value: lct.ID},
+		{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+	}
+	if usuario != "" {
+		campos = append(campos, bson.E{Key: "resgatado_por", Value: usuario})
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: prm.ID},
+		{Key: "data_type", Value: "pagamento_premio"},
+		{Key: "status", Value: de},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	result := &model.PagamentoPremio{}
+	err = pds.mdb.GetCollection("cfStore").FindOneAndUpdate(ctx, filter, bson.D{{Key: "$set", Value: campos}}, opts).Decode(result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return pds.GetByID(ctx, prm.ID.Hex())
+		}
+		logger.Error("erro ao marcar premio creditado", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// criarIndices garante um registro por prêmio de origem.
+func (pds *PremiacaoDataService) criarIndices(ctx context.Context) error {
+	_, err := pds.mdb.GetCollection("cfStore").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "origem", Value: 1},
+			{Key: "referencia", Value: 1},
+		},
+		Options: options.Index().
+			SetName("premio_origem").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.D{{Key: "data_type", Value: "pagamento_premio"}}),
+	})
+	return err
+}
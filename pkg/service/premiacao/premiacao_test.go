@@ -0,0 +1,160 @@
+package premiacao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// mongoTeste entrega a coleção do mtest em modo mock.
+type mongoTeste struct {
+	mt *mtest.T
+}
+
+func (m mongoTeste) GetCollection(string) *mongo.Collection       { return m.mt.Coll }
+func (m mongoTeste) GetCollectionByName(string) *mongo.Collection { return m.mt.Coll }
+
+func (m mongoTeste) WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) (interface{}, error)) (interface{}, error) {
+	sess, err := m.mt.Client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.EndSession(ctx)
+	return fn(mongo.NewSessionContext(ctx, sess))
+}
+
+// confTeste usa a alíquota e a faixa de isenção padrão do IRRF.
+func confTeste(resgateManual int64) *config.Config {
+	return &config.Config{IRRFAliquota: 3000, IRRFIsencao: 190398, PremioResgateManual: resgateManual}
+}
+
+func TestNovoPremio(t *testing.T) {
+	str := &model.Sorteio{ID: primitive.NewObjectID()}
+	cliente := primitive.NewObjectID()
+
+	tests := []struct {
+		name          string
+		bruto         int64
+		base          int64
+		resgateManual int64
+		wantAliquota  int64
+		wantImposto   int64
+		wantLiquido   int64
+		wantStatus    string
+	}{
+		{"abaixo da isenção", 100000, 100000, 0, 0, 0, 100000, model.PremioPendente},
+		{"no limite da isenção", 190398, 190398, 0, 0, 0, 190398, model.PremioPendente},
+		{"um centavo acima da isenção", 190399, 190399, 0, 3000, 57119, 133280, model.PremioPendente},
+		{"cota pequena de bolão acima da isenção", 50000, 1000000, 0, 3000, 15000, 35000, model.PremioPendente},
+		{"cota de bolão isento", 50000, 150000, 0, 0, 0, 50000, model.PremioPendente},
+		{"no limite do resgate manual", 500000, 500000, 500000, 3000, 150000, 350000, model.PremioPendente},
+		{"acima do resgate manual", 500001, 500001, 500000, 3000, 150000, 350001, model.PremioAguardandoResgate},
+		{"sem resgate manual configurado", 50000000, 50000000, 0, 3000, 15000000, 35000000, model.PremioPendente},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pds := &PremiacaoDataService{conf: confTeste(tt.resgateManual)}
+
+			prm := pds.novoPremio(str, cliente, model.OrigemAposta, "apt-1", tt.bruto, tt.base)
+			if prm.Aliquota != tt.wantAliquota {
+				t.Errorf("Aliquota = %d, want %d", prm.Aliquota, tt.wantAliquota)
+			}
+			if prm.Imposto != tt.wantImposto {
+				t.Errorf("Imposto = %d, want %d", prm.Imposto, tt.wantImposto)
+			}
+			if prm.Liquido != tt.wantLiquido {
+				t.Errorf("Liquido = %d, want %d", prm.Liquido, tt.wantLiquido)
+			}
+			if prm.Bruto != prm.Imposto+prm.Liquido {
+				t.Errorf("Bruto %d != Imposto %d + Liquido %d", prm.Bruto, prm.Imposto, prm.Liquido)
+			}
+			if prm.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", prm.Status, tt.wantStatus)
+			}
+			if prm.SorteioID != str.ID || prm.ClienteID != cliente || prm.ChaveLancamento() != "aposta:apt-1" {
+				t.Errorf("novoPremio = %+v, want o prêmio de apt-1 do cliente no sorteio", prm)
+			}
+		})
+	}
+}
+
+// documento converte o modelo no documento que o mock devolve.
+func documento(t *testing.T, v interface{}) bson.D {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("bson.Unmarshal: %v", err)
+	}
+	return doc
+}
+
+func cursor(docs ...bson.D) bson.D {
+	return mtest.CreateCursorResponse(0, "fortuna.cfStore", mtest.FirstBatch, docs...)
+}
+
+// TestApurarPremios confere quem recebe e qual valor decide a retenção: as
+// apostas do bolão liquidado não pagam direto, e as cotas e a parte do
+// organizador retêm pelo prêmio do bolão inteiro.
+func TestApurarPremios(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("sorteio com bolão liquidado", func(mt *mtest.T) {
+		str := &model.Sorteio{ID: primitive.NewObjectID()}
+		blo := model.Bolao{
+			ID:                primitive.NewObjectID(),
+			SorteioID:         str.ID,
+			OrganizadorID:     primitive.NewObjectID(),
+			Status:            model.BolaoLiquidado,
+			Premio:            1000000,
+			PremioOrganizador: 100000,
+		}
+		comum := model.Aposta{ID: primitive.NewObjectID(), ClienteID: primitive.NewObjectID(), Status: model.ApostaAceita, Premio: 150000}
+		doBolao := model.Aposta{ID: primitive.NewObjectID(), ClienteID: blo.OrganizadorID, Status: model.ApostaAceita, Premio: 1000000, BolaoID: blo.ID}
+		cta := model.CotaBolao{ID: primitive.NewObjectID(), BolaoID: blo.ID, ClienteID: primitive.NewObjectID(), Premio: 50000}
+
+		mt.AddMockResponses(
+			cursor(documento(t, blo)),
+			cursor(documento(t, comum), documento(t, doBolao)),
+			cursor(documento(t, cta)),
+		)
+		pds := &PremiacaoDataService{mdb: mongoTeste{mt: mt}, conf: confTeste(0)}
+
+		premios, err := pds.apurarPremios(context.Background(), str)
+		if err != nil {
+			mt.Fatalf("apurarPremios: %v", err)
+		}
+
+		want := map[string]struct {
+			cliente primitive.ObjectID
+			bruto   int64
+			imposto int64
+		}{
+			model.OrigemAposta + ":" + comum.ID.Hex():    {comum.ClienteID, 150000, 0},
+			model.OrigemCota + ":" + cta.ID.Hex():        {cta.ClienteID, 50000, 15000},
+			model.OrigemOrganizador + ":" + blo.ID.Hex(): {blo.OrganizadorID, 100000, 30000},
+		}
+		if len(premios) != len(want) {
+			mt.Fatalf("%d prêmios, want %d: %+v", len(premios), len(want), premios)
+		}
+		for _, prm := range premios {
+			w, ok := want[prm.ChaveLancamento()]
+			if !ok {
+				mt.Errorf("prêmio inesperado %s", prm.ChaveLancamento())
+				continue
+			}
+			if prm.ClienteID != w.cliente || prm.Bruto != w.bruto || prm.Imposto != w.imposto {
+				mt.Errorf("%s = cliente %s, bruto %d, imposto %d; want cliente %s, bruto %d, imposto %d",
+					prm.ChaveLancamento(), prm.ClienteID.Hex(), prm.Bruto, prm.Imposto, w.cliente.Hex(), w.bruto, w.imposto)
+			}
+		}
+	})
+}
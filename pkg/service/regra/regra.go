@@ -0,0 +1,299 @@
+package regra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/regras"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrRegraNaoEncontrada = errors.New("regra não encontrada")
+	ErrRegraInvalida      = errors.New("dados da regra inválidos")
+	ErrRegraDuplicada     = errors.New("já existe regra para o jogo")
+)
+
+type RegraServiceInterface interface {
+	Create(ctx context.Context, regra model.RegraJogo) (*model.RegraJogo, error)
+	Update(ctx context.Context, ID string, regraToChange *model.RegraJogo) (bool, error)
+	GetByID(ctx context.Context, ID string) (*model.RegraJogo, error)
+	GetAll(ctx context.Context, filters model.FilterRegraJogo, limit, page int64) (*model.Paginate, error)
+	Avaliar(ctx context.Context, str *model.Sorteio, aposta model.Aposta) ([]model.ErroCampo, error)
+	AvaliarLimites(ctx context.Context, str *model.Sorteio, clienteID primitive.ObjectID, bilhetes, valor int64) ([]model.ErroCampo, error)
+}
+
+type RegraDataService struct {
+	mdb mongodb.MongoDBInterface
+}
+
+func NewRegraService(mongo_connection mongodb.MongoDBInterface) *RegraDataService {
+	return &RegraDataService{
+		mdb: mongo_connection,
+	}
+}
+
+func (rds *RegraDataService) Create(ctx context.Context, regra model.RegraJogo) (*model.RegraJogo, error) {
+	collection := rds.mdb.GetCollection("cfStore")
+
+	rgr := model.NewRegraJogo(regra)
+	if err := regras.ValidarRegra(rgr); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRegraInvalida, err.Error())
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.D{
+		{Key: "data_type", Value: "regra_jogo"},
+		{Key: "jogo", Value: rgr.Jogo},
+	})
+	if err != nil {
+		logger.Error("erro ao consultar Regra do jogo", err)
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("%w %q", ErrRegraDuplicada, rgr.Jogo)
+	}
+
+	result, err := collection.InsertOne(ctx, rgr)
+	if err != nil {
+		logger.Error("erro salvar Regra", err)
+		return nil, err
+	}
+
+	rgr.ID = result.InsertedID.(primitive.ObjectID)
+
+	return rgr, nil
+}
+
+// Update troca os limites da regra; o jogo não muda.
+func (rds *RegraDataService) Update(ctx context.Context, ID string, regra *model.RegraJogo) (bool, error) {
+	collection := rds.mdb.GetCollection("cfStore")
+
+	atual, err := rds.GetByID(ctx, ID)
+	if err != nil {
+		return false, err
+	}
+
+	atual.CorteMinutos = regra.CorteMinutos
+	atual.MaxNumeros = regra.MaxNumeros
+	atual.MaxBilhetesCliente = regra.MaxBilhetesCliente
+	atual.MaxValorCliente = regra.MaxValorCliente
+	atual.Enabled = regra.Enabled
+
+	if err := regras.ValidarRegra(atual); err != nil {
+		return false, fmt.Errorf("%w: %s", ErrRegraInvalida, err.Error())
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: atual.ID},
+		{Key: "data_type", Value: "regra_jogo"},
+	}
+
+	update := bson.D{{Key: "$set",
+		Value: bson.D{
+			{Key: "corte_minutos", Value: atual.CorteMinutos},
+			{Key: "max_numeros", Value: atual.MaxNumeros},
+			{Key: "max_bilhetes_cliente", Value: atual.MaxBilhetesCliente},
+			{Key: "max_valor_cliente", Value: atual.MaxValorCliente},
+			{Key: "enabled", Value: atual.Enabled},
+			{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+		},
+	}}
+
+	_, err = collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error while updating data", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (rds *RegraDataService) GetByID(ctx context.Context, ID string) (*model.RegraJogo, error) {
+	collection := rds.mdb.GetCollection("cfStore")
+
+	regra := &model.RegraJogo{}
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		logger.Error("Error to parse ObjectIDFromHex", err)
+		return nil, ErrRegraNaoEncontrada
+	}
+
+	filter := bson.D{
+		{Key: "data_type", Value: "regra_jogo"},
+		{Key: "_id", Value: objectID},
+	}
+
+	err = collection.FindOne(ctx, filter).Decode(regra)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRegraNaoEncontrada
+		}
+		logger.Error("erro ao consultar Regra", err)
+		return nil, err
+	}
+
+	return regra, nil
+}
+
+func (rds *RegraDataService) GetAll(ctx context.Context, filters model.FilterRegraJogo, limit, page int64) (*model.Paginate, error) {
+	collection := rds.mdb.GetCollection("cfStore")
+
+	query := bson.M{"data_type": "regra_jogo"}
+
+	if filters.Jogo != "" {
+		query["jogo"] = filters.Jogo
+	}
+	if filters.Enabled != "" {
+		enable, err := strconv.ParseBool(filters.Enabled)
+		if err != nil {
+			logger.Error("erro converter campo enabled", err)
+			return nil, err
+		}
+		query["enabled"] = enable
+	}
+
+	count, err := collection.CountDocuments(ctx, query, &options.CountOptions{})
+	if err != nil {
+		logger.Error("erro ao consultar todas as Regras", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+
+	curr, err := collection.Find(ctx, query, pagination.GetPaginatedOpts())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.RegraJogo, 0)
+	for curr.Next(ctx) {
+		rgr := &model.RegraJogo{}
+		if err := curr.Decode(rgr); err != nil {
+			logger.Error("erro ao consulta todas as Regras", err)
+		}
+		result = append(result, rgr)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+// Avaliar aplica as regras do jogo do sorteio a uma aposta de um bilhete. A
+// própria aposta, quando já gravada, fica fora do consumo do cliente.
+func (rds *RegraDataService) Avaliar(ctx context.Context, str *model.Sorteio, aposta model.Aposta) ([]model.ErroCampo, error) {
+	game, err := jogo.Obter(str.Jogo)
+	if err != nil {
+		return []model.ErroCampo{{Campo: "sorteio_id", Codigo: regras.CodigoNaoPermitido, Mensagem: err.Error()}}, nil
+	}
+
+	regra, err := rds.regraAtiva(ctx, str.Jogo)
+	if err != nil {
+		return nil, err
+	}
+
+	entrada := regras.Entrada{Sorteio: str, Regra: regra, Agora: time.Now()}
+	if regra != nil && !aposta.ClienteID.IsZero() {
+		entrada.Consumo, err = rds.consumo(ctx, str.ID, aposta.ClienteID, aposta.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return regras.Avaliar(game, entrada, aposta), nil
+}
+
+// AvaliarLimites confere se o cliente pode levar mais bilhetes no valor
+// total informado, para vendas que não passam por Avaliar, como a rifa.
+func (rds *RegraDataService) AvaliarLimites(ctx context.Context, str *model.Sorteio, clienteID primitive.ObjectID, bilhetes, valor int64) ([]model.ErroCampo, error) {
+	regra, err := rds.regraAtiva(ctx, str.Jogo)
+	if err != nil || regra == nil {
+		return nil, err
+	}
+
+	consumo, err := rds.consumo(ctx, str.ID, clienteID, primitive.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	erros := regras.Venda(regras.Entrada{Sorteio: str, Regra: regra, Agora: time.Now()})
+	return append(erros, regras.Limites(regra, consumo, bilhetes, valor)...), nil
+}
+
+// regraAtiva devolve a regra habilitada do jogo, ou nil quando não há.
+func (rds *RegraDataService) regraAtiva(ctx context.Context, nome string) (*model.RegraJogo, error) {
+	collection := rds.mdb.GetCollection("cfStore")
+
+	regra := &model.RegraJogo{}
+	filter := bson.D{
+		{Key: "data_type", Value: "regra_jogo"},
+		{Key: "jogo", Value: nome},
+		{Key: "enabled", Value: true},
+	}
+
+	err := collection.FindOne(ctx, filter).Decode(regra)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Error("erro ao consultar Regra do jogo", err)
+		return nil, err
+	}
+
+	return regra, nil
+}
+
+// consumo soma as apostas pendentes e aceitas do cliente no sorteio.
+func (rds *RegraDataService) consumo(ctx context.Context, sorteioID, clienteID, excluir primitive.ObjectID) (regras.Consumo, error) {
+	collection := rds.mdb.GetCollection("cfStore")
+
+	match := bson.D{
+		{Key: "data_type", Value: "aposta"},
+		{Key: "sorteio_id", Value: sorteioID},
+		{Key: "cliente_id", Value: clienteID},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{model.ApostaPendente, model.ApostaAceita}}}},
+	}
+	if !excluir.IsZero() {
+		match = append(match, bson.E{Key: "_id", Value: bson.D{{Key: "$ne", Value: excluir}}})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "bilhetes", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "valor", Value: bson.D{{Key: "$sum", Value: "$valor"}}},
+		}}},
+	}
+
+	curr, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("erro ao somar Apostas do cliente", err)
+		return regras.Consumo{}, err
+	}
+	defer curr.Close(ctx)
+
+	var total struct {
+		Bilhetes int64 `bson:"bilhetes"`
+		Valor    int64 `bson:"valor"`
+	}
+	if curr.Next(ctx) {
+		if err := curr.Decode(&total); err != nil {
+			logger.Error("erro ao somar Apostas do cliente", err)
+			return regras.Consumo{}, err
+		}
+	}
+
+	return regras.Consumo{Bilhetes: total.Bilhetes, Valor: total.Valor}, nil
+}
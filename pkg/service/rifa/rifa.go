@@ -0,0 +1,470 @@
+// Package rifa controla a venda de bilhetes numerados de sorteios do tipo
+// rifa. Os números escolhidos ficam reservados no Redis por alguns minutos;
+// confirmada a reserva eles passam a vendidos, o cliente é debitado na
+// carteira e os números viram apostas aceitas no sorteio.
+//
+// Chaves usadas por sorteio (o {id} entre chaves mantém todas no mesmo slot):
+//
+//	rifa:{id}:vendidos      SET com os números vendidos
+//	rifa:{id}:hold:<n>      id da reserva que segura o número n, com TTL
+//	rifa:{id}:reserva:<r>   HASH com o cliente e os números da reserva r, com TTL
+//	rifa:{id}:cliente:<c>   SET com as reservas do cliente c, com TTL
+//
+// Toda leitura e escrita dessas chaves é feita por scripts Lua, de modo que
+// duas reservas concorrentes nunca ficam com o mesmo número.
+package rifa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/redisdb"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/comprovante"
+	"github.com/katana/fortuna/backend-go/pkg/service/regra"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/regras"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// LimiteReserva é a quantidade máxima de números reservados e ainda não
+// confirmados de um cliente, somadas todas as reservas ativas dele.
+const LimiteReserva = 100
+
+var (
+	ErrNaoERifa             = errors.New("sorteio não é do tipo rifa")
+	ErrVendasEncerradas     = errors.New("vendas do sorteio não estão abertas")
+	ErrReservaInvalida      = errors.New("reserva inválida")
+	ErrNumeroIndisponivel   = errors.New("número indisponível")
+	ErrReservaNaoEncontrada = errors.New("reserva não encontrada ou expirada")
+)
+
+// contarReservados soma os números das reservas ainda ativas do cliente,
+// esquecendo as que já expiraram ou foram confirmadas.
+const contarReservados = `
+local reservados = 0
+for _, r in ipairs(redis.call('SMEMBERS', KEYS[1])) do
+	local n = redis.call('HLEN', ARGV[1] .. r)
+	if n == 0 then redis.call('SREM', KEYS[1], r) else reservados = reservados + n - 1 end
+end
+`
+
+// scriptReservados devolve quantos números o cliente tem reservados.
+// KEYS: reservas do cliente; ARGV: prefixo das reservas.
+const scriptReservados = contarReservados + `
+return reservados
+`
+
+// scriptReservar confere que o cliente não passa do limite de números
+// reservados e que nenhum número está vendido ou reservado e, só então,
+// segura todos eles para a reserva.
+// KEYS: reservas do cliente, vendidos, reserva, holds...
+// ARGV: prefixo das reservas, id da reserva, ttl em ms, cliente, limite, números...
+const scriptReservar = contarReservados + `
+if reservados + #KEYS - 3 > tonumber(ARGV[5]) then return {0, reservados, 'limite'} end
+for i = 4, #KEYS do
+	local n = ARGV[i + 2]
+	if redis.call('SISMEMBER', KEYS[2], n) == 1 then return {0, n, 'vendido'} end
+	if redis.call('EXISTS', KEYS[i]) == 1 then return {0, n, 'reservado'} end
+end
+for i = 4, #KEYS do
+	redis.call('SET', KEYS[i], ARGV[2], 'PX', ARGV[3])
+	redis.call('HSET', KEYS[3], ARGV[i + 2], 1)
+end
+redis.call('HSET', KEYS[3], 'cliente', ARGV[4])
+redis.call('PEXPIRE', KEYS[3], ARGV[3])
+redis.call('SADD', KEYS[1], ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {1}
+`
+
+// scriptConfirmar converte os números ainda seguros pela reserva do cliente
+// em vendidos.
+// KEYS: vendidos, reserva; ARGV: id da reserva, prefixo dos holds, cliente.
+const scriptConfirmar = `
+local campos = redis.call('HGETALL', KEYS[2])
+if #campos == 0 then return {0} end
+local cliente = ''
+local numeros = {}
+for i = 1, #campos, 2 do
+	if campos[i] == 'cliente' then cliente = campos[i + 1] else table.insert(numeros, campos[i]) end
+end
+if cliente ~= ARGV[3] then return {0} end
+for _, n in ipairs(numeros) do
+	if redis.call('GET', ARGV[2] .. n) ~= ARGV[1] then return {0} end
+end
+for _, n in ipairs(numeros) do
+	redis.call('SADD', KEYS[1], n)
+	redis.call('DEL', ARGV[2] .. n)
+end
+redis.call('DEL', KEYS[2])
+local r = {1, cliente}
+for _, n in ipairs(numeros) do table.insert(r, n) end
+return r
+`
+
+// scriptLiberar solta os números que ainda pertencem à reserva do cliente.
+// KEYS: reserva; ARGV: id da reserva, prefixo dos holds, cliente.
+const scriptLiberar = `
+if redis.call('HGET', KEYS[1], 'cliente') ~= ARGV[3] then return 0 end
+local campos = redis.call('HGETALL', KEYS[1])
+for i = 1, #campos, 2 do
+	if campos[i] ~= 'cliente' and redis.call('GET', ARGV[2] .. campos[i]) == ARGV[1] then
+		redis.call('DEL', ARGV[2] .. campos[i])
+	end
+end
+redis.call('DEL', KEYS[1])
+return #campos
+`
+
+// scriptEstornar desfaz a venda quando as apostas não puderam ser gravadas.
+// KEYS: vendidos; ARGV: números...
+const scriptEstornar = `
+for i = 1, #ARGV do redis.call('SREM', KEYS[1], ARGV[i]) end
+return #ARGV
+`
+
+// scriptDisponiveis percorre os números da rifa separando vendidos, reservados e livres.
+// KEYS: vendidos; ARGV: prefixo dos holds, total de números.
+const scriptDisponiveis = `
+local vendidos, reservados = 0, 0
+local livres = {}
+for n = 0, tonumber(ARGV[2]) - 1 do
+	if redis.call('SISMEMBER', KEYS[1], n) == 1 then
+		vendidos = vendidos + 1
+	elseif redis.call('EXISTS', ARGV[1] .. n) == 1 then
+		reservados = reservados + 1
+	else
+		table.insert(livres, n)
+	end
+end
+return {vendidos, reservados, livres}
+`
+
+type RifaServiceInterface interface {
+	Reservar(ctx context.Context, sorteioID string, reserva model.ReservaRifa) (*model.ReservaRifa, error)
+	Confirmar(ctx context.Context, sorteioID string, reservaID string, clienteID primitive.ObjectID) ([]*model.Aposta, error)
+	Liberar(ctx context.Context, sorteioID string, reservaID string, clienteID primitive.ObjectID) error
+	Disponiveis(ctx context.Context, sorteioID string) (*model.DisponibilidadeRifa, error)
+}
+
+type RifaDataService struct {
+	mdb      mongodb.MongoDBInterface
+	rdb      redisdb.RedisClientInterface
+	sorteios sorteio.SorteioServiceInterface
+	regras   regra.RegraServiceInterface
+	carteira carteira.CarteiraServiceInterface
+	conf     *config.Config
+}
+
+func NewRifaService(mongo_connection mongodb.MongoDBInterface, redis_connection redisdb.RedisClientInterface, sorteios sorteio.SorteioServiceInterface, regras_jogo regra.RegraServiceInterface, carteira_cliente carteira.CarteiraServiceInterface, conf *config.Config) *RifaDataService {
+	return &RifaDataService{
+		mdb:      mongo_connection,
+		rdb:      redis_connection,
+		sorteios: sorteios,
+		regras:   regras_jogo,
+		carteira: carteira_cliente,
+		conf:     conf,
+	}
+}
+
+func (rs *RifaDataService) Reservar(ctx context.Context, sorteioID string, reserva model.ReservaRifa) (*model.ReservaRifa, error) {
+	str, rifa, err := rs.carregar(ctx, sorteioID)
+	if err != nil {
+		return nil, err
+	}
+
+	if str.Status != model.SorteioAberto {
+		return nil, ErrVendasEncerradas
+	}
+
+	if reserva.ClienteID.IsZero() {
+		return nil, fmt.Errorf("%w: cliente obrigatório", ErrReservaInvalida)
+	}
+	if len(reserva.Numeros) == 0 || len(reserva.Numeros) > LimiteReserva {
+		return nil, fmt.Errorf("%w: escolha de 1 a %d números", ErrReservaInvalida, LimiteReserva)
+	}
+
+	vistos := make(map[int]bool, len(reserva.Numeros))
+	for _, n := range reserva.Numeros {
+		if err := rifa.ValidarAposta([]int{n}); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrReservaInvalida, err.Error())
+		}
+		if vistos[n] {
+			return nil, fmt.Errorf("%w: número %d repetido", ErrReservaInvalida, n)
+		}
+		vistos[n] = true
+	}
+
+	// As reservas ativas do cliente contam no limite como se já fossem
+	// apostas, senão ele seguraria números sem fim reservando de novo.
+	resp, err := rs.rdb.Eval(ctx, scriptReservados,
+		[]string{chaveReservasCliente(str.ID, reserva.ClienteID)},
+		prefixoReserva(str.ID))
+	if err != nil {
+		logger.Error("erro ao contar reservas do cliente na rifa", err)
+		return nil, err
+	}
+	reservados, _ := resp.(int64)
+
+	bilhetes := int64(len(reserva.Numeros)) + reservados
+	erros, err := rs.regras.AvaliarLimites(ctx, str, reserva.ClienteID, bilhetes, bilhetes*str.ValorAposta)
+	if err != nil {
+		return nil, err
+	}
+	if err := regras.Erro(erros); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReservaInvalida, err)
+	}
+
+	prazo := time.Duration(rs.conf.RifaReservaMinutos) * time.Minute
+	if prazo <= 0 {
+		prazo = 10 * time.Minute
+	}
+
+	result := &model.ReservaRifa{
+		ID:        primitive.NewObjectID().Hex(),
+		SorteioID: str.ID,
+		ClienteID: reserva.ClienteID,
+		Numeros:   reserva.Numeros,
+		ExpiraEm:  time.Now().Add(prazo),
+	}
+
+	keys := []string{chaveReservasCliente(str.ID, result.ClienteID), chaveVendidos(str.ID), chaveReserva(str.ID, result.ID)}
+	args := []interface{}{prefixoReserva(str.ID), result.ID, prazo.Milliseconds(), result.ClienteID.Hex(), LimiteReserva}
+	for _, n := range result.Numeros {
+		keys = append(keys, prefixoHold(str.ID)+strconv.Itoa(n))
+		args = append(args, n)
+	}
+
+	resp, err = rs.rdb.Eval(ctx, scriptReservar, keys, args...)
+	if err != nil {
+		logger.Error("erro ao reservar números da rifa", err)
+		return nil, err
+	}
+
+	valores, _ := resp.([]interface{})
+	if len(valores) == 0 || valores[0] != int64(1) {
+		if len(valores) == 3 && valores[2] == "limite" {
+			return nil, fmt.Errorf("%w: cliente já tem %v números reservados, limite de %d", ErrReservaInvalida, valores[1], LimiteReserva)
+		}
+		if len(valores) == 3 {
+			return nil, fmt.Errorf("%w: número %v já %v", ErrNumeroIndisponivel, valores[1], valores[2])
+		}
+		return nil, ErrNumeroIndisponivel
+	}
+
+	return result, nil
+}
+
+// Confirmar compra os números da reserva do cliente: eles passam a vendidos
+// no Redis e, numa única transação, cada um vira uma aposta aceita debitada
+// da carteira, com o sorteio ainda aberto. Se a transação falha, por saldo
+// insuficiente ou vendas encerradas, os números voltam a ficar livres.
+func (rs *RifaDataService) Confirmar(ctx context.Context, sorteioID string, reservaID string, clienteID primitive.ObjectID) ([]*model.Aposta, error) {
+	str, _, err := rs.carregar(ctx, sorteioID)
+	if err != nil {
+		return nil, err
+	}
+
+	if str.Status != model.SorteioAberto {
+		return nil, ErrVendasEncerradas
+	}
+
+	resp, err := rs.rdb.Eval(ctx, scriptConfirmar,
+		[]string{chaveVendidos(str.ID), chaveReserva(str.ID, reservaID)},
+		reservaID, prefixoHold(str.ID), clienteID.Hex())
+	if err != nil {
+		logger.Error("erro ao confirmar reserva da rifa", err)
+		return nil, err
+	}
+
+	valores, _ := resp.([]interface{})
+	if len(valores) < 2 || valores[0] != int64(1) {
+		return nil, ErrReservaNaoEncontrada
+	}
+
+	apostas := make([]*model.Aposta, 0, len(valores)-2)
+	documentos := make([]interface{}, 0, len(valores)-2)
+	numeros := make([]interface{}, 0, len(valores)-2)
+	for _, v := range valores[2:] {
+		n, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("%w: número %v", ErrReservaInvalida, v)
+		}
+
+		aposta := model.NewAposta(model.Aposta{
+			SorteioID: str.ID,
+			ClienteID: clienteID,
+			Numeros:   []int{n},
+			Valor:     str.ValorAposta,
+		})
+		aposta.Status = model.ApostaAceita
+		aposta.Codigo = comprovante.Codigo(rs.conf.ComprovanteSegredo, aposta.ID)
+
+		apostas = append(apostas, aposta)
+		documentos = append(documentos, aposta)
+		numeros = append(numeros, n)
+	}
+
+	if err := rs.vender(ctx, str, apostas, documentos); err != nil {
+		// Sem as apostas gravadas e pagas os números não podem ficar como vendidos.
+		if _, errEstorno := rs.rdb.Eval(ctx, scriptEstornar, []string{chaveVendidos(str.ID)}, numeros...); errEstorno != nil {
+			logger.Error("erro ao estornar números da rifa", errEstorno)
+		}
+		return nil, err
+	}
+
+	return apostas, nil
+}
+
+// vender grava as apostas da reserva e debita cada uma da carteira numa
+// transação que só soma os totais do sorteio se ele ainda estiver aberto,
+// como no aceite das apostas.
+func (rs *RifaDataService) vender(ctx context.Context, str *model.Sorteio, apostas []*model.Aposta, documentos []interface{}) error {
+	collection := rs.mdb.GetCollection("cfStore")
+
+	_, err := rs.mdb.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		result, err := collection.UpdateOne(sessCtx,
+			bson.D{
+				{Key: "_id", Value: str.ID},
+				{Key: "data_type", Value: "sorteio"},
+				{Key: "status", Value: model.SorteioAberto},
+			},
+			bson.D{{Key: "$inc", Value: bson.D{
+				{Key: "qtd_apostas", Value: int64(len(apostas))},
+				{Key: "total_arrecadado", Value: int64(len(apostas)) * str.ValorAposta},
+			}}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, ErrVendasEncerradas
+		}
+
+		if _, err := collection.InsertMany(sessCtx, documentos); err != nil {
+			return nil, err
+		}
+
+		for _, aposta := range apostas {
+			if _, err := rs.carteira.DebitarAposta(sessCtx, aposta); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil && !errors.Is(err, ErrVendasEncerradas) && !errors.Is(err, carteira.ErrSaldoInsuficiente) {
+		logger.Error("erro ao gravar apostas da rifa", err)
+	}
+	return err
+}
+
+func (rs *RifaDataService) Liberar(ctx context.Context, sorteioID string, reservaID string, clienteID primitive.ObjectID) error {
+	objectID, err := primitive.ObjectIDFromHex(sorteioID)
+	if err != nil {
+		return sorteio.ErrSorteioNaoEncontrado
+	}
+
+	resp, err := rs.rdb.Eval(ctx, scriptLiberar,
+		[]string{chaveReserva(objectID, reservaID)},
+		reservaID, prefixoHold(objectID), clienteID.Hex())
+	if err != nil {
+		logger.Error("erro ao liberar reserva da rifa", err)
+		return err
+	}
+
+	if resp == int64(0) {
+		return ErrReservaNaoEncontrada
+	}
+	return nil
+}
+
+func (rs *RifaDataService) Disponiveis(ctx context.Context, sorteioID string) (*model.DisponibilidadeRifa, error) {
+	str, rifa, err := rs.carregar(ctx, sorteioID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rs.rdb.Eval(ctx, scriptDisponiveis,
+		[]string{chaveVendidos(str.ID)},
+		prefixoHold(str.ID), rifa.Numeros)
+	if err != nil {
+		logger.Error("erro ao consultar números da rifa", err)
+		return nil, err
+	}
+
+	valores, _ := resp.([]interface{})
+	if len(valores) != 3 {
+		return nil, fmt.Errorf("resposta inesperada do redis: %v", resp)
+	}
+
+	vendidos, _ := valores[0].(int64)
+	reservados, _ := valores[1].(int64)
+	livres, _ := valores[2].([]interface{})
+
+	result := &model.DisponibilidadeRifa{
+		SorteioID:   str.ID,
+		Total:       rifa.Numeros,
+		Vendidos:    int(vendidos),
+		Reservados:  int(reservados),
+		Disponiveis: make([]int, 0, len(livres)),
+	}
+	for _, v := range livres {
+		if n, ok := v.(int64); ok {
+			result.Disponiveis = append(result.Disponiveis, int(n))
+		}
+	}
+	result.Esgotado = result.Vendidos == result.Total
+
+	return result, nil
+}
+
+// carregar busca o sorteio e garante que o jogo dele é uma rifa.
+func (rs *RifaDataService) carregar(ctx context.Context, sorteioID string) (*model.Sorteio, *jogo.Rifa, error) {
+	str, err := rs.sorteios.GetByID(ctx, sorteioID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	game, err := jogo.Obter(str.Jogo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rifa, ok := game.(*jogo.Rifa)
+	if !ok {
+		return nil, nil, ErrNaoERifa
+	}
+
+	return str, rifa, nil
+}
+
+func chaveVendidos(sorteioID primitive.ObjectID) string {
+	return fmt.Sprintf("rifa:{%s}:vendidos", sorteioID.Hex())
+}
+
+func chaveReserva(sorteioID primitive.ObjectID, reservaID string) string {
+	return prefixoReserva(sorteioID) + reservaID
+}
+
+func prefixoReserva(sorteioID primitive.ObjectID) string {
+	return fmt.Sprintf("rifa:{%s}:reserva:", sorteioID.Hex())
+}
+
+func chaveReservasCliente(sorteioID, clienteID primitive.ObjectID) string {
+	return fmt.Sprintf("rifa:{%s}:cliente:%s", sorteioID.Hex(), clienteID.Hex())
+}
+
+func prefixoHold(sorteioID primitive.ObjectID) string {
+	return fmt.Sprintf("rifa:{%s}:hold:", sorteioID.Hex())
+}
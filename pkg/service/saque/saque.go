@@ -0,0 +1,381 @@
+package saque
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/psp"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/meiopag"
+	"github.com/katana/fortuna/backend-go/pkg/service/validation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrSaqueInvalido       = errors.New("dados do saque inválidos")
+	ErrSaqueNaoEncontrado  = errors.New("saque não encontrado")
+	ErrSaqueJaAvaliado     = errors.New("saque já avaliado")
+	ErrChaveInvalida       = errors.New("chave Pix inválida")
+	ErrChaveDeOutroTitular = errors.New("chave Pix não pertence ao cliente")
+)
+
+type SaqueServiceInterface interface {
+	Solicitar(ctx context.Context, pedido model.PedidoSaque) (*model.Saque, error)
+	Aprovar(ctx context.Context, ID string, decisao model.DecisaoSaque) (*model.Saque, error)
+	Rejeitar(ctx context.Context, ID string, decisao model.DecisaoSaque) (*model.Saque, error)
+	GetByID(ctx context.Context, ID string) (*model.Saque, error)
+	GetAll(ctx context.Context, filters model.FilterSaque, limit, page int64) (*model.Paginate, error)
+}
+
+// SaqueDataService paga os saques por Pix. O valor fica bloqueado na carteira
+// desde o pedido; até o limite de aprovação o pagamento sai na hora, acima
+// dele o saque espera na fila de aprovação.
+type SaqueDataService struct {
+	mdb      mongodb.MongoDBInterface
+	psp      psp.PSPInterface
+	carteira carteira.CarteiraServiceInterface
+	meios    meiopag.MeioPagServiceInterface
+	conf     *config.Config
+}
+
+func NewSaqueService(mongo_connection mongodb.MongoDBInterface, provedor psp.PSPInterface, carteira_cliente carteira.CarteiraServiceInterface, meios meiopag.MeioPagServiceInterface, conf *config.Config) *SaqueDataService {
+	return &SaqueDataService{
+		mdb:      mongo_connection,
+		psp:      provedor,
+		carteira: carteira_cliente,
+		meios:    meios,
+		conf:     conf,
+	}
+}
+
+// Solicitar confere a chave e o titular no DICT, bloqueia o valor e grava o
+// saque. Saques até conf.SaqueLimiteAprovacao são pagos em seguida.
+func (sds *SaqueDataService) Solicitar(ctx context.Context, pedido model.PedidoSaque) (*model.Saque, error) {
+	if pedido.ClienteID.IsZero() {
+		return nil, fmt.Errorf("%w: cliente obrigatório", ErrSaqueInvalido)
+	}
+	if pedido.Valor <= 0 {
+		return nil, fmt.Errorf("%w: valor deve ser positivo", ErrSaqueInvalido)
+	}
+
+	cli := &model.Cliente{}
+	err := sds.mdb.GetCollection("cfStore").FindOne(ctx, bson.D{
+		{Key: "data_type", Value: "cliente"},
+		{Key: "_id", Value: pedido.ClienteID},
+		{Key: "enabled", Value: true},
+	}).Decode(cli)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w: cliente não encontrado ou desativado", ErrSaqueInvalido)
+		}
+		logger.Error("erro ao consultar Cliente do saque", err)
+		return nil, err
+	}
+
+	documento := validation.ExtractNumbers(cli.Documento)
+	if documento == "" {
+		return nil, fmt.Errorf("%w: cliente sem CPF/CNPJ cadastrado", ErrSaqueInvalido)
+	}
+
+	chave, err := normalizarChave(pedido.TipoChave, pedido.Chave)
+	if err != nil {
+		return nil, err
+	}
+	if (pedido.TipoChave == model.ChaveCPF || pedido.TipoChave == model.ChaveCNPJ) && chave != documento {
+		return nil, ErrChaveDeOutroTitular
+	}
+
+	meio, err := sds.meios.Autorizar(ctx, model.MeioPagPix, model.OperacaoSaque, pedido.Valor)
+	if err != nil {
+		return nil, err
+	}
+
+	dono, err := sds.psp.ConsultarChave(ctx, chave)
+	if err != nil {
+		if errors.Is(err, psp.ErrChaveNaoEncontrada) {
+			return nil, fmt.Errorf("%w: %v", ErrChaveInvalida, err)
+		}
+		logger.Error("erro ao consultar chave Pix no DICT", err)
+		return nil, err
+	}
+	if validation.ExtractNumbers(dono.Documento) != documento {
+		return nil, ErrChaveDeOutroTitular
+	}
+
+	automatico := pedido.Valor <= sds.conf.SaqueLimiteAprovacao
+	status := model.SaqueAguardandoAprovacao
+	if automatico {
+		status = model.SaqueProcessando
+	}
+
+	novo := model.NewSaque(model.Saque{
+		ClienteID:     pedido.ClienteID,
+		Valor:         pedido.Valor,
+		Taxa:          meio.Tarifa(pedido.Valor),
+		TipoChave:     pedido.TipoChave,
+		Chave:         chave,
+		NomeRecebedor: dono.Nome,
+		Status:        status,
+		Automatico:    automatico,
+	})
+
+	if _, err := sds.carteira.BloquearSaque(ctx, novo); err != nil {
+		return nil, err
+	}
+
+	if _, err := sds.mdb.GetCollection("cfStore").InsertOne(ctx, novo); err != nil {
+		logger.Error("erro salvar saque", err)
+		if _, errLib := sds.carteira.LiberarSaque(ctx, novo); errLib != nil {
+			logger.Error("erro ao liberar bloqueio do saque não gravado "+novo.ID.Hex(), errLib)
+		}
+		return nil, err
+	}
+
+	if automatico {
+		return sds.pagar(ctx, novo)
+	}
+
+	return novo, nil
+}
+
+// Aprovar paga um saque da fila. Um saque parado em processando, por falha
+// depois do envio, pode ser aprovado de novo: o PSP e a carteira não repetem
+// o mesmo saque.
+func (sds *SaqueDataService) Aprovar(ctx context.Context, ID string, decisao model.DecisaoSaque) (*model.Saque, error) {
+	saq, err := sds.avaliar(ctx, ID, []string{model.SaqueAguardandoAprovacao, model.SaqueProcessando}, bson.D{
+		{Key: "status", Value: model.SaqueProcessando},
+		{Key: "avaliado_por", Value: decisao.Usuario},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sds.pagar(ctx, saq)
+}
+
+// Rejeitar tira o saque da fila e devolve o valor bloqueado ao cliente.
+// Repetir a rejeição só refaz a devolução, que não credita duas vezes.
+func (sds *SaqueDataService) Rejeitar(ctx context.Context, ID string, decisao model.DecisaoSaque) (*model.Saque, error) {
+	if strings.TrimSpace(decisao.Motivo) == "" {
+		return nil, fmt.Errorf("%w: motivo da rejeição obrigatório", ErrSaqueInvalido)
+	}
+
+	saq, err := sds.avaliar(ctx, ID, []string{model.SaqueAguardandoAprovacao, model.SaqueRejeitado}, bson.D{
+		{Key: "status", Value: model.SaqueRejeitado},
+		{Key: "avaliado_por", Value: decisao.Usuario},
+		{Key: "motivo", Value: decisao.Motivo},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := sds.carteira.LiberarSaque(ctx, saq); err != nil {
+		return nil, err
+	}
+
+	return saq, nil
+}
+
+func (sds *SaqueDataService) GetByID(ctx context.Context, ID string) (*model.Saque, error) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return nil, ErrSaqueNaoEncontrado
+	}
+
+	saq := &model.Saque{}
+	err = sds.mdb.GetCollection("cfStore").FindOne(ctx, bson.D{
+		{Key: "data_type", Value: "saque"},
+		{Key: "_id", Value: objectID},
+	}).Decode(saq)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrSaqueNaoEncontrado
+		}
+		logger.Error("erro ao consultar saque", err)
+		return nil, err
+	}
+
+	return saq, nil
+}
+
+// GetAll lista os saques do mais antigo para o mais novo, a ordem da fila.
+func (sds *SaqueDataService) GetAll(ctx context.Context, filters model.FilterSaque, limit, page int64) (*model.Paginate, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	query := bson.M{"data_type": "saque"}
+	if !filters.ClienteID.IsZero() {
+		query["cliente_id"] = filters.ClienteID
+	}
+	if filters.Status != "" {
+		query["status"] = filters.Status
+	}
+
+	count, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		logger.Error("erro ao consultar todos os saques", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+
+	opts := pagination.GetPaginatedOpts().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	curr, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]*model.Saque, 0)
+	for curr.Next(ctx) {
+		saq := &model.Saque{}
+		if err := curr.Decode(saq); err != nil {
+			logger.Error("erro ao consultar todos os saques", err)
+			continue
+		}
+		result = append(result, saq)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+// avaliar muda o saque de estado só se ele estiver num dos estados de
+// origem, o que impede dois administradores de decidirem o mesmo saque.
+func (sds *SaqueDataService) avaliar(ctx context.Context, ID string, origem []string, campos bson.D) (*model.Saque, error) {
+	atual, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: atual.ID},
+		{Key: "data_type", Value: "saque"},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: origem}}},
+	}
+	campos = append(campos, bson.E{Key: "updated_at", Value: time.Now().Format(time.RFC3339)})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	saq := &model.Saque{}
+	err = sds.mdb.GetCollection("cfStore").FindOneAndUpdate(ctx, filter, bson.D{{Key: "$set", Value: campos}}, opts).Decode(saq)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w: status %s", ErrSaqueJaAvaliado, atual.Status)
+		}
+		logger.Error("erro ao avaliar saque", err)
+		return nil, err
+	}
+
+	return saq, nil
+}
+
+// pagar envia o Pix do saque em processando. Se o PSP recusa de vez, o saque
+// falha e o valor volta ao cliente; se paga, a carteira baixa o bloqueio. Num
+// erro incerto, como timeout, o Pix pode ter saído: o saque fica em
+// processando, com o valor bloqueado, até ser aprovado de novo ou conciliado.
+func (sds *SaqueDataService) pagar(ctx context.Context, saq *model.Saque) (*model.Saque, error) {
+	enviado, err := sds.psp.EnviarPix(ctx, psp.Transferencia{
+		ID:    saq.ID.Hex(),
+		Chave: saq.Chave,
+		Valor: saq.Valor - saq.Taxa,
+	})
+	if err != nil {
+		logger.Error("erro ao enviar Pix do saque "+saq.ID.Hex(), err)
+		if !errors.Is(err, psp.ErrPixRecusado) {
+			return saq, nil
+		}
+		if _, errLib := sds.carteira.LiberarSaque(ctx, saq); errLib != nil {
+			return nil, errLib
+		}
+		return sds.concluir(ctx, saq, bson.D{
+			{Key: "status", Value: model.SaqueFalhou},
+			{Key: "motivo", Value: err.Error()},
+		})
+	}
+
+	saq.EndToEndID = enviado.EndToEndID
+	if _, err := sds.carteira.Sacar(ctx, saq); err != nil {
+		return nil, err
+	}
+
+	return sds.concluir(ctx, saq, bson.D{
+		{Key: "status", Value: model.SaquePago},
+		{Key: "end_to_end_id", Value: enviado.EndToEndID},
+		{Key: "pago_em", Value: enviado.Horario},
+	})
+}
+
+func (sds *SaqueDataService) concluir(ctx context.Context, saq *model.Saque, campos bson.D) (*model.Saque, error) {
+	filter := bson.D{
+		{Key: "_id", Value: saq.ID},
+		{Key: "data_type", Value: "saque"},
+		{Key: "status", Value: model.SaqueProcessando},
+	}
+	campos = append(campos, bson.E{Key: "updated_at", Value: time.Now().Format(time.RFC3339)})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	result := &model.Saque{}
+	err := sds.mdb.GetCollection("cfStore").FindOneAndUpdate(ctx, filter, bson.D{{Key: "$set", Value: campos}}, opts).Decode(result)
+	if err != nil {
+		// Outra aprovação do mesmo saque concluiu primeiro.
+		if err == mongo.ErrNoDocuments {
+			return sds.GetByID(ctx, saq.ID.Hex())
+		}
+		logger.Error("erro ao concluir saque", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// normalizarChave valida a chave pelo tipo e a devolve no formato do DICT:
+// documentos só com dígitos, e-mail e EVP em minúsculas, telefone com +55.
+func normalizarChave(tipo, chave string) (string, error) {
+	chave = strings.TrimSpace(chave)
+
+	switch tipo {
+	case model.ChaveCPF:
+		chave = validation.ExtractNumbers(chave)
+		if !validation.IsCPFValid(chave) {
+			return "", fmt.Errorf("%w: CPF inválido", ErrChaveInvalida)
+		}
+	case model.ChaveCNPJ:
+		chave = validation.ExtractNumbers(chave)
+		if !validation.IsCNPJValid(chave) {
+			return "", fmt.Errorf("%w: CNPJ inválido", ErrChaveInvalida)
+		}
+	case model.ChaveEmail:
+		chave = strings.ToLower(chave)
+		if !validation.IsEmailValid(chave) {
+			return "", fmt.Errorf("%w: e-mail inválido", ErrChaveInvalida)
+		}
+	case model.ChaveTelefone:
+		numeros := validation.ExtractNumbers(chave)
+		if !strings.HasPrefix(chave, "+") {
+			numeros = "55" + numeros
+		}
+		chave = "+" + numeros
+		if !validation.IsTelefoneValid(chave) {
+			return "", fmt.Errorf("%w: telefone deve ter DDD e número, ex.: +5511999998888", ErrChaveInvalida)
+		}
+	case model.ChaveEVP:
+		chave = strings.ToLower(chave)
+		if !validation.IsEVPValid(chave) {
+			return "", fmt.Errorf("%w: chave aleatória inválida", ErrChaveInvalida)
+		}
+	default:
+		return "", fmt.Errorf("%w: tipo deve ser cpf, cnpj, email, telefone ou evp", ErrChaveInvalida)
+	}
+
+	return chave, nil
+}
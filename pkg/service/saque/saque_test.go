@@ -0,0 +1,305 @@
+package saque
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/psp"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/carteira"
+	"github.com/katana/fortuna/backend-go/pkg/service/meiopag"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// mongoTeste entrega a coleção do mtest em modo mock.
+type mongoTeste struct {
+	mt *mtest.T
+}
+
+func (m mongoTeste) GetCollection(string) *mongo.Collection       { return m.mt.Coll }
+func (m mongoTeste) GetCollectionByName(string) *mongo.Collection { return m.mt.Coll }
+
+func (m mongoTeste) WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) (interface{}, error)) (interface{}, error) {
+	sess, err := m.mt.Client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.EndSession(ctx)
+	return fn(mongo.NewSessionContext(ctx, sess))
+}
+
+// carteiraTeste anota os movimentos do saque na ordem em que acontecem.
+type carteiraTeste struct {
+	carteira.CarteiraServiceInterface
+	chamadas    []string
+	errBloqueio error
+}
+
+func (ct *carteiraTeste) BloquearSaque(ctx context.Context, saque *model.Saque) (*model.Lancamento, error) {
+	ct.chamadas = append(ct.chamadas, "bloquear")
+	if ct.errBloqueio != nil {
+		return nil, ct.errBloqueio
+	}
+	return &model.Lancamento{ID: primitive.NewObjectID()}, nil
+}
+
+func (ct *carteiraTeste) LiberarSaque(ctx context.Context, saque *model.Saque) (*model.Lancamento, error) {
+	ct.chamadas = append(ct.chamadas, "liberar")
+	return &model.Lancamento{ID: primitive.NewObjectID()}, nil
+}
+
+func (ct *carteiraTeste) Sacar(ctx context.Context, saque *model.Saque) (*model.Lancamento, error) {
+	ct.chamadas = append(ct.chamadas, "sacar")
+	return &model.Lancamento{ID: primitive.NewObjectID()}, nil
+}
+
+// meiosTeste autoriza todo saque Pix com tarifa de 1%.
+type meiosTeste struct {
+	meiopag.MeioPagServiceInterface
+}
+
+func (meiosTeste) Autorizar(ctx context.Context, nome, operacao string, valor int64) (*model.MeioPagamento, error) {
+	return &model.MeioPagamento{MeioPg: nome, Taxa: 100}, nil
+}
+
+// pspTeste responde o DICT com dono e o envio com errEnvio.
+type pspTeste struct {
+	psp.PSPInterface
+	dono     *psp.DonoChave
+	errEnvio error
+	enviados []psp.Transferencia
+}
+
+func (pt *pspTeste) ConsultarChave(ctx context.Context, chave string) (*psp.DonoChave, error) {
+	if pt.dono == nil {
+		return nil, psp.ErrChaveNaoEncontrada
+	}
+	return pt.dono, nil
+}
+
+func (pt *pspTeste) EnviarPix(ctx context.Context, transferencia psp.Transferencia) (*psp.PixEnviado, error) {
+	pt.enviados = append(pt.enviados, transferencia)
+	if pt.errEnvio != nil {
+		return nil, pt.errEnvio
+	}
+	return &psp.PixEnviado{EndToEndID: "E2E1", Horario: time.Now()}, nil
+}
+
+const cpfTeste = "52998224725"
+
+// documento converte o modelo no documento que o mock devolve.
+func documento(t *testing.T, v interface{}) bson.D {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("bson.Unmarshal: %v", err)
+	}
+	return doc
+}
+
+// statusGravado devolve o status do $set de cada findAndModify enviado.
+func statusGravado(mt *mtest.T) []string {
+	var result []string
+	for ev := mt.GetStartedEvent(); ev != nil; ev = mt.GetStartedEvent() {
+		if ev.CommandName != "findAndModify" {
+			continue
+		}
+		result = append(result, ev.Command.Lookup("update", "$set", "status").StringValue())
+	}
+	return result
+}
+
+func TestSolicitar(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	clienteID := primitive.NewObjectID()
+	titular := &psp.DonoChave{Documento: cpfTeste, Nome: "Ana"}
+	outro := &psp.DonoChave{Documento: "11144477735", Nome: "Bia"}
+	inseriu := mtest.CreateSuccessResponse()
+	concluiu := mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "data_type", Value: "saque"}}})
+	falhaInsert := mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "falha ao gravar"})
+
+	tests := []struct {
+		name         string
+		pedido       model.PedidoSaque
+		dono         *psp.DonoChave
+		errBloqueio  error
+		errEnvio     error
+		respostas    []bson.D
+		wantErr      error
+		wantFalha    bool
+		wantChamadas []string
+		wantEnvio    int64
+		wantStatus   string
+		wantGravado  []string
+	}{
+		{
+			name:         "automático pago",
+			pedido:       model.PedidoSaque{Valor: 10000, TipoChave: model.ChaveEmail, Chave: " Ana@Exemplo.com "},
+			dono:         titular,
+			respostas:    []bson.D{inseriu, concluiu},
+			wantChamadas: []string{"bloquear", "sacar"},
+			wantEnvio:    9900,
+			wantGravado:  []string{model.SaquePago},
+		},
+		{
+			name:         "automático recusado pelo PSP devolve o valor",
+			pedido:       model.PedidoSaque{Valor: 10000, TipoChave: model.ChaveEmail, Chave: "ana@exemplo.com"},
+			dono:         titular,
+			errEnvio:     psp.ErrPixRecusado,
+			respostas:    []bson.D{inseriu, concluiu},
+			wantChamadas: []string{"bloquear", "liberar"},
+			wantEnvio:    9900,
+			wantGravado:  []string{model.SaqueFalhou},
+		},
+		{
+			name:         "erro incerto no envio mantém o bloqueio",
+			pedido:       model.PedidoSaque{Valor: 10000, TipoChave: model.ChaveEmail, Chave: "ana@exemplo.com"},
+			dono:         titular,
+			errEnvio:     errors.New("timeout"),
+			respostas:    []bson.D{inseriu},
+			wantChamadas: []string{"bloquear"},
+			wantEnvio:    9900,
+			wantStatus:   model.SaqueProcessando,
+		},
+		{
+			name:         "acima do limite fica na fila bloqueado",
+			pedido:       model.PedidoSaque{Valor: 500001, TipoChave: model.ChaveCPF, Chave: "529.982.247-25"},
+			dono:         titular,
+			respostas:    []bson.D{inseriu},
+			wantChamadas: []string{"bloquear"},
+			wantStatus:   model.SaqueAguardandoAprovacao,
+		},
+		{
+			name:         "saldo insuficiente não grava o saque",
+			pedido:       model.PedidoSaque{Valor: 10000, TipoChave: model.ChaveEmail, Chave: "ana@exemplo.com"},
+			dono:         titular,
+			errBloqueio:  carteira.ErrSaldoInsuficiente,
+			wantErr:      carteira.ErrSaldoInsuficiente,
+			wantChamadas: []string{"bloquear"},
+		},
+		{
+			name:         "falha ao gravar libera o bloqueio",
+			pedido:       model.PedidoSaque{Valor: 10000, TipoChave: model.ChaveEmail, Chave: "ana@exemplo.com"},
+			dono:         titular,
+			respostas:    []bson.D{falhaInsert},
+			wantFalha:    true,
+			wantChamadas: []string{"bloquear", "liberar"},
+		},
+		{
+			name:    "CPF de outro titular",
+			pedido:  model.PedidoSaque{Valor: 10000, TipoChave: model.ChaveCPF, Chave: "111.444.777-35"},
+			dono:    titular,
+			wantErr: ErrChaveDeOutroTitular,
+		},
+		{
+			name:    "chave de outro titular no DICT",
+			pedido:  model.PedidoSaque{Valor: 10000, TipoChave: model.ChaveEmail, Chave: "bia@exemplo.com"},
+			dono:    outro,
+			wantErr: ErrChaveDeOutroTitular,
+		},
+		{
+			name:    "chave fora do DICT",
+			pedido:  model.PedidoSaque{Valor: 10000, TipoChave: model.ChaveEmail, Chave: "ninguem@exemplo.com"},
+			wantErr: ErrChaveInvalida,
+		},
+		{
+			name:    "valor zero",
+			pedido:  model.PedidoSaque{TipoChave: model.ChaveEmail, Chave: "ana@exemplo.com"},
+			dono:    titular,
+			wantErr: ErrSaqueInvalido,
+		},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			cli := model.Cliente{ID: clienteID, Documento: cpfTeste, Enabled: true}
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "fortuna.cfStore", mtest.FirstBatch, documento(t, cli)))
+			mt.AddMockResponses(tt.respostas...)
+
+			ct := &carteiraTeste{errBloqueio: tt.errBloqueio}
+			pt := &pspTeste{dono: tt.dono, errEnvio: tt.errEnvio}
+			sds := NewSaqueService(mongoTeste{mt: mt}, pt, ct, meiosTeste{}, &config.Config{SaqueLimiteAprovacao: 500000})
+
+			tt.pedido.ClienteID = clienteID
+			saq, err := sds.Solicitar(context.Background(), tt.pedido)
+			switch {
+			case tt.wantFalha:
+				if err == nil {
+					mt.Fatalf("Solicitar sem erro, want a falha do insert")
+				}
+			case !errors.Is(err, tt.wantErr):
+				mt.Fatalf("erro = %v, want %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(ct.chamadas, tt.wantChamadas) {
+				mt.Errorf("carteira = %v, want %v", ct.chamadas, tt.wantChamadas)
+			}
+			if tt.wantEnvio == 0 && len(pt.enviados) > 0 {
+				mt.Errorf("Pix enviado %+v, want nenhum envio", pt.enviados)
+			}
+			if tt.wantEnvio > 0 && (len(pt.enviados) != 1 || pt.enviados[0].Valor != tt.wantEnvio || pt.enviados[0].Chave != "ana@exemplo.com") {
+				mt.Errorf("Pix enviado %+v, want um envio de %d para ana@exemplo.com", pt.enviados, tt.wantEnvio)
+			}
+			if tt.wantStatus != "" && saq.Status != tt.wantStatus {
+				mt.Errorf("Status = %q, want %q", saq.Status, tt.wantStatus)
+			}
+			if gravado := statusGravado(mt); !slices.Equal(gravado, tt.wantGravado) {
+				mt.Errorf("status gravado = %v, want %v", gravado, tt.wantGravado)
+			}
+		})
+	}
+}
+
+func TestRejeitar(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("devolve o valor bloqueado", func(mt *mtest.T) {
+		saq := model.Saque{ID: primitive.NewObjectID(), Valor: 600000, Status: model.SaqueAguardandoAprovacao}
+		rejeitado := saq
+		rejeitado.Status = model.SaqueRejeitado
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "fortuna.cfStore", mtest.FirstBatch, documento(t, saq)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: documento(t, rejeitado)}),
+		)
+
+		ct := &carteiraTeste{}
+		sds := NewSaqueService(mongoTeste{mt: mt}, &pspTeste{}, ct, meiosTeste{}, &config.Config{})
+
+		got, err := sds.Rejeitar(context.Background(), saq.ID.Hex(), model.DecisaoSaque{Usuario: "admin", Motivo: "chave suspeita"})
+		if err != nil {
+			mt.Fatalf("Rejeitar: %v", err)
+		}
+		if got.Status != model.SaqueRejeitado {
+			mt.Errorf("Status = %q, want %q", got.Status, model.SaqueRejeitado)
+		}
+		if want := []string{"liberar"}; !slices.Equal(ct.chamadas, want) {
+			mt.Errorf("carteira = %v, want %v", ct.chamadas, want)
+		}
+		if gravado := statusGravado(mt); !slices.Equal(gravado, []string{model.SaqueRejeitado}) {
+			mt.Errorf("status gravado = %v, want %v", gravado, []string{model.SaqueRejeitado})
+		}
+	})
+
+	mt.Run("sem motivo", func(mt *mtest.T) {
+		ct := &carteiraTeste{}
+		sds := NewSaqueService(mongoTeste{mt: mt}, &pspTeste{}, ct, meiosTeste{}, &config.Config{})
+
+		_, err := sds.Rejeitar(context.Background(), primitive.NewObjectID().Hex(), model.DecisaoSaque{Usuario: "admin"})
+		if !errors.Is(err, ErrSaqueInvalido) {
+			mt.Errorf("erro = %v, want %v", err, ErrSaqueInvalido)
+		}
+		if len(ct.chamadas) > 0 {
+			mt.Errorf("carteira = %v, want nenhum movimento", ct.chamadas)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+// Package desdobramento expande uma aposta com mais números que a aposta
+// simples nas linhas que ela cobre. O desdobramento completo gera todas as
+// combinações; o reduzido gera um conjunto menor de linhas com garantia
+// "g se g": se g números sorteados estiverem entre os escolhidos, ao menos
+// uma linha acerta esses g. As linhas saem sempre na mesma ordem para os
+// mesmos números, então podem ser recalculadas a partir da aposta.
+package desdobramento
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// LimiteLinhas é o máximo de linhas de uma aposta desdobrada.
+	LimiteLinhas = 10000
+	// limiteCandidatos limita as combinações examinadas no desdobramento reduzido.
+	limiteCandidatos = 20000
+)
+
+var ErrDesdobramentoInvalido = errors.New("desdobramento inválido")
+
+// Combinacoes devolve C(n, k), ou -1 se passar de int64.
+func Combinacoes(n, k int) int64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	var c int64 = 1
+	for i := 1; i <= k; i++ {
+		hi, lo := bits.Mul64(uint64(c), uint64(n-k+i))
+		if hi != 0 || lo > 1<<62 {
+			return -1
+		}
+		c = int64(lo) / int64(i)
+	}
+	return c
+}
+
+// Gerar devolve as linhas de tamanho k cobertas pelos números. Garantia 0 ou
+// igual a k pede o desdobramento completo.
+func Gerar(numeros []int, k, garantia int) ([][]int, error) {
+	n := len(numeros)
+	if n <= k {
+		return nil, fmt.Errorf("%w: escolha mais de %d números", ErrDesdobramentoInvalido, k)
+	}
+	if n > 62 {
+		return nil, fmt.Errorf("%w: números demais", ErrDesdobramentoInvalido)
+	}
+	if garantia < 0 || garantia > k {
+		return nil, fmt.Errorf("%w: garantia deve estar entre 1 e %d", ErrDesdobramentoInvalido, k)
+	}
+
+	ordenados := append([]int(nil), numeros...)
+	sort.Ints(ordenados)
+
+	total := Combinacoes(n, k)
+	if garantia == 0 || garantia == k {
+		if total < 0 || total > LimiteLinhas {
+			return nil, fmt.Errorf("%w: %d números geram mais de %d linhas", ErrDesdobramentoInvalido, n, LimiteLinhas)
+		}
+		return linhas(ordenados, combinar(n, k)), nil
+	}
+
+	if total < 0 || total > limiteCandidatos {
+		return nil, fmt.Errorf("%w: números demais para o desdobramento reduzido", ErrDesdobramentoInvalido)
+	}
+	return linhas(ordenados, reduzir(n, k, garantia)), nil
+}
+
+// combinar enumera os subconjuntos de k posições em [0, n) em ordem lexicográfica.
+func combinar(n, k int) []uint64 {
+	result := make([]uint64, 0)
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+	for {
+		var m uint64
+		for _, i := range idx {
+			m |= 1 << uint(i)
+		}
+		result = append(result, m)
+
+		i := k - 1
+		for i >= 0 && idx[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return result
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+// reduzir escolhe linhas de forma gulosa: a cada passo entra a combinação que
+// cobre mais subconjuntos de tamanho g ainda descobertos (no empate, a primeira
+// em ordem lexicográfica), até todos estarem cobertos.
+func reduzir(n, k, g int) []uint64 {
+	candidatos := combinar(n, k)
+	alvos := combinar(n, g)
+
+	indice := make(map[uint64]int, len(alvos))
+	for i, a := range alvos {
+		indice[a] = i
+	}
+
+	cobre := make([][]int, len(candidatos))
+	for c, m := range candidatos {
+		for _, sub := range subconjuntos(m, g) {
+			cobre[c] = append(cobre[c], indice[sub])
+		}
+	}
+
+	coberto := make([]bool, len(alvos))
+	faltam := len(alvos)
+	result := make([]uint64, 0)
+
+	for faltam > 0 {
+		melhor, ganho := -1, 0
+		for c := range candidatos {
+			novos := 0
+			for _, a := range cobre[c] {
+				if !coberto[a] {
+					novos++
+				}
+			}
+			if novos > ganho {
+				melhor, ganho = c, novos
+			}
+		}
+
+		result = append(result, candidatos[melhor])
+		for _, a := range cobre[melhor] {
+			if !coberto[a] {
+				coberto[a] = true
+				faltam--
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return menor(result[i], result[j]) })
+	return result
+}
+
+// subconjuntos devolve os subconjuntos de g bits da máscara m.
+func subconjuntos(m uint64, g int) []uint64 {
+	posicoes := make([]int, 0, bits.OnesCount64(m))
+	for i := 0; i < 64; i++ {
+		if m&(1<<uint(i)) != 0 {
+			posicoes = append(posicoes, i)
+		}
+	}
+
+	result := make([]uint64, 0)
+	for _, c := range combinar(len(posicoes), g) {
+		var sub uint64
+		for i, p := range posicoes {
+			if c&(1<<uint(i)) != 0 {
+				sub |= 1 << uint(p)
+			}
+		}
+		result = append(result, sub)
+	}
+	return result
+}
+
+// menor compara máscaras pela ordem lexicográfica das posições ligadas.
+func menor(a, b uint64) bool {
+	for a != 0 && b != 0 {
+		pa, pb := bits.TrailingZeros64(a), bits.TrailingZeros64(b)
+		if pa != pb {
+			return pa < pb
+		}
+		a &= a - 1
+		b &= b - 1
+	}
+	return a == 0 && b != 0
+}
+
+func linhas(numeros []int, mascaras []uint64) [][]int {
+	result := make([][]int, len(mascaras))
+	for i, m := range mascaras {
+		linha := make([]int, 0, bits.OnesCount64(m))
+		for p := range numeros {
+			if m&(1<<uint(p)) != 0 {
+				linha = append(linha, numeros[p])
+			}
+		}
+		result[i] = linha
+	}
+	return result
+}
@@ -0,0 +1,182 @@
+package desdobramento
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// subconjuntosDe enumera os subconjuntos de tamanho g dos números, sem usar
+// as máscaras do pacote.
+func subconjuntosDe(numeros []int, g int) [][]int {
+	if g == 0 {
+		return [][]int{{}}
+	}
+	if len(numeros) < g {
+		return nil
+	}
+	result := make([][]int, 0)
+	for _, resto := range subconjuntosDe(numeros[1:], g-1) {
+		result = append(result, append([]int{numeros[0]}, resto...))
+	}
+	return append(result, subconjuntosDe(numeros[1:], g)...)
+}
+
+func contem(linha, sub []int) bool {
+	presentes := make(map[int]bool, len(linha))
+	for _, n := range linha {
+		presentes[n] = true
+	}
+	for _, n := range sub {
+		if !presentes[n] {
+			return false
+		}
+	}
+	return true
+}
+
+func sequencia(de, ate int) []int {
+	result := make([]int, 0, ate-de+1)
+	for n := de; n <= ate; n++ {
+		result = append(result, n)
+	}
+	return result
+}
+
+func TestCombinacoes(t *testing.T) {
+	tests := []struct {
+		n, k int
+		want int64
+	}{
+		{5, 2, 10},
+		{0, 0, 1},
+		{7, 7, 1},
+		{60, 6, 50_063_860},
+		{25, 15, 3_268_760},
+		{3, 4, 0},
+		{3, -1, 0},
+		{100, 50, -1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("C(%d,%d)", tt.n, tt.k), func(t *testing.T) {
+			if got := Combinacoes(tt.n, tt.k); got != tt.want {
+				t.Errorf("Combinacoes(%d, %d) = %d, want %d", tt.n, tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGerarGarantia confere a promessa do desdobramento reduzido: qualquer g
+// dos números escolhidos aparecem juntos em ao menos uma linha.
+func TestGerarGarantia(t *testing.T) {
+	tests := []struct {
+		name     string
+		numeros  []int
+		k        int
+		garantia int
+	}{
+		{"7 números, quina garantida", []int{3, 11, 19, 27, 35, 43, 51}, 6, 5},
+		{"8 números, quina garantida", sequencia(1, 8), 6, 5},
+		{"9 números, quadra garantida", sequencia(10, 18), 6, 4},
+		{"10 números, terno garantido", []int{60, 1, 45, 7, 33, 12, 28, 9, 51, 2}, 6, 3},
+		{"12 números, quadra garantida", sequencia(1, 12), 6, 4},
+		{"10 números na quina, quadra garantida", sequencia(30, 39), 5, 4},
+		{"18 números na lotofácil, 14 garantidos", sequencia(1, 18), 15, 14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Gerar(tt.numeros, tt.k, tt.garantia)
+			if err != nil {
+				t.Fatalf("Gerar: %v", err)
+			}
+
+			if completo := Combinacoes(len(tt.numeros), tt.k); int64(len(got)) >= completo {
+				t.Errorf("reduzido gerou %d linhas, o completo tem %d", len(got), completo)
+			}
+
+			escolhidos := make(map[int]bool, len(tt.numeros))
+			for _, n := range tt.numeros {
+				escolhidos[n] = true
+			}
+			for _, linha := range got {
+				if len(linha) != tt.k || !sort.IntsAreSorted(linha) {
+					t.Fatalf("linha %v não tem %d números em ordem", linha, tt.k)
+				}
+				for i, n := range linha {
+					if !escolhidos[n] || (i > 0 && linha[i-1] == n) {
+						t.Fatalf("linha %v tem número repetido ou não escolhido", linha)
+					}
+				}
+			}
+
+			ordenados := append([]int(nil), tt.numeros...)
+			sort.Ints(ordenados)
+			for _, sub := range subconjuntosDe(ordenados, tt.garantia) {
+				coberto := false
+				for _, linha := range got {
+					if contem(linha, sub) {
+						coberto = true
+						break
+					}
+				}
+				if !coberto {
+					t.Fatalf("%v não está em nenhuma linha", sub)
+				}
+			}
+
+			again, _ := Gerar(tt.numeros, tt.k, tt.garantia)
+			if !reflect.DeepEqual(got, again) {
+				t.Error("mesmos números geraram linhas diferentes")
+			}
+		})
+	}
+}
+
+func TestGerarCompleto(t *testing.T) {
+	tests := []struct {
+		name     string
+		numeros  []int
+		k        int
+		garantia int
+		want     [][]int
+	}{
+		{"garantia zero", []int{4, 2, 9}, 2, 0, [][]int{{2, 4}, {2, 9}, {4, 9}}},
+		{"garantia igual a k", []int{1, 2, 3, 4}, 3, 3, [][]int{{1, 2, 3}, {1, 2, 4}, {1, 3, 4}, {2, 3, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Gerar(tt.numeros, tt.k, tt.garantia)
+			if err != nil {
+				t.Fatalf("Gerar: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Gerar = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGerarInvalido(t *testing.T) {
+	tests := []struct {
+		name     string
+		numeros  []int
+		k        int
+		garantia int
+	}{
+		{"números de menos", sequencia(1, 6), 6, 0},
+		{"números demais para a máscara", sequencia(1, 63), 6, 0},
+		{"garantia negativa", sequencia(1, 8), 6, -1},
+		{"garantia maior que k", sequencia(1, 8), 6, 7},
+		{"completo passa do limite de linhas", sequencia(1, 20), 6, 0},
+		{"reduzido com candidatos demais", sequencia(1, 20), 6, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Gerar(tt.numeros, tt.k, tt.garantia); !errors.Is(err, ErrDesdobramentoInvalido) {
+				t.Errorf("Gerar = %v, want %v", err, ErrDesdobramentoInvalido)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Package fair implementa o esquema commit-reveal usado nos sorteios.
+//
+// Ao abrir as vendas o servidor gera uma seed secreta e publica apenas o
+// SHA-256 dela (o commit). No momento do sorteio a seed é revelada e o
+// resultado é derivado de forma determinística a partir da seed e de uma
+// entropia pública, escolhida depois do fechamento das vendas (por exemplo
+// o hash de um bloco ou o resultado de uma loteria oficial).
+//
+// Algoritmo, para quem quiser reimplementar a verificação:
+//
+//  1. commit = hex(SHA-256(bytes(seed_hex)))
+//  2. o fluxo de bytes é a concatenação dos blocos
+//     HMAC-SHA256(chave = bytes(seed_hex), msg = entropia + ":" + contador),
+//     com contador decimal começando em 0;
+//  3. cada inteiro é lido como uint64 big-endian de 8 bytes do fluxo;
+//     Intn(n) descarta valores >= 2^64 - (2^64 mod n) e devolve v mod n;
+//  4. as dezenas saem de um Fisher-Yates parcial sobre [min..max]: para
+//     i = 0..qtd-1, j = i + Intn(total-i) e troca as posições i e j. As qtd
+//     primeiras posições, ordenadas, são o resultado.
+package fair
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+var (
+	ErrCommitInvalido = errors.New("seed revelada não corresponde ao commit publicado")
+	ErrParametros     = errors.New("parâmetros de sorteio inválidos")
+)
+
+// GerarSeed devolve 32 bytes aleatórios codificados em hexadecimal.
+func GerarSeed() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Commit devolve o hash publicado antes da revelação da seed.
+func Commit(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Stream é o gerador determinístico derivado de seed e entropia.
+type Stream struct {
+	seed     []byte
+	entropia string
+	contador uint64
+	buf      []byte
+}
+
+func NovoStream(seed, entropia string) *Stream {
+	return &Stream{
+		seed:     []byte(seed),
+		entropia: entropia,
+	}
+}
+
+func (s *Stream) proximoBloco() {
+	mac := hmac.New(sha256.New, s.seed)
+	mac.Write([]byte(s.entropia + ":" + strconv.FormatUint(s.contador, 10)))
+	s.buf = append(s.buf, mac.Sum(nil)...)
+	s.contador++
+}
+
+// Uint64 lê os próximos 8 bytes do fluxo.
+func (s *Stream) Uint64() uint64 {
+	for len(s.buf) < 8 {
+		s.proximoBloco()
+	}
+	v := binary.BigEndian.Uint64(s.buf[:8])
+	s.buf = s.buf[8:]
+	return v
+}
+
+// Intn devolve um inteiro uniforme em [0, n) sem viés de módulo.
+func (s *Stream) Intn(n int) int {
+	if n <= 0 {
+		panic("fair: Intn com n <= 0")
+	}
+	limite := math.MaxUint64 - (math.MaxUint64%uint64(n)+1)%uint64(n)
+	for {
+		v := s.Uint64()
+		if v <= limite {
+			return int(v % uint64(n))
+		}
+	}
+}
+
+// Dezenas sorteia qtd números distintos em [min, max], em ordem crescente.
+func Dezenas(s *Stream, qtd, min, max int) ([]int, error) {
+	total := max - min + 1
+	if qtd <= 0 || total < qtd {
+		return nil, ErrParametros
+	}
+
+	universo := make([]int, total)
+	for i := range universo {
+		universo[i] = min + i
+	}
+
+	for i := 0; i < qtd; i++ {
+		j := i + s.Intn(total-i)
+		universo[i], universo[j] = universo[j], universo[i]
+	}
+
+	resultado := append([]int(nil), universo[:qtd]...)
+	sort.Ints(resultado)
+	return resultado, nil
+}
+
+// ConfereCommit valida a seed revelada contra o commit publicado.
+func ConfereCommit(seed, seedHash string) error {
+	esperado, err := hex.DecodeString(seedHash)
+	if err != nil {
+		return ErrCommitInvalido
+	}
+	sum := sha256.Sum256([]byte(seed))
+	if !hmac.Equal(sum[:], esperado) {
+		return ErrCommitInvalido
+	}
+	return nil
+}
+
+// Verificar recalcula o resultado de um sorteio a partir dos dados revelados.
+func Verificar(seed, seedHash, entropia string, qtd, min, max int) ([]int, error) {
+	if err := ConfereCommit(seed, seedHash); err != nil {
+		return nil, err
+	}
+	return Dezenas(NovoStream(seed, entropia), qtd, min, max)
+}
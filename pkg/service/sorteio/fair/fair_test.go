@@ -0,0 +1,232 @@
+package fair
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// seedTeste e commitTeste, o SHA-256 dela publicado na abertura.
+const (
+	seedTeste   = "test"
+	commitTeste = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+)
+
+// fluxoEsperado recalcula o fluxo pela descrição do pacote, sem usar Stream.
+func fluxoEsperado(seed, entropia string, blocos int) []byte {
+	fluxo := make([]byte, 0, blocos*sha256.Size)
+	for c := 0; c < blocos; c++ {
+		mac := hmac.New(sha256.New, []byte(seed))
+		mac.Write([]byte(entropia + ":" + strconv.Itoa(c)))
+		fluxo = append(fluxo, mac.Sum(nil)...)
+	}
+	return fluxo
+}
+
+func TestCommit(t *testing.T) {
+	tests := []struct {
+		name string
+		seed string
+		want string
+	}{
+		{"vazia", "", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"test", seedTeste, commitTeste},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Commit(tt.seed); got != tt.want {
+				t.Errorf("Commit(%q) = %s, want %s", tt.seed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamUint64(t *testing.T) {
+	tests := []struct {
+		name     string
+		seed     string
+		entropia string
+		leituras int
+	}{
+		{"um bloco", seedTeste, "bloco-123", 4},
+		{"atravessa blocos", seedTeste, "bloco-123", 13},
+		{"entropia vazia", seedTeste, "", 5},
+		{"outra seed", commitTeste, "loteria:2024-01-01", 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fluxo := fluxoEsperado(tt.seed, tt.entropia, (tt.leituras*8+sha256.Size-1)/sha256.Size)
+			s := NovoStream(tt.seed, tt.entropia)
+			for i := 0; i < tt.leituras; i++ {
+				want := binary.BigEndian.Uint64(fluxo[i*8:])
+				if got := s.Uint64(); got != want {
+					t.Fatalf("leitura %d = %d, want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamDeterministico(t *testing.T) {
+	tests := []struct {
+		name      string
+		entropiaA string
+		entropiaB string
+		iguais    bool
+	}{
+		{"mesma entropia", "bloco-1", "bloco-1", true},
+		{"entropias diferentes", "bloco-1", "bloco-2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := NovoStream(seedTeste, tt.entropiaA), NovoStream(seedTeste, tt.entropiaB)
+			iguais := true
+			for i := 0; i < 16; i++ {
+				if a.Uint64() != b.Uint64() {
+					iguais = false
+				}
+			}
+			if iguais != tt.iguais {
+				t.Errorf("fluxos iguais = %v, want %v", iguais, tt.iguais)
+			}
+		})
+	}
+}
+
+func TestIntn(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		sorteios int
+	}{
+		{"n = 1", 1, 50},
+		{"n = 2", 2, 1000},
+		{"dezenas da mega", 60, 6000},
+		{"n grande", 1 << 40, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NovoStream(seedTeste, tt.name)
+			vistos := make(map[int]bool)
+			for i := 0; i < tt.sorteios; i++ {
+				v := s.Intn(tt.n)
+				if v < 0 || v >= tt.n {
+					t.Fatalf("Intn(%d) = %d, fora de [0, %d)", tt.n, v, tt.n)
+				}
+				vistos[v] = true
+			}
+			// Com tantas amostras todos os valores de um n pequeno aparecem.
+			if tt.n <= 60 && len(vistos) != tt.n {
+				t.Errorf("Intn(%d) produziu %d valores distintos, want %d", tt.n, len(vistos), tt.n)
+			}
+		})
+	}
+}
+
+func TestIntnModulo(t *testing.T) {
+	// Sem rejeição no primeiro valor, Intn é o Uint64 do fluxo módulo n.
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{"potência de dois", 64},
+		{"primo", 61},
+		{"dezenas da lotofácil", 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := int(NovoStream(seedTeste, "modulo").Uint64() % uint64(tt.n))
+			if got := NovoStream(seedTeste, "modulo").Intn(tt.n); got != want {
+				t.Errorf("Intn(%d) = %d, want %d", tt.n, got, want)
+			}
+		})
+	}
+}
+
+func TestIntnPanicaSemIntervalo(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Intn(%d) não entrou em pânico", n)
+				}
+			}()
+			NovoStream(seedTeste, "").Intn(n)
+		})
+	}
+}
+
+func TestDezenas(t *testing.T) {
+	tests := []struct {
+		name    string
+		qtd     int
+		min     int
+		max     int
+		wantErr error
+	}{
+		{"mega-sena", 6, 1, 60, nil},
+		{"lotofácil", 15, 1, 25, nil},
+		{"universo inteiro", 10, 0, 9, nil},
+		{"qtd zero", 0, 1, 60, ErrParametros},
+		{"qtd maior que o universo", 11, 0, 9, ErrParametros},
+		{"intervalo invertido", 1, 10, 1, ErrParametros},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Dezenas(NovoStream(seedTeste, "dezenas"), tt.qtd, tt.min, tt.max)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != tt.qtd {
+				t.Fatalf("len = %d, want %d", len(got), tt.qtd)
+			}
+			for i, d := range got {
+				if d < tt.min || d > tt.max {
+					t.Errorf("dezena %d fora de [%d, %d]", d, tt.min, tt.max)
+				}
+				if i > 0 && got[i-1] >= d {
+					t.Errorf("dezenas fora de ordem ou repetidas: %v", got)
+				}
+			}
+
+			again, _ := Dezenas(NovoStream(seedTeste, "dezenas"), tt.qtd, tt.min, tt.max)
+			if !reflect.DeepEqual(got, again) {
+				t.Errorf("mesma seed e entropia deram %v e %v", got, again)
+			}
+		})
+	}
+}
+
+func TestVerificar(t *testing.T) {
+	esperado, _ := Dezenas(NovoStream(seedTeste, "bloco-9"), 6, 1, 60)
+
+	tests := []struct {
+		name     string
+		seed     string
+		seedHash string
+		want     []int
+		wantErr  error
+	}{
+		{"seed revelada confere", seedTeste, commitTeste, esperado, nil},
+		{"seed trocada", "outra", commitTeste, nil, ErrCommitInvalido},
+		{"commit não hexadecimal", seedTeste, "zz", nil, ErrCommitInvalido},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Verificar(tt.seed, tt.seedHash, "bloco-9", 6, 1, 60)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Verificar = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
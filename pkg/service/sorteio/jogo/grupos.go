@@ -0,0 +1,68 @@
+package jogo
+
+import "fmt"
+
+// Grupos é o jogo de números agrupados: as dezenas 00-99 são divididas em
+// QtdGrupos grupos consecutivos do mesmo tamanho; com 25 grupos cada um tem
+// 4 dezenas (grupo 1 = 01..04, ..., grupo 25 = 97..00).
+// O sorteio extrai Premios milhares (0000-9999), na ordem do 1º ao último
+// prêmio, e a aposta acerta cada prêmio cuja dezena final cai no grupo escolhido.
+type Grupos struct {
+	Codigo    string
+	Titulo    string
+	QtdGrupos int
+	Premios   int
+}
+
+func (g *Grupos) Nome() string      { return g.Codigo }
+func (g *Grupos) Descricao() string { return g.Titulo }
+
+func (g *Grupos) Formato() Formato {
+	return Formato{Min: 1, Max: g.QtdGrupos, Tamanhos: []int{1}}
+}
+
+func (g *Grupos) ValidarAposta(numeros []int) error {
+	if len(numeros) != 1 {
+		return fmt.Errorf("%w: escolha exatamente um grupo", ErrApostaInvalida)
+	}
+	if numeros[0] < 1 || numeros[0] > g.QtdGrupos {
+		return fmt.Errorf("%w: grupo %d fora do intervalo 1-%d", ErrApostaInvalida, numeros[0], g.QtdGrupos)
+	}
+	return nil
+}
+
+// GerarResultado sorteia milhares independentes; repetições são permitidas.
+func (g *Grupos) GerarResultado(r Rand) ([]int, error) {
+	resultado := make([]int, g.Premios)
+	for i := range resultado {
+		resultado[i] = r.Intn(10000)
+	}
+	return resultado, nil
+}
+
+func (g *Grupos) GerarAposta(r Rand) []int {
+	return []int{1 + r.Intn(g.QtdGrupos)}
+}
+
+func (g *Grupos) ContarAcertos(aposta, resultado []int) int {
+	if len(aposta) != 1 {
+		return 0
+	}
+
+	acertos := 0
+	for _, milhar := range resultado {
+		if g.GrupoDaMilhar(milhar) == aposta[0] {
+			acertos++
+		}
+	}
+	return acertos
+}
+
+// GrupoDaMilhar devolve o grupo (1-QtdGrupos) da dezena final da milhar.
+func (g *Grupos) GrupoDaMilhar(milhar int) int {
+	dezena := milhar % 100
+	if dezena == 0 {
+		return g.QtdGrupos
+	}
+	return (dezena-1)/(100/g.QtdGrupos) + 1
+}
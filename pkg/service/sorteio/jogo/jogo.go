@@ -0,0 +1,97 @@
+// Package jogo define os tipos de jogo aceitos pelos sorteios. Cada tipo
+// decide como uma aposta é validada, como o resultado é gerado e como os
+// acertos são contados. Novos tipos entram por Registrar, sem mudanças nos
+// handlers.
+package jogo
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/fair"
+)
+
+var (
+	ErrJogoNaoEncontrado = errors.New("tipo de jogo não encontrado")
+	ErrApostaInvalida    = errors.New("aposta inválida para o tipo de jogo")
+)
+
+// Rand é a fonte de aleatoriedade usada para gerar resultados. O sorteio real
+// usa o fluxo do commit-reveal (fair.Stream); simulações podem usar math/rand.
+type Rand interface {
+	Intn(n int) int
+}
+
+// Formato descreve as apostas aceitas por um jogo: os números ficam em
+// [Min, Max], sem repetição, e a quantidade escolhida é uma das Tamanhos.
+// Jogos Desdobraveis aceitam mais números que Tamanhos[0], cobrindo várias
+// linhas numa aposta só.
+type Formato struct {
+	Min         int   `json:"min"`
+	Max         int   `json:"max"`
+	Tamanhos    []int `json:"tamanhos"`
+	Desdobravel bool  `json:"desdobravel"`
+}
+
+type GameType interface {
+	Nome() string
+	Descricao() string
+	Formato() Formato
+	ValidarAposta(numeros []int) error
+	// GerarAposta devolve uma aposta válida escolhida ao acaso.
+	GerarAposta(r Rand) []int
+	GerarResultado(r Rand) ([]int, error)
+	ContarAcertos(aposta, resultado []int) int
+}
+
+var (
+	registroLock sync.RWMutex
+	registro     = map[string]GameType{}
+)
+
+// Registrar adiciona um tipo de jogo ao registro; um nome repetido substitui o anterior.
+func Registrar(g GameType) {
+	registroLock.Lock()
+	defer registroLock.Unlock()
+
+	registro[g.Nome()] = g
+}
+
+func Obter(nome string) (GameType, error) {
+	registroLock.RLock()
+	defer registroLock.RUnlock()
+
+	g, ok := registro[nome]
+	if !ok {
+		return nil, ErrJogoNaoEncontrado
+	}
+	return g, nil
+}
+
+// Listar devolve os tipos registrados ordenados pelo nome.
+func Listar() []GameType {
+	registroLock.RLock()
+	defer registroLock.RUnlock()
+
+	jogos := make([]GameType, 0, len(registro))
+	for _, g := range registro {
+		jogos = append(jogos, g)
+	}
+	sort.Slice(jogos, func(i, j int) bool { return jogos[i].Nome() < jogos[j].Nome() })
+	return jogos
+}
+
+// Verificar confere o commit da seed e recalcula o resultado do jogo.
+func Verificar(g GameType, seed, seedHash, entropia string) ([]int, error) {
+	if err := fair.ConfereCommit(seed, seedHash); err != nil {
+		return nil, err
+	}
+	return g.GerarResultado(fair.NovoStream(seed, entropia))
+}
+
+func init() {
+	Registrar(&PickN{Codigo: "megasena", Titulo: "Escolha 6 dezenas de 01 a 60", Escolher: 6, Sortear: 6, Min: 1, Max: 60})
+	Registrar(&Rifa{Codigo: "rifa", Titulo: "Rifa numerada de 000 a 999", Numeros: 1000})
+	Registrar(&Grupos{Codigo: "bicho", Titulo: "25 grupos de 4 dezenas, 5 prêmios de milhar", QtdGrupos: 25, Premios: 5})
+}
@@ -0,0 +1,84 @@
+package jogo
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PickN é o jogo de dezenas no estilo Mega-Sena: o apostador escolhe
+// Escolher números em [Min, Max] e o sorteio extrai Sortear números distintos.
+type PickN struct {
+	Codigo   string
+	Titulo   string
+	Escolher int
+	Sortear  int
+	Min      int
+	Max      int
+}
+
+func (p *PickN) Nome() string      { return p.Codigo }
+func (p *PickN) Descricao() string { return p.Titulo }
+
+func (p *PickN) Formato() Formato {
+	return Formato{Min: p.Min, Max: p.Max, Tamanhos: []int{p.Escolher}, Desdobravel: true}
+}
+
+func (p *PickN) ValidarAposta(numeros []int) error {
+	if len(numeros) != p.Escolher {
+		return fmt.Errorf("%w: escolha exatamente %d dezenas", ErrApostaInvalida, p.Escolher)
+	}
+
+	vistos := make(map[int]bool, len(numeros))
+	for _, n := range numeros {
+		if n < p.Min || n > p.Max {
+			return fmt.Errorf("%w: dezena %d fora do intervalo %d-%d", ErrApostaInvalida, n, p.Min, p.Max)
+		}
+		if vistos[n] {
+			return fmt.Errorf("%w: dezena %d repetida", ErrApostaInvalida, n)
+		}
+		vistos[n] = true
+	}
+	return nil
+}
+
+func (p *PickN) GerarResultado(r Rand) ([]int, error) {
+	return p.escolher(r, p.Sortear), nil
+}
+
+func (p *PickN) GerarAposta(r Rand) []int {
+	return p.escolher(r, p.Escolher)
+}
+
+// escolher extrai qtd dezenas distintas em ordem crescente.
+func (p *PickN) escolher(r Rand, qtd int) []int {
+	total := p.Max - p.Min + 1
+	universo := make([]int, total)
+	for i := range universo {
+		universo[i] = p.Min + i
+	}
+
+	// Fisher-Yates parcial, o mesmo descrito no pacote fair.
+	for i := 0; i < qtd; i++ {
+		j := i + r.Intn(total-i)
+		universo[i], universo[j] = universo[j], universo[i]
+	}
+
+	dezenas := append([]int(nil), universo[:qtd]...)
+	sort.Ints(dezenas)
+	return dezenas
+}
+
+func (p *PickN) ContarAcertos(aposta, resultado []int) int {
+	sorteadas := make(map[int]bool, len(resultado))
+	for _, n := range resultado {
+		sorteadas[n] = true
+	}
+
+	acertos := 0
+	for _, n := range aposta {
+		if sorteadas[n] {
+			acertos++
+		}
+	}
+	return acertos
+}
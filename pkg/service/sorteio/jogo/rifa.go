@@ -0,0 +1,43 @@
+package jogo
+
+import "fmt"
+
+// Rifa é a rifa numerada: cada bilhete é um único número em [0, Numeros) e o
+// sorteio extrai um número vencedor.
+type Rifa struct {
+	Codigo  string
+	Titulo  string
+	Numeros int
+}
+
+func (f *Rifa) Nome() string      { return f.Codigo }
+func (f *Rifa) Descricao() string { return f.Titulo }
+
+func (f *Rifa) Formato() Formato {
+	return Formato{Min: 0, Max: f.Numeros - 1, Tamanhos: []int{1}}
+}
+
+func (f *Rifa) ValidarAposta(numeros []int) error {
+	if len(numeros) != 1 {
+		return fmt.Errorf("%w: um bilhete de rifa tem exatamente um número", ErrApostaInvalida)
+	}
+	if numeros[0] < 0 || numeros[0] >= f.Numeros {
+		return fmt.Errorf("%w: número %d fora do intervalo 0-%d", ErrApostaInvalida, numeros[0], f.Numeros-1)
+	}
+	return nil
+}
+
+func (f *Rifa) GerarResultado(r Rand) ([]int, error) {
+	return []int{r.Intn(f.Numeros)}, nil
+}
+
+func (f *Rifa) GerarAposta(r Rand) []int {
+	return []int{r.Intn(f.Numeros)}
+}
+
+func (f *Rifa) ContarAcertos(aposta, resultado []int) int {
+	if len(aposta) == 1 && len(resultado) == 1 && aposta[0] == resultado[0] {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package jogo
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// RandSeguro tira os números de crypto/rand. É a fonte das surpresinhas,
+// que não podem ser previsíveis para quem conhece o horário da aposta.
+type RandSeguro struct{}
+
+func (RandSeguro) Intn(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(fmt.Sprintf("crypto/rand indisponível: %v", err))
+	}
+	return int(v.Int64())
+}
+
+// Surpresinha completa os fixos com números aleatórios do formato até o
+// primeiro tamanho permitido, sem usar os excluídos. Devolve em ordem crescente.
+func Surpresinha(formato Formato, fixos, excluidos []int, r Rand) ([]int, error) {
+	if len(formato.Tamanhos) == 0 {
+		return nil, fmt.Errorf("%w: jogo sem tamanho de aposta", ErrApostaInvalida)
+	}
+	tamanho := formato.Tamanhos[0]
+
+	if len(fixos) > tamanho {
+		return nil, fmt.Errorf("%w: no máximo %d números fixos", ErrApostaInvalida, tamanho)
+	}
+
+	fora := make(map[int]bool, len(excluidos)+len(fixos))
+	for _, n := range excluidos {
+		if n < formato.Min || n > formato.Max {
+			return nil, fmt.Errorf("%w: excluído %d fora do intervalo %d-%d", ErrApostaInvalida, n, formato.Min, formato.Max)
+		}
+		fora[n] = true
+	}
+	for _, n := range fixos {
+		if n < formato.Min || n > formato.Max {
+			return nil, fmt.Errorf("%w: fixo %d fora do intervalo %d-%d", ErrApostaInvalida, n, formato.Min, formato.Max)
+		}
+		if fora[n] {
+			return nil, fmt.Errorf("%w: fixo %d repetido ou excluído", ErrApostaInvalida, n)
+		}
+		fora[n] = true
+	}
+
+	universo := make([]int, 0, formato.Max-formato.Min+1)
+	for n := formato.Min; n <= formato.Max; n++ {
+		if !fora[n] {
+			universo = append(universo, n)
+		}
+	}
+
+	faltam := tamanho - len(fixos)
+	if faltam > len(universo) {
+		return nil, fmt.Errorf("%w: sobram %d números para completar %d", ErrApostaInvalida, len(universo), faltam)
+	}
+
+	// Fisher-Yates parcial, como em PickN.escolher.
+	for i := 0; i < faltam; i++ {
+		j := i + r.Intn(len(universo)-i)
+		universo[i], universo[j] = universo[j], universo[i]
+	}
+
+	numeros := append(append([]int(nil), fixos...), universo[:faltam]...)
+	sort.Ints(numeros)
+	return numeros, nil
+}
@@ -0,0 +1,113 @@
+// Package merkle monta a árvore de Merkle usada para comprometer o conjunto
+// de apostas de um sorteio no fechamento das vendas.
+//
+// Folhas e nós usam prefixos distintos para que uma folha nunca possa ser
+// apresentada como nó interno: folha = SHA-256(0x00 || dados) e
+// nó = SHA-256(0x01 || esquerda || direita). Num nível com quantidade ímpar o
+// último hash sobe sem alteração. A raiz de um conjunto vazio é SHA-256("").
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+const (
+	Esquerda = "esquerda"
+	Direita  = "direita"
+)
+
+var ErrIndiceInvalido = errors.New("índice fora do conjunto de folhas")
+
+// Passo é um irmão no caminho da folha até a raiz e o lado em que ele fica.
+type Passo struct {
+	Hash string `json:"hash"`
+	Lado string `json:"lado"`
+}
+
+func Folha(dados []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(dados)
+	return h.Sum(nil)
+}
+
+func no(esquerda, direita []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(esquerda)
+	h.Write(direita)
+	return h.Sum(nil)
+}
+
+func proximoNivel(nivel [][]byte) [][]byte {
+	acima := make([][]byte, 0, (len(nivel)+1)/2)
+	for i := 0; i < len(nivel); i += 2 {
+		if i+1 == len(nivel) {
+			acima = append(acima, nivel[i])
+			continue
+		}
+		acima = append(acima, no(nivel[i], nivel[i+1]))
+	}
+	return acima
+}
+
+// Raiz devolve a raiz da árvore formada pelas folhas, na ordem informada.
+func Raiz(folhas [][]byte) []byte {
+	if len(folhas) == 0 {
+		vazio := sha256.Sum256(nil)
+		return vazio[:]
+	}
+
+	nivel := folhas
+	for len(nivel) > 1 {
+		nivel = proximoNivel(nivel)
+	}
+	return nivel[0]
+}
+
+// Prova devolve o caminho de inclusão da folha no índice informado.
+func Prova(folhas [][]byte, indice int) ([]Passo, error) {
+	if indice < 0 || indice >= len(folhas) {
+		return nil, ErrIndiceInvalido
+	}
+
+	caminho := make([]Passo, 0)
+	nivel := folhas
+	for len(nivel) > 1 {
+		irmao := indice ^ 1
+		if irmao < len(nivel) {
+			lado := Direita
+			if irmao < indice {
+				lado = Esquerda
+			}
+			caminho = append(caminho, Passo{Hash: hex.EncodeToString(nivel[irmao]), Lado: lado})
+		}
+		nivel = proximoNivel(nivel)
+		indice /= 2
+	}
+	return caminho, nil
+}
+
+// VerificarProva recalcula a raiz a partir da folha e do caminho e compara
+// com a raiz publicada.
+func VerificarProva(folha []byte, caminho []Passo, raiz []byte) bool {
+	atual := folha
+	for _, p := range caminho {
+		irmao, err := hex.DecodeString(p.Hash)
+		if err != nil {
+			return false
+		}
+		switch p.Lado {
+		case Esquerda:
+			atual = no(irmao, atual)
+		case Direita:
+			atual = no(atual, irmao)
+		default:
+			return false
+		}
+	}
+	return bytes.Equal(atual, raiz)
+}
@@ -0,0 +1,186 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func hashNo(esquerda, direita []byte) []byte {
+	sum := sha256.Sum256(append(append([]byte{0x01}, esquerda...), direita...))
+	return sum[:]
+}
+
+func folhasTeste(n int) [][]byte {
+	folhas := make([][]byte, n)
+	for i := range folhas {
+		folhas[i] = Folha([]byte("aposta-" + strconv.Itoa(i)))
+	}
+	return folhas
+}
+
+func TestFolha(t *testing.T) {
+	tests := []struct {
+		name  string
+		dados []byte
+	}{
+		{"vazia", nil},
+		{"aposta", []byte("aposta-0")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := sha256.Sum256(append([]byte{0x00}, tt.dados...))
+			if got := Folha(tt.dados); !bytes.Equal(got, want[:]) {
+				t.Errorf("Folha = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestRaiz(t *testing.T) {
+	f := folhasTeste(5)
+	vazio := sha256.Sum256(nil)
+
+	tests := []struct {
+		name   string
+		folhas [][]byte
+		want   []byte
+	}{
+		{"sem folhas", nil, vazio[:]},
+		{"uma folha", f[:1], f[0]},
+		{"duas folhas", f[:2], hashNo(f[0], f[1])},
+		{"ímpar sobe sem alteração", f[:3], hashNo(hashNo(f[0], f[1]), f[2])},
+		{"quatro folhas", f[:4], hashNo(hashNo(f[0], f[1]), hashNo(f[2], f[3]))},
+		{"cinco folhas", f[:5], hashNo(hashNo(hashNo(f[0], f[1]), hashNo(f[2], f[3])), f[4])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Raiz(tt.folhas); !bytes.Equal(got, tt.want) {
+				t.Errorf("Raiz = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRaizDependeDaOrdem(t *testing.T) {
+	f := folhasTeste(2)
+	if bytes.Equal(Raiz([][]byte{f[0], f[1]}), Raiz([][]byte{f[1], f[0]})) {
+		t.Error("trocar a ordem das folhas não mudou a raiz")
+	}
+}
+
+func TestProva(t *testing.T) {
+	tests := []struct {
+		name   string
+		folhas int
+	}{
+		{"uma folha", 1},
+		{"duas folhas", 2},
+		{"três folhas", 3},
+		{"potência de dois", 8},
+		{"ímpar em vários níveis", 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folhas := folhasTeste(tt.folhas)
+			raiz := Raiz(folhas)
+			for i := range folhas {
+				caminho, err := Prova(folhas, i)
+				if err != nil {
+					t.Fatalf("Prova(%d): %v", i, err)
+				}
+				if !VerificarProva(folhas[i], caminho, raiz) {
+					t.Errorf("prova da folha %d não confere com a raiz", i)
+				}
+			}
+		})
+	}
+}
+
+func TestProvaCaminho(t *testing.T) {
+	f := folhasTeste(3)
+
+	tests := []struct {
+		name   string
+		indice int
+		want   []Passo
+	}{
+		{"primeira folha", 0, []Passo{
+			{Hash: hex.EncodeToString(f[1]), Lado: Direita},
+			{Hash: hex.EncodeToString(f[2]), Lado: Direita},
+		}},
+		{"segunda folha", 1, []Passo{
+			{Hash: hex.EncodeToString(f[0]), Lado: Esquerda},
+			{Hash: hex.EncodeToString(f[2]), Lado: Direita},
+		}},
+		{"folha sem irmão", 2, []Passo{
+			{Hash: hex.EncodeToString(hashNo(f[0], f[1])), Lado: Esquerda},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Prova(f, tt.indice)
+			if err != nil {
+				t.Fatalf("Prova: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("caminho = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("passo %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProvaIndiceInvalido(t *testing.T) {
+	folhas := folhasTeste(4)
+	for _, indice := range []int{-1, 4, 10} {
+		t.Run(strconv.Itoa(indice), func(t *testing.T) {
+			if _, err := Prova(folhas, indice); !errors.Is(err, ErrIndiceInvalido) {
+				t.Errorf("Prova(%d) = %v, want %v", indice, err, ErrIndiceInvalido)
+			}
+		})
+	}
+}
+
+func TestVerificarProvaAdulterada(t *testing.T) {
+	folhas := folhasTeste(6)
+	raiz := Raiz(folhas)
+	caminho, _ := Prova(folhas, 2)
+
+	trocarLado := append([]Passo(nil), caminho...)
+	trocarLado[0].Lado = Esquerda
+
+	ladoInvalido := append([]Passo(nil), caminho...)
+	ladoInvalido[0].Lado = "meio"
+
+	hashInvalido := append([]Passo(nil), caminho...)
+	hashInvalido[0].Hash = "zz"
+
+	tests := []struct {
+		name    string
+		folha   []byte
+		caminho []Passo
+		raiz    []byte
+	}{
+		{"outra folha", folhas[3], caminho, raiz},
+		{"lado trocado", folhas[2], trocarLado, raiz},
+		{"lado desconhecido", folhas[2], ladoInvalido, raiz},
+		{"hash não hexadecimal", folhas[2], hashInvalido, raiz},
+		{"caminho incompleto", folhas[2], caminho[:len(caminho)-1], raiz},
+		{"raiz de outro conjunto", folhas[2], caminho, Raiz(folhas[:5])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerificarProva(tt.folha, tt.caminho, tt.raiz) {
+				t.Error("prova adulterada foi aceita")
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Package rateio calcula a divisão do prêmio de um sorteio entre as faixas
+// da tabela e os ganhadores de cada faixa. Todos os valores são centavos em
+// int64; toda divisão é truncada e o que sobra vai para o resíduo, de modo
+// que prêmios pagos + resíduo + acumulado para o próximo sorteio somam
+// exatamente o prêmio do sorteio mais o acumulado recebido.
+package rateio
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/katana/fortuna/backend-go/pkg/model"
+)
+
+var ErrTabelaInvalida = errors.New("tabela de prêmios inválida")
+
+// ValidarTabela confere tipos, valores e a soma dos percentuais da tabela.
+func ValidarTabela(tabela []model.FaixaPremio) error {
+	vistos := make(map[int]bool, len(tabela))
+	var soma int64
+
+	for _, f := range tabela {
+		if vistos[f.Acertos] {
+			return fmt.Errorf("%w: faixa de %d acertos repetida", ErrTabelaInvalida, f.Acertos)
+		}
+		vistos[f.Acertos] = true
+
+		switch f.Tipo {
+		case model.FaixaFixa:
+			if f.ValorFixo <= 0 {
+				return fmt.Errorf("%w: faixa de %d acertos sem valor fixo", ErrTabelaInvalida, f.Acertos)
+			}
+		case model.FaixaPercentual:
+			if f.Percentual <= 0 {
+				return fmt.Errorf("%w: faixa de %d acertos sem percentual", ErrTabelaInvalida, f.Acertos)
+			}
+			soma += f.Percentual
+		default:
+			return fmt.Errorf("%w: tipo de faixa %q desconhecido", ErrTabelaInvalida, f.Tipo)
+		}
+	}
+
+	if soma > model.BaseCalculo {
+		return fmt.Errorf("%w: percentuais somam mais de 100%%", ErrTabelaInvalida)
+	}
+	return nil
+}
+
+// ValorPremio devolve a parte da arrecadação destinada às faixas percentuais.
+func ValorPremio(arrecadado, percentual int64) int64 {
+	return arrecadado * percentual / model.BaseCalculo
+}
+
+// Resultado é a apuração completa do rateio de um sorteio.
+type Resultado struct {
+	Faixas []model.RateioFaixa
+	// Residuo é o que sobra das divisões truncadas e das faixas sem ganhador.
+	Residuo int64
+	// Acumular é o valor da faixa principal que passa para o próximo sorteio
+	// do mesmo jogo por não ter tido ganhadores.
+	Acumular int64
+}
+
+// Calcular distribui premio entre as faixas da tabela conforme o número de
+// ganhadores por quantidade de acertos. A faixa principal (a percentual de
+// mais acertos) recebe também o acumulado dos sorteios anteriores; se passar
+// do teto (quando teto > 0) o excedente vai para a faixa percentual logo
+// abaixo. Sem ganhadores, a faixa principal acumula e as demais faixas
+// percentuais vão para o resíduo. Faixas fixas não consomem o prêmio.
+func Calcular(tabela []model.FaixaPremio, premio, acumulado, teto int64, ganhadores map[int]int64) Resultado {
+	principal, abaixo := faixasPrincipais(tabela)
+
+	valores := make(map[int]int64, len(tabela))
+	var distribuido int64
+	for _, f := range tabela {
+		if f.Tipo == model.FaixaPercentual {
+			valores[f.Acertos] = premio * f.Percentual / model.BaseCalculo
+			distribuido += valores[f.Acertos]
+		}
+	}
+
+	result := Resultado{Residuo: premio - distribuido}
+
+	if principal >= 0 {
+		valores[principal] += acumulado
+		if teto > 0 && valores[principal] > teto {
+			excedente := valores[principal] - teto
+			valores[principal] = teto
+			if abaixo >= 0 {
+				valores[abaixo] += excedente
+			} else {
+				result.Residuo += excedente
+			}
+		}
+	} else {
+		// Sem faixa percentual não há onde aplicar o acumulado: ele segue adiante.
+		result.Acumular = acumulado
+	}
+
+	result.Faixas = make([]model.RateioFaixa, 0, len(tabela))
+	for _, f := range tabela {
+		rf := model.RateioFaixa{
+			Acertos:    f.Acertos,
+			Tipo:       f.Tipo,
+			Ganhadores: ganhadores[f.Acertos],
+		}
+
+		switch f.Tipo {
+		case model.FaixaFixa:
+			rf.PremioPorGanhador = f.ValorFixo
+			rf.ValorFaixa = f.ValorFixo * rf.Ganhadores
+		case model.FaixaPercentual:
+			rf.ValorFaixa = valores[f.Acertos]
+			switch {
+			case rf.Ganhadores > 0:
+				rf.PremioPorGanhador = rf.ValorFaixa / rf.Ganhadores
+				rf.Residuo = rf.ValorFaixa % rf.Ganhadores
+				result.Residuo += rf.Residuo
+			case f.Acertos == principal:
+				result.Acumular += rf.ValorFaixa
+			default:
+				rf.Residuo = rf.ValorFaixa
+				result.Residuo += rf.Residuo
+			}
+		}
+
+		result.Faixas = append(result.Faixas, rf)
+	}
+
+	return result
+}
+
+// ValorPrincipal devolve o valor da faixa principal já calculado no rateio.
+func ValorPrincipal(tabela []model.FaixaPremio, faixas []model.RateioFaixa) int64 {
+	principal, _ := faixasPrincipais(tabela)
+	for _, f := range faixas {
+		if f.Acertos == principal {
+			return f.ValorFaixa
+		}
+	}
+	return 0
+}
+
+// faixasPrincipais devolve os acertos da faixa percentual mais alta e da
+// faixa percentual imediatamente abaixo dela, ou -1 quando não existem.
+func faixasPrincipais(tabela []model.FaixaPremio) (int, int) {
+	principal, abaixo := -1, -1
+	for _, f := range tabela {
+		if f.Tipo != model.FaixaPercentual {
+			continue
+		}
+		switch {
+		case f.Acertos > principal:
+			abaixo = principal
+			principal = f.Acertos
+		case f.Acertos > abaixo:
+			abaixo = f.Acertos
+		}
+	}
+	return principal, abaixo
+}
+
+// PremioPorAcertos devolve o prêmio de um ganhador com a quantidade de acertos informada.
+func PremioPorAcertos(faixas []model.RateioFaixa, acertos int) int64 {
+	for _, f := range faixas {
+		if f.Acertos == acertos {
+			return f.PremioPorGanhador
+		}
+	}
+	return 0
+}
+
+// Proporcional divide total em partes proporcionais aos pesos pelo método dos
+// maiores restos: cada parte recebe o piso da sua fração e os centavos que
+// sobram vão, um a um, para as maiores frações descartadas (empate fica com
+// o primeiro). As partes somam exatamente total quando algum peso é positivo.
+func Proporcional(total int64, pesos []int64) []int64 {
+	partes := make([]int64, len(pesos))
+
+	var soma int64
+	for _, p := range pesos {
+		if p > 0 {
+			soma += p
+		}
+	}
+	if soma == 0 || total <= 0 {
+		return partes
+	}
+
+	restos := make([]int64, len(pesos))
+	distribuido := int64(0)
+	for i, p := range pesos {
+		if p <= 0 {
+			continue
+		}
+		// total * p pode passar de int64 com prêmios grandes; divide em duas etapas.
+		q, r := total/soma, total%soma
+		partes[i] = q*p + (r*p)/soma
+		restos[i] = (r * p) % soma
+		distribuido += partes[i]
+	}
+
+	ordem := make([]int, 0, len(pesos))
+	for i, p := range pesos {
+		if p > 0 {
+			ordem = append(ordem, i)
+		}
+	}
+	sort.SliceStable(ordem, func(a, b int) bool { return restos[ordem[a]] > restos[ordem[b]] })
+
+	for k := 0; distribuido < total; k++ {
+		partes[ordem[k%len(ordem)]]++
+		distribuido++
+	}
+
+	return partes
+}
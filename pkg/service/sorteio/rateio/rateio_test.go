@@ -0,0 +1,186 @@
+package rateio
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/katana/fortuna/backend-go/pkg/model"
+)
+
+func percentual(acertos int, pontos int64) model.FaixaPremio {
+	return model.FaixaPremio{Acertos: acertos, Tipo: model.FaixaPercentual, Percentual: pontos}
+}
+
+func fixa(acertos int, valor int64) model.FaixaPremio {
+	return model.FaixaPremio{Acertos: acertos, Tipo: model.FaixaFixa, ValorFixo: valor}
+}
+
+var tabelaMega = []model.FaixaPremio{percentual(6, 3500), percentual(5, 1900), percentual(4, 1900)}
+
+func TestProporcional(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int64
+		pesos []int64
+		want  []int64
+	}{
+		{"sem pesos", 100, []int64{}, []int64{}},
+		{"divisão exata", 90, []int64{1, 2, 3, 3}, []int64{10, 20, 30, 30}},
+		{"empate nos restos fica com o primeiro", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"maior resto leva o centavo", 10, []int64{1, 2, 3, 0}, []int64{2, 3, 5, 0}},
+		{"peso negativo não recebe", 7, []int64{-5, 1, 1}, []int64{0, 4, 3}},
+		{"pesos zerados", 100, []int64{0, 0}, []int64{0, 0}},
+		{"total zero", 0, []int64{1, 2}, []int64{0, 0}},
+		{"total negativo", -10, []int64{1, 2}, []int64{0, 0}},
+		{"total vezes peso passaria de int64", 9_000_000_000_000_000_000, []int64{1_000_000, 1_000_000, 1_000_000},
+			[]int64{3_000_000_000_000_000_000, 3_000_000_000_000_000_000, 3_000_000_000_000_000_000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Proporcional(tt.total, tt.pesos)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Proporcional(%d, %v) = %v, want %v", tt.total, tt.pesos, got, tt.want)
+			}
+
+			var soma int64
+			for _, p := range got {
+				soma += p
+			}
+			if tt.total > 0 && soma != 0 && soma != tt.total {
+				t.Errorf("partes somam %d, want %d", soma, tt.total)
+			}
+		})
+	}
+}
+
+func TestValidarTabela(t *testing.T) {
+	tests := []struct {
+		name    string
+		tabela  []model.FaixaPremio
+		wantErr error
+	}{
+		{"mega-sena", tabelaMega, nil},
+		{"com faixa fixa", []model.FaixaPremio{percentual(15, 6200), fixa(11, 600)}, nil},
+		{"percentuais somam 100%", []model.FaixaPremio{percentual(6, 5000), percentual(5, 5000)}, nil},
+		{"faixa repetida", []model.FaixaPremio{percentual(6, 3500), percentual(6, 1000)}, ErrTabelaInvalida},
+		{"fixa sem valor", []model.FaixaPremio{fixa(11, 0)}, ErrTabelaInvalida},
+		{"percentual sem pontos", []model.FaixaPremio{percentual(6, 0)}, ErrTabelaInvalida},
+		{"tipo desconhecido", []model.FaixaPremio{{Acertos: 6, Tipo: "bonus"}}, ErrTabelaInvalida},
+		{"percentuais passam de 100%", []model.FaixaPremio{percentual(6, 6000), percentual(5, 4001)}, ErrTabelaInvalida},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidarTabela(tt.tabela); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidarTabela = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalcular(t *testing.T) {
+	tests := []struct {
+		name       string
+		tabela     []model.FaixaPremio
+		premio     int64
+		acumulado  int64
+		teto       int64
+		ganhadores map[int]int64
+		// Prêmio por ganhador esperado em cada faixa.
+		premios      map[int]int64
+		wantResiduo  int64
+		wantAcumular int64
+	}{
+		{
+			name:   "todas as faixas com ganhadores",
+			tabela: tabelaMega, premio: 1_000_000,
+			ganhadores:  map[int]int64{6: 1, 5: 3, 4: 7},
+			premios:     map[int]int64{6: 350_000, 5: 63_333, 4: 27_142},
+			wantResiduo: 270_000 + 1 + 6,
+		},
+		{
+			name:   "principal sem ganhador acumula com o acumulado recebido",
+			tabela: tabelaMega, premio: 1_000_000, acumulado: 50_000,
+			ganhadores:   map[int]int64{5: 2},
+			premios:      map[int]int64{6: 0, 5: 95_000, 4: 0},
+			wantResiduo:  270_000 + 190_000,
+			wantAcumular: 400_000,
+		},
+		{
+			name:   "excedente do teto vai para a faixa abaixo",
+			tabela: tabelaMega, premio: 1_000_000, acumulado: 100_000, teto: 300_000,
+			ganhadores:  map[int]int64{6: 2, 5: 1},
+			premios:     map[int]int64{6: 150_000, 5: 340_000, 4: 0},
+			wantResiduo: 270_000 + 190_000,
+		},
+		{
+			name:   "excedente do teto sem faixa abaixo vai para o resíduo",
+			tabela: []model.FaixaPremio{percentual(6, 5000)}, premio: 100_000, acumulado: 80_000, teto: 100_000,
+			ganhadores:  map[int]int64{6: 1},
+			premios:     map[int]int64{6: 100_000},
+			wantResiduo: 50_000 + 30_000,
+		},
+		{
+			name:   "faixa fixa não consome o prêmio",
+			tabela: []model.FaixaPremio{percentual(15, 6200), percentual(14, 1300), fixa(11, 600)}, premio: 100_000,
+			ganhadores:   map[int]int64{11: 4},
+			premios:      map[int]int64{15: 0, 14: 0, 11: 600},
+			wantResiduo:  25_000 + 13_000,
+			wantAcumular: 62_000,
+		},
+		{
+			name:   "sem faixa percentual o acumulado segue adiante",
+			tabela: []model.FaixaPremio{fixa(3, 500)}, premio: 10_000, acumulado: 7_000,
+			ganhadores:   map[int]int64{3: 2},
+			premios:      map[int]int64{3: 500},
+			wantResiduo:  10_000,
+			wantAcumular: 7_000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calcular(tt.tabela, tt.premio, tt.acumulado, tt.teto, tt.ganhadores)
+
+			if got.Residuo != tt.wantResiduo {
+				t.Errorf("Residuo = %d, want %d", got.Residuo, tt.wantResiduo)
+			}
+			if got.Acumular != tt.wantAcumular {
+				t.Errorf("Acumular = %d, want %d", got.Acumular, tt.wantAcumular)
+			}
+
+			// Pago nas faixas percentuais + resíduo + acumular fecha com o
+			// prêmio mais o acumulado recebido.
+			pago := int64(0)
+			for _, f := range got.Faixas {
+				if want := tt.premios[f.Acertos]; f.PremioPorGanhador != want {
+					t.Errorf("faixa %d: PremioPorGanhador = %d, want %d", f.Acertos, f.PremioPorGanhador, want)
+				}
+				if f.Tipo == model.FaixaPercentual {
+					pago += f.PremioPorGanhador * f.Ganhadores
+				}
+			}
+			if total := pago + got.Residuo + got.Acumular; total != tt.premio+tt.acumulado {
+				t.Errorf("pago + resíduo + acumular = %d, want %d", total, tt.premio+tt.acumulado)
+			}
+		})
+	}
+}
+
+func TestValorPrincipal(t *testing.T) {
+	tests := []struct {
+		name   string
+		tabela []model.FaixaPremio
+		want   int64
+	}{
+		{"faixa percentual mais alta", tabelaMega, 350_000 + 20_000},
+		{"sem faixa percentual", []model.FaixaPremio{fixa(3, 500)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Calcular(tt.tabela, 1_000_000, 20_000, 0, nil)
+			if got := ValorPrincipal(tt.tabela, res.Faixas); got != tt.want {
+				t.Errorf("ValorPrincipal = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
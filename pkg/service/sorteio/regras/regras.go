@@ -0,0 +1,257 @@
+// Package regras concentra a validação de apostas de um sorteio: janela de
+// vendas com o corte configurado, formato dos números conforme o jogo e
+// limites por cliente. As violações são devolvidas campo a campo, para que a
+// API e o consumidor da fila reportem o mesmo motivo.
+package regras
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+)
+
+// LimiteDesdobramento é o padrão de números numa aposta desdobrada quando a
+// regra do jogo não define MaxNumeros.
+const LimiteDesdobramento = 15
+
+// Códigos de ErroCampo.
+const (
+	CodigoVendasEncerradas    = "vendas_encerradas"
+	CodigoQuantidade          = "quantidade_invalida"
+	CodigoForaDoIntervalo     = "fora_do_intervalo"
+	CodigoRepetido            = "repetido"
+	CodigoDesdobramento       = "desdobramento_invalido"
+	CodigoObrigatorio         = "obrigatorio"
+	CodigoNaoPermitido        = "nao_permitido"
+	CodigoLimiteBilhetes      = "limite_bilhetes"
+	CodigoLimiteValor         = "limite_valor"
+	CodigoClienteIndisponivel = "cliente_indisponivel"
+	CodigoSaldoInsuficiente   = "saldo_insuficiente"
+)
+
+// ErroValidacao agrupa as regras violadas por uma aposta.
+type ErroValidacao struct {
+	Erros []model.ErroCampo
+}
+
+func (e *ErroValidacao) Error() string {
+	partes := make([]string, len(e.Erros))
+	for i, c := range e.Erros {
+		partes[i] = c.Campo + ": " + c.Mensagem
+	}
+	return strings.Join(partes, "; ")
+}
+
+// Erro devolve nil quando não há violações e *ErroValidacao caso contrário.
+func Erro(erros []model.ErroCampo) error {
+	if len(erros) == 0 {
+		return nil
+	}
+	return &ErroValidacao{Erros: erros}
+}
+
+// Consumo é o que o cliente já tem em apostas pendentes e aceitas no sorteio.
+type Consumo struct {
+	Bilhetes int64
+	Valor    int64
+}
+
+// Entrada reúne o contexto da avaliação. Sem Regra valem só as regras do jogo.
+type Entrada struct {
+	Sorteio *model.Sorteio
+	Regra   *model.RegraJogo
+	Agora   time.Time
+	Consumo Consumo
+}
+
+// Avaliar aplica todas as regras a uma aposta de um bilhete, que custa o
+// valor do sorteio por linha quando desdobrada.
+func Avaliar(game jogo.GameType, e Entrada, aposta model.Aposta) []model.ErroCampo {
+	formato := game.Formato()
+	formato.Tamanhos = Tamanhos(formato, e.Regra)
+
+	linhas := int64(len(aposta.Linhas))
+	if linhas == 0 {
+		linhas = 1
+	}
+
+	erros := Venda(e)
+	erros = append(erros, Numeros(formato, aposta.Numeros)...)
+	erros = append(erros, Limites(e.Regra, e.Consumo, 1, linhas*e.Sorteio.ValorAposta)...)
+	return erros
+}
+
+// Tamanhos devolve as quantidades de números aceitas numa aposta: as do jogo
+// e, se ele for desdobrável, as maiores até o limite da regra.
+func Tamanhos(formato jogo.Formato, regra *model.RegraJogo) []int {
+	if !formato.Desdobravel || len(formato.Tamanhos) == 0 {
+		return formato.Tamanhos
+	}
+
+	limite := LimiteDesdobramento
+	if regra != nil && regra.MaxNumeros > 0 {
+		limite = regra.MaxNumeros
+	}
+	if universo := formato.Max - formato.Min + 1; limite > universo {
+		limite = universo
+	}
+
+	result := append([]int(nil), formato.Tamanhos...)
+	for n := formato.Tamanhos[0] + 1; n <= limite; n++ {
+		result = append(result, n)
+	}
+	return result
+}
+
+// Venda confere que o sorteio está aberto e que o corte ainda não passou.
+func Venda(e Entrada) []model.ErroCampo {
+	if e.Sorteio.Status != model.SorteioAberto {
+		return []model.ErroCampo{{
+			Campo:    "sorteio_id",
+			Codigo:   CodigoVendasEncerradas,
+			Mensagem: "vendas do sorteio não estão abertas",
+		}}
+	}
+
+	if !e.Sorteio.DataAbertura.IsZero() && e.Agora.Before(e.Sorteio.DataAbertura) {
+		return []model.ErroCampo{{
+			Campo:    "sorteio_id",
+			Codigo:   CodigoVendasEncerradas,
+			Mensagem: "vendas abrem em " + e.Sorteio.DataAbertura.Format(time.RFC3339),
+		}}
+	}
+
+	if corte := Corte(e.Sorteio, e.Regra); !corte.IsZero() && !e.Agora.Before(corte) {
+		return []model.ErroCampo{{
+			Campo:    "sorteio_id",
+			Codigo:   CodigoVendasEncerradas,
+			Mensagem: "vendas encerradas em " + corte.Format(time.RFC3339),
+		}}
+	}
+
+	return nil
+}
+
+// Corte é o instante em que as vendas do sorteio terminam. Zero quando o
+// sorteio não tem data de fechamento.
+func Corte(str *model.Sorteio, regra *model.RegraJogo) time.Time {
+	if str.DataFechamento.IsZero() {
+		return time.Time{}
+	}
+	if regra == nil {
+		return str.DataFechamento
+	}
+	return str.DataFechamento.Add(-time.Duration(regra.CorteMinutos) * time.Minute)
+}
+
+// Numeros confere a quantidade escolhida, o intervalo e a unicidade dos números.
+func Numeros(formato jogo.Formato, numeros []int) []model.ErroCampo {
+	erros := make([]model.ErroCampo, 0)
+
+	if !permitido(formato.Tamanhos, len(numeros)) {
+		erros = append(erros, model.ErroCampo{
+			Campo:    "numeros",
+			Codigo:   CodigoQuantidade,
+			Mensagem: fmt.Sprintf("escolha %s números, foram %d", descreverTamanhos(formato.Tamanhos), len(numeros)),
+		})
+	}
+
+	vistos := make(map[int]bool, len(numeros))
+	for i, n := range numeros {
+		campo := fmt.Sprintf("numeros[%d]", i)
+		switch {
+		case n < formato.Min || n > formato.Max:
+			erros = append(erros, model.ErroCampo{
+				Campo:    campo,
+				Codigo:   CodigoForaDoIntervalo,
+				Mensagem: fmt.Sprintf("número %d fora do intervalo %d a %d", n, formato.Min, formato.Max),
+			})
+		case vistos[n]:
+			erros = append(erros, model.ErroCampo{
+				Campo:    campo,
+				Codigo:   CodigoRepetido,
+				Mensagem: fmt.Sprintf("número %d repetido", n),
+			})
+		}
+		vistos[n] = true
+	}
+
+	return erros
+}
+
+// Limites confere se bilhetes novos no valor total informado cabem nos
+// limites do cliente no sorteio.
+func Limites(regra *model.RegraJogo, consumo Consumo, bilhetes, valor int64) []model.ErroCampo {
+	if regra == nil {
+		return nil
+	}
+
+	erros := make([]model.ErroCampo, 0)
+	if regra.MaxBilhetesCliente > 0 && consumo.Bilhetes+bilhetes > regra.MaxBilhetesCliente {
+		erros = append(erros, model.ErroCampo{
+			Campo:  "cliente_id",
+			Codigo: CodigoLimiteBilhetes,
+			Mensagem: fmt.Sprintf("limite de %d bilhetes por cliente no sorteio, já são %d",
+				regra.MaxBilhetesCliente, consumo.Bilhetes),
+		})
+	}
+	if regra.MaxValorCliente > 0 && consumo.Valor+valor > regra.MaxValorCliente {
+		erros = append(erros, model.ErroCampo{
+			Campo:  "cliente_id",
+			Codigo: CodigoLimiteValor,
+			Mensagem: fmt.Sprintf("limite de %d centavos por cliente no sorteio, já são %d",
+				regra.MaxValorCliente, consumo.Valor),
+		})
+	}
+
+	return erros
+}
+
+// ValidarRegra confere a configuração antes de gravá-la.
+func ValidarRegra(regra *model.RegraJogo) error {
+	if _, err := jogo.Obter(regra.Jogo); err != nil {
+		return fmt.Errorf("jogo %q não registrado", regra.Jogo)
+	}
+	if regra.CorteMinutos < 0 {
+		return fmt.Errorf("corte não pode ser negativo")
+	}
+	if regra.MaxBilhetesCliente < 0 || regra.MaxValorCliente < 0 {
+		return fmt.Errorf("limites por cliente não podem ser negativos")
+	}
+	if regra.MaxNumeros < 0 {
+		return fmt.Errorf("máximo de números não pode ser negativo")
+	}
+	return nil
+}
+
+func permitido(tamanhos []int, n int) bool {
+	for _, t := range tamanhos {
+		if t == n {
+			return true
+		}
+	}
+	return false
+}
+
+// descreverTamanhos junta as quantidades aceitas, resumindo sequências: "6 a 15".
+func descreverTamanhos(tamanhos []int) string {
+	partes := make([]string, 0, len(tamanhos))
+	for i := 0; i < len(tamanhos); {
+		j := i
+		for j+1 < len(tamanhos) && tamanhos[j+1] == tamanhos[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			partes = append(partes, fmt.Sprintf("%d a %d", tamanhos[i], tamanhos[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				partes = append(partes, fmt.Sprint(tamanhos[k]))
+			}
+		}
+		i = j + 1
+	}
+	return strings.Join(partes, " ou ")
+}
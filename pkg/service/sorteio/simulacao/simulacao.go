@@ -0,0 +1,253 @@
+// Package simulacao estima por Monte Carlo o retorno ao apostador (RTP) e a
+// distribuição dos pagamentos de uma configuração de jogo. Cada sorteio
+// simulado gera o resultado e ApostasPorSorteio apostas aleatórias com o
+// próprio tipo de jogo e aplica o mesmo rateio da liquidação real.
+//
+// Os sorteios são independentes: o acumulado não passa de um sorteio simulado
+// para o outro e aparece em AcumuladoMedio. Workers é limitado ao número de
+// CPUs; com a mesma Semente e a mesma quantidade efetiva de workers o
+// resultado é reproduzível.
+package simulacao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/rateio"
+)
+
+// LimiteApostas é o máximo de apostas simuladas (sorteios x apostas por sorteio)
+// aceito numa única chamada.
+const LimiteApostas = 50_000_000
+
+var ErrParametrosInvalidos = errors.New("parâmetros da simulação inválidos")
+
+// estatisticas acumula os números de um worker antes da consolidação.
+type estatisticas struct {
+	ganhadores map[int]int64
+	sorteios   map[int]int64
+	pago       map[int]int64
+	acumulado  int64
+	residuo    int64
+}
+
+func novasEstatisticas() *estatisticas {
+	return &estatisticas{
+		ganhadores: make(map[int]int64),
+		sorteios:   make(map[int]int64),
+		pago:       make(map[int]int64),
+	}
+}
+
+// Simular executa params.Sorteios sorteios em paralelo e consolida o resultado.
+func Simular(ctx context.Context, params model.ParametrosSimulacao) (*model.ResultadoSimulacao, error) {
+	game, err := validar(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mais workers que CPUs não acelera a simulação, só multiplica goroutines
+	// e estatísticas parciais.
+	workers := params.Workers
+	if workers <= 0 || workers > runtime.NumCPU() {
+		workers = runtime.NumCPU()
+	}
+	if workers > params.Sorteios {
+		workers = params.Sorteios
+	}
+
+	semente := params.Semente
+	if semente == 0 {
+		semente = time.Now().UnixNano()
+	}
+
+	arrecadado := params.ApostasPorSorteio * params.ValorAposta
+	premio := rateio.ValorPremio(arrecadado, params.PercentualPremio)
+
+	// Cada worker escreve apenas nos índices dos seus sorteios.
+	pagamentos := make([]int64, params.Sorteios)
+	parciais := make([]*estatisticas, workers)
+	erros := make([]error, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(semente + int64(w)))
+			est := novasEstatisticas()
+			parciais[w] = est
+
+			for i := w; i < params.Sorteios; i += workers {
+				if err := ctx.Err(); err != nil {
+					erros[w] = err
+					return
+				}
+
+				pago, err := simularSorteio(r, game, params, premio, est)
+				if err != nil {
+					erros[w] = err
+					return
+				}
+				pagamentos[i] = pago
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for _, err := range erros {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	total := novasEstatisticas()
+	for _, est := range parciais {
+		for k, v := range est.ganhadores {
+			total.ganhadores[k] += v
+		}
+		for k, v := range est.sorteios {
+			total.sorteios[k] += v
+		}
+		for k, v := range est.pago {
+			total.pago[k] += v
+		}
+		total.acumulado += est.acumulado
+		total.residuo += est.residuo
+	}
+
+	return consolidar(params, semente, arrecadado, pagamentos, total), nil
+}
+
+// simularSorteio gera um resultado, as apostas do sorteio e aplica o rateio,
+// devolvendo o total pago aos ganhadores.
+func simularSorteio(r *rand.Rand, game jogo.GameType, params model.ParametrosSimulacao, premio int64, est *estatisticas) (int64, error) {
+	resultado, err := game.GerarResultado(r)
+	if err != nil {
+		return 0, err
+	}
+
+	ganhadores := make(map[int]int64)
+	for a := int64(0); a < params.ApostasPorSorteio; a++ {
+		ganhadores[game.ContarAcertos(game.GerarAposta(r), resultado)]++
+	}
+
+	res := rateio.Calcular(params.TabelaPremios, premio, 0, 0, ganhadores)
+
+	var pago int64
+	for _, f := range res.Faixas {
+		if f.Ganhadores == 0 {
+			continue
+		}
+
+		valor := f.PremioPorGanhador * f.Ganhadores
+		pago += valor
+
+		est.ganhadores[f.Acertos] += f.Ganhadores
+		est.sorteios[f.Acertos]++
+		est.pago[f.Acertos] += valor
+	}
+	est.acumulado += res.Acumular
+	est.residuo += res.Residuo
+
+	return pago, nil
+}
+
+func consolidar(params model.ParametrosSimulacao, semente, arrecadado int64, pagamentos []int64, total *estatisticas) *model.ResultadoSimulacao {
+	n := float64(len(pagamentos))
+
+	var soma float64
+	for _, p := range pagamentos {
+		soma += float64(p)
+	}
+	media := soma / n
+
+	var variancia float64
+	for _, p := range pagamentos {
+		d := float64(p) - media
+		variancia += d * d
+	}
+	variancia /= n
+
+	ordenados := append([]int64(nil), pagamentos...)
+	sort.Slice(ordenados, func(i, j int) bool { return ordenados[i] < ordenados[j] })
+
+	result := &model.ResultadoSimulacao{
+		Jogo:                 params.Jogo,
+		Sorteios:             params.Sorteios,
+		ApostasPorSorteio:    params.ApostasPorSorteio,
+		Semente:              semente,
+		ArrecadadoPorSorteio: arrecadado,
+		PagamentoMedio:       media,
+		DesvioPadrao:         math.Sqrt(variancia),
+		Percentis: model.PercentisPagamento{
+			P50:    percentil(ordenados, 0.50),
+			P90:    percentil(ordenados, 0.90),
+			P95:    percentil(ordenados, 0.95),
+			P99:    percentil(ordenados, 0.99),
+			Maximo: ordenados[len(ordenados)-1],
+		},
+		AcumuladoMedio: float64(total.acumulado) / n,
+		ResiduoMedio:   float64(total.residuo) / n,
+		Faixas:         make([]model.ProbabilidadeFaixa, 0, len(params.TabelaPremios)),
+	}
+	if arrecadado > 0 {
+		result.RTP = media / float64(arrecadado)
+	}
+
+	apostas := n * float64(params.ApostasPorSorteio)
+	for _, f := range params.TabelaPremios {
+		result.Faixas = append(result.Faixas, model.ProbabilidadeFaixa{
+			Acertos:              f.Acertos,
+			Tipo:                 f.Tipo,
+			ProbabilidadeAposta:  float64(total.ganhadores[f.Acertos]) / apostas,
+			ProbabilidadeSorteio: float64(total.sorteios[f.Acertos]) / n,
+			GanhadoresMedio:      float64(total.ganhadores[f.Acertos]) / n,
+			PagamentoMedio:       float64(total.pago[f.Acertos]) / n,
+		})
+	}
+
+	return result
+}
+
+// percentil usa o método do posto mais próximo sobre valores já ordenados.
+func percentil(ordenados []int64, p float64) int64 {
+	i := int(math.Ceil(p*float64(len(ordenados)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return ordenados[i]
+}
+
+func validar(params model.ParametrosSimulacao) (jogo.GameType, error) {
+	game, err := jogo.Obter(params.Jogo)
+	if err != nil {
+		return nil, fmt.Errorf("%w: jogo %q não registrado", ErrParametrosInvalidos, params.Jogo)
+	}
+	if params.ValorAposta <= 0 {
+		return nil, fmt.Errorf("%w: valor da aposta deve ser informado em centavos", ErrParametrosInvalidos)
+	}
+	if params.PercentualPremio < 0 || params.PercentualPremio > model.BaseCalculo {
+		return nil, fmt.Errorf("%w: percentual do prêmio deve estar entre 0 e %d", ErrParametrosInvalidos, model.BaseCalculo)
+	}
+	if err := rateio.ValidarTabela(params.TabelaPremios); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrParametrosInvalidos, err.Error())
+	}
+	if params.Sorteios <= 0 || params.ApostasPorSorteio <= 0 {
+		return nil, fmt.Errorf("%w: informe a quantidade de sorteios e de apostas por sorteio", ErrParametrosInvalidos)
+	}
+	if params.ApostasPorSorteio > LimiteApostas || int64(params.Sorteios)*params.ApostasPorSorteio > LimiteApostas {
+		return nil, fmt.Errorf("%w: no máximo %d apostas simuladas por chamada", ErrParametrosInvalidos, LimiteApostas)
+	}
+	return game, nil
+}
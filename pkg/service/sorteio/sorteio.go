@@ -0,0 +1,1239 @@
+package sorteio
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/katana/fortuna/backend-go/internal/config"
+	"github.com/katana/fortuna/backend-go/internal/config/logger"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/mongodb"
+	"github.com/katana/fortuna/backend-go/pkg/adapter/rabbitmq"
+	"github.com/katana/fortuna/backend-go/pkg/model"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/fair"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/jogo"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/merkle"
+	"github.com/katana/fortuna/backend-go/pkg/service/sorteio/rateio"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrSorteioNaoEncontrado  = errors.New("sorteio não encontrado")
+	ErrTransicaoInvalida     = errors.New("transição de status inválida")
+	ErrTransicaoConcorrente  = errors.New("sorteio alterado por outra requisição")
+	ErrForaDaJanela          = errors.New("fora da janela de vendas do sorteio")
+	ErrSorteioInvalido       = errors.New("dados do sorteio inválidos")
+	ErrEntropiaObrigatoria   = errors.New("entropia pública obrigatória para o sorteio")
+	ErrFonteDivergente       = errors.New("entropia de fonte diferente da fixada na abertura")
+	ErrSemProximoSorteio     = errors.New("nenhum sorteio programado para o jogo")
+	ErrApostaNaoEncontrada   = errors.New("aposta não encontrada no sorteio")
+	ErrSemCompromisso        = errors.New("apostas do sorteio ainda não comprometidas")
+	ErrApostaNaoComprometida = errors.New("aposta fora do conjunto comprometido no fechamento")
+	ErrEtapasPendentes       = errors.New("etapas do sorteio ainda pendentes")
+)
+
+type SorteioServiceInterface interface {
+	Create(ctx context.Context, sorteio model.Sorteio) (*model.Sorteio, error)
+	CreateRecorrente(ctx context.Context, sorteio model.Sorteio) (*model.Sorteio, bool, error)
+	Update(ctx context.Context, ID string, sorteioToChange *model.Sorteio) (bool, error)
+	GetByID(ctx context.Context, ID string) (*model.Sorteio, error)
+	GetAll(ctx context.Context, filters model.FilterSorteio, limit, page int64) (*model.Paginate, error)
+	GetVencidos(ctx context.Context, status string, ate time.Time) ([]*model.Sorteio, error)
+	Abrir(ctx context.Context, ID string) (*model.Sorteio, error)
+	Fechar(ctx context.Context, ID string) (*model.Sorteio, error)
+	Sortear(ctx context.Context, ID string, fonte, entropia string) (*model.Sorteio, error)
+	Liquidar(ctx context.Context, ID string) (*model.Sorteio, error)
+	Cancelar(ctx context.Context, ID string, motivo string) (*model.Sorteio, error)
+	Verificar(ctx context.Context, ID string) (*model.VerificacaoSorteio, error)
+	Rateio(ctx context.Context, ID string) (*model.RateioSorteio, error)
+	Estimativa(ctx context.Context, nomeJogo string) (*model.EstimativaPremio, error)
+	ProvaInclusao(ctx context.Context, ID string, apostaID string) (*model.ProvaInclusao, error)
+	Reprocessar(ctx context.Context, ID string) (*model.Sorteio, error)
+}
+
+// etapaSorteio é uma etapa registrada em AoLiquidar ou AoTransicionar. O nome
+// fica em Sorteio.EtapasPendentes quando ela falha, para o Reprocessar.
+type etapaSorteio struct {
+	nome     string
+	executar func(ctx context.Context, sorteio *model.Sorteio) error
+}
+
+type SorteioDataService struct {
+	mdb            mongodb.MongoDBInterface
+	rbt            rabbitmq.RabbitInterface
+	conf           *config.Config
+	aoLiquidar     []etapaSorteio
+	aoTransicionar []etapaSorteio
+}
+
+func NewSorteioService(rabbit_connection rabbitmq.RabbitInterface, mongo_connection mongodb.MongoDBInterface, conf *config.Config) *SorteioDataService {
+	return &SorteioDataService{
+		mdb:  mongo_connection,
+		rbt:  rabbit_connection,
+		conf: conf,
+	}
+}
+
+func (sds *SorteioDataService) Create(ctx context.Context, sorteio model.Sorteio) (*model.Sorteio, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	// Sem janela informada, o sorteio usa a janela global da configuração.
+	if sorteio.DataAbertura.IsZero() {
+		sorteio.DataAbertura = sds.conf.DataInicial
+	}
+	if sorteio.DataFechamento.IsZero() {
+		sorteio.DataFechamento = sds.conf.DataFinal
+	}
+	if sorteio.DataSorteio.IsZero() {
+		sorteio.DataSorteio = sorteio.DataFechamento
+	}
+
+	if err := validarSorteio(sorteio); err != nil {
+		return nil, err
+	}
+
+	str := model.NewSorteio(sorteio)
+	result, err := collection.InsertOne(ctx, str)
+	if err != nil {
+		logger.Error("erro salvar Sorteio", err)
+		return nil, err
+	}
+
+	str.ID = result.InsertedID.(primitive.ObjectID)
+
+	return str, nil
+}
+
+// CreateRecorrente cria o sorteio de uma agenda apenas se ainda não existir um
+// sorteio da mesma agenda na mesma data. Pode ser chamado por várias réplicas ao
+// mesmo tempo; o booleano indica se este chamador foi quem criou o documento.
+func (sds *SorteioDataService) CreateRecorrente(ctx context.Context, sorteio model.Sorteio) (*model.Sorteio, bool, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	if sorteio.AgendaID.IsZero() {
+		return nil, false, fmt.Errorf("%w: sorteio recorrente sem agenda", ErrSorteioInvalido)
+	}
+	if err := validarSorteio(sorteio); err != nil {
+		return nil, false, err
+	}
+
+	str := model.NewSorteio(sorteio)
+
+	filter := bson.D{
+		{Key: "data_type", Value: "sorteio"},
+		{Key: "agenda_id", Value: str.AgendaID},
+		{Key: "data_sorteio", Value: str.DataSorteio},
+	}
+	update := bson.D{{Key: "$setOnInsert", Value: str}}
+
+	result, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, false, nil
+		}
+		logger.Error("erro salvar Sorteio recorrente", err)
+		return nil, false, err
+	}
+
+	if result.UpsertedCount == 0 {
+		return nil, false, nil
+	}
+
+	return str, true, nil
+}
+
+func (sds *SorteioDataService) Update(ctx context.Context, ID string, sorteio *model.Sorteio) (bool, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		logger.Error("Error to parse ObjectIDFromHex", err)
+		return false, err
+	}
+
+	// Janela e nome só podem mudar enquanto o sorteio ainda não abriu as vendas.
+	filter := bson.D{
+		{Key: "_id", Value: objectID},
+		{Key: "data_type", Value: "sorteio"},
+		{Key: "status", Value: model.SorteioAgendado},
+	}
+
+	values := bson.D{
+		{Key: "nome", Value: sorteio.Nome},
+		{Key: "enabled", Value: sorteio.Enabled},
+		{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+	}
+	if !sorteio.DataAbertura.IsZero() {
+		values = append(values, bson.E{Key: "data_abertura", Value: sorteio.DataAbertura})
+	}
+	if !sorteio.DataFechamento.IsZero() {
+		values = append(values, bson.E{Key: "data_fechamento", Value: sorteio.DataFechamento})
+	}
+	if !sorteio.DataSorteio.IsZero() {
+		values = append(values, bson.E{Key: "data_sorteio", Value: sorteio.DataSorteio})
+	}
+	if sorteio.ValorAposta > 0 {
+		values = append(values, bson.E{Key: "valor_aposta", Value: sorteio.ValorAposta})
+	}
+	if sorteio.PercentualPremio > 0 {
+		if sorteio.PercentualPremio > model.BaseCalculo {
+			return false, fmt.Errorf("%w: percentual do prêmio acima de 100%%", ErrSorteioInvalido)
+		}
+		values = append(values, bson.E{Key: "percentual_premio", Value: sorteio.PercentualPremio})
+	}
+	if sorteio.TetoAcumulado > 0 {
+		values = append(values, bson.E{Key: "teto_acumulado", Value: sorteio.TetoAcumulado})
+	}
+	if fonte := strings.TrimSpace(sorteio.FonteEntropia); fonte != "" {
+		values = append(values, bson.E{Key: "fonte_entropia", Value: fonte})
+	}
+	if sorteio.TabelaPremios != nil {
+		if err := rateio.ValidarTabela(sorteio.TabelaPremios); err != nil {
+			return false, fmt.Errorf("%w: %s", ErrSorteioInvalido, err.Error())
+		}
+		values = append(values, bson.E{Key: "tabela_premios", Value: sorteio.TabelaPremios})
+	}
+
+	update := bson.D{{Key: "$set", Value: values}}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error while updating data", err)
+		return false, err
+	}
+
+	if result.MatchedCount == 0 {
+		return false, ErrTransicaoInvalida
+	}
+
+	return true, nil
+}
+
+func (sds *SorteioDataService) GetByID(ctx context.Context, ID string) (*model.Sorteio, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	sorteio := &model.Sorteio{}
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		logger.Error("Error to parse ObjectIDFromHex", err)
+		return nil, err
+	}
+
+	filter := bson.D{
+		{Key: "data_type", Value: "sorteio"},
+		{Key: "_id", Value: objectID},
+	}
+
+	err = collection.FindOne(ctx, filter).Decode(sorteio)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrSorteioNaoEncontrado
+		}
+		logger.Error("erro ao consultar Sorteio", err)
+		return nil, err
+	}
+
+	return sorteio, nil
+}
+
+func (sds *SorteioDataService) GetAll(ctx context.Context, filters model.FilterSorteio, limit, page int64) (*model.Paginate, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	query := bson.M{"data_type": "sorteio"}
+
+	if filters.Nome != "" {
+		query["nome"] = bson.M{"$regex": fmt.Sprintf(".*%s.*", filters.Nome), "$options": "i"}
+	}
+	if filters.Jogo != "" {
+		query["jogo"] = filters.Jogo
+	}
+	if filters.Status != "" {
+		query["status"] = filters.Status
+	}
+	if filters.Enabled != "" {
+		enable, err := strconv.ParseBool(filters.Enabled)
+		if err != nil {
+			logger.Error("erro converter campo enabled", err)
+			return nil, err
+		}
+		query["enabled"] = enable
+	}
+
+	count, err := collection.CountDocuments(ctx, query, &options.CountOptions{})
+	if err != nil {
+		logger.Error("erro ao consultar todos os Sorteios", err)
+		return nil, err
+	}
+
+	pagination := model.NewPaginate(limit, page, count)
+
+	opts := pagination.GetPaginatedOpts()
+	opts.SetSort(bson.D{{Key: "data_sorteio", Value: -1}})
+
+	curr, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Sorteio, 0)
+	for curr.Next(ctx) {
+		str := &model.Sorteio{}
+		if err := curr.Decode(str); err != nil {
+			logger.Error("erro ao consulta todos os Sorteios", err)
+		}
+		result = append(result, str)
+	}
+
+	pagination.Paginate(result)
+
+	return pagination, nil
+}
+
+// GetVencidos devolve os sorteios no status informado cujo prazo da próxima
+// transição já passou: abertura para agendados e fechamento para abertos.
+func (sds *SorteioDataService) GetVencidos(ctx context.Context, status string, ate time.Time) ([]*model.Sorteio, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	campo := "data_abertura"
+	if status == model.SorteioAberto {
+		campo = "data_fechamento"
+	}
+
+	filter := bson.D{
+		{Key: "data_type", Value: "sorteio"},
+		{Key: "enabled", Value: true},
+		{Key: "status", Value: status},
+		{Key: campo, Value: bson.D{{Key: "$lte", Value: ate}}},
+	}
+
+	curr, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: campo, Value: 1}}))
+	if err != nil {
+		logger.Error("erro ao consultar Sorteios vencidos", err)
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := make([]*model.Sorteio, 0)
+	for curr.Next(ctx) {
+		str := &model.Sorteio{}
+		if err := curr.Decode(str); err != nil {
+			logger.Error("erro ao consultar Sorteios vencidos", err)
+			continue
+		}
+		result = append(result, str)
+	}
+
+	return result, nil
+}
+
+// Abrir inicia as vendas. O sorteio precisa ter a fonte pública da entropia,
+// como a altura de um bloco futuro ou um concurso oficial ainda não realizado;
+// a partir daqui ela não muda e o Sortear só aceita a entropia dessa fonte.
+func (sds *SorteioDataService) Abrir(ctx context.Context, ID string) (*model.Sorteio, error) {
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sorteio.FonteEntropia == "" {
+		return nil, fmt.Errorf("%w: defina a fonte da entropia antes de abrir", ErrEntropiaObrigatoria)
+	}
+
+	if !sorteio.JanelaAberta(time.Now()) {
+		return nil, ErrForaDaJanela
+	}
+
+	// Commit da seed: só o hash é publicado enquanto as vendas estão abertas.
+	seed, err := fair.GerarSeed()
+	if err != nil {
+		logger.Error("erro ao gerar seed do Sorteio", err)
+		return nil, err
+	}
+
+	// O acumulado que ficou sem sorteio programado entra neste sorteio na
+	// mesma transação da abertura: se o resgate falhar o sorteio continua
+	// agendado e a abertura pode ser repetida.
+	result, err := sds.mdb.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		aberto, err := sds.gravarTransicao(sessCtx, sorteio, model.SorteioAberto, "", bson.D{
+			{Key: "server_seed", Value: seed},
+			{Key: "seed_hash", Value: fair.Commit(seed)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		valor, err := sds.resgatarAcumulado(sessCtx, aberto)
+		if err != nil {
+			logger.Error("erro ao resgatar acumulado do jogo", err)
+			return nil, err
+		}
+		aberto.Acumulado += valor
+
+		return aberto, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	aberto := result.(*model.Sorteio)
+	sds.executarEtapas(ctx, aberto, sds.aoTransicionar)
+
+	return aberto, nil
+}
+
+func (sds *SorteioDataService) Fechar(ctx context.Context, ID string) (*model.Sorteio, error) {
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fechado, err := sds.transicionar(ctx, sorteio, model.SorteioFechado, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Com as vendas encerradas o conjunto de apostas é comprometido antes do
+	// resultado. Se falhar, o Sortear tenta de novo antes de gerar o resultado.
+	if err := sds.comprometerApostas(ctx, fechado); err != nil {
+		logger.Error("erro ao comprometer apostas do Sorteio", err)
+		return nil, err
+	}
+
+	return fechado, nil
+}
+
+func (sds *SorteioDataService) Sortear(ctx context.Context, ID string, fonte, entropia string) (*model.Sorteio, error) {
+	if strings.TrimSpace(entropia) == "" {
+		return nil, ErrEntropiaObrigatoria
+	}
+
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem fonte fixada antes das vendas, quem sorteia poderia escolher a
+	// entropia depois de ver as apostas.
+	if sorteio.FonteEntropia == "" || strings.TrimSpace(fonte) != sorteio.FonteEntropia {
+		return nil, fmt.Errorf("%w: esperada %q", ErrFonteDivergente, sorteio.FonteEntropia)
+	}
+
+	if !sorteio.PodeTransitar(model.SorteioSorteado) {
+		return nil, ErrTransicaoInvalida
+	}
+
+	// O resultado nunca é gerado sem a raiz das apostas gravada.
+	if sorteio.MerkleRaiz == "" {
+		if err := sds.comprometerApostas(ctx, sorteio); err != nil {
+			logger.Error("erro ao comprometer apostas do Sorteio", err)
+			return nil, err
+		}
+	}
+
+	game, err := jogo.Obter(sorteio.Jogo)
+	if err != nil {
+		return nil, err
+	}
+
+	resultado, err := game.GerarResultado(fair.NovoStream(sorteio.ServerSeed, entropia))
+	if err != nil {
+		logger.Error("erro ao gerar resultado do Sorteio", err)
+		return nil, err
+	}
+
+	return sds.transicionar(ctx, sorteio, model.SorteioSorteado, "", bson.D{
+		{Key: "resultado", Value: resultado},
+		{Key: "entropia", Value: entropia},
+	})
+}
+
+// Liquidar apura os acertos das apostas aceitas, calcula o rateio pela tabela
+// de prêmios e grava o prêmio de cada aposta antes de marcar o sorteio como liquidado.
+func (sds *SorteioDataService) Liquidar(ctx context.Context, ID string) (*model.Sorteio, error) {
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sorteio.PodeTransitar(model.SorteioLiquidado) {
+		return nil, ErrTransicaoInvalida
+	}
+
+	game, err := jogo.Obter(sorteio.Jogo)
+	if err != nil {
+		return nil, err
+	}
+
+	apuracao, err := sds.apurar(ctx, sorteio, game)
+	if err != nil {
+		logger.Error("erro ao apurar apostas do Sorteio", err)
+		return nil, err
+	}
+
+	valorPremio := rateio.ValorPremio(apuracao.arrecadado, sorteio.PercentualPremio)
+	res := rateio.Calcular(sorteio.TabelaPremios, valorPremio, sorteio.Acumulado, sorteio.TetoAcumulado, apuracao.ganhadores)
+
+	if err := sds.gravarPremios(ctx, sorteio, res.Faixas); err != nil {
+		logger.Error("erro ao gravar prêmios do Sorteio", err)
+		return nil, err
+	}
+
+	liquidado, err := sds.transicionar(ctx, sorteio, model.SorteioLiquidado, "", bson.D{
+		{Key: "qtd_apostas", Value: apuracao.apostas},
+		{Key: "total_arrecadado", Value: apuracao.arrecadado},
+		{Key: "valor_premio", Value: valorPremio},
+		{Key: "rateio", Value: res.Faixas},
+		{Key: "residuo", Value: res.Residuo},
+		{Key: "acumulado_proximo", Value: res.Acumular},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Só quem efetivou a liquidação repassa o acumulado, evitando repasse em dobro.
+	if res.Acumular > 0 {
+		if err := sds.repassarAcumulado(ctx, liquidado, res.Acumular); err != nil {
+			logger.Error("erro ao repassar acumulado do Sorteio", err)
+		}
+	}
+
+	sds.executarEtapas(ctx, liquidado, sds.aoLiquidar)
+
+	return liquidado, nil
+}
+
+// AoLiquidar registra uma etapa executada depois que o sorteio é liquidado,
+// com os prêmios das apostas já gravados. Uma falha não desfaz a liquidação:
+// o nome vai para Sorteio.EtapasPendentes até o Reprocessar, então cada etapa
+// precisa poder ser repetida à parte.
+func (sds *SorteioDataService) AoLiquidar(nome string, etapa func(ctx context.Context, sorteio *model.Sorteio) error) {
+	sds.aoLiquidar = append(sds.aoLiquidar, etapaSorteio{nome: nome, executar: etapa})
+}
+
+// AoTransicionar registra uma etapa executada depois de cada mudança de
+// status gravada, com o sorteio já atualizado. Falhas ficam pendentes como
+// nas etapas de AoLiquidar.
+func (sds *SorteioDataService) AoTransicionar(nome string, etapa func(ctx context.Context, sorteio *model.Sorteio) error) {
+	sds.aoTransicionar = append(sds.aoTransicionar, etapaSorteio{nome: nome, executar: etapa})
+}
+
+// Reprocessar executa de novo as etapas que falharam depois de uma transição
+// ou da liquidação, com o sorteio no estado atual. As que passam saem de
+// EtapasPendentes; se alguma falhar outra vez, devolve ErrEtapasPendentes.
+func (sds *SorteioDataService) Reprocessar(ctx context.Context, ID string) (*model.Sorteio, error) {
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	etapas := make([]etapaSorteio, 0, len(sorteio.EtapasPendentes))
+	for _, etapa := range append(append([]etapaSorteio{}, sds.aoTransicionar...), sds.aoLiquidar...) {
+		if slices.Contains(sorteio.EtapasPendentes, etapa.nome) {
+			etapas = append(etapas, etapa)
+		}
+	}
+
+	falhas := sds.executarEtapas(ctx, sorteio, etapas)
+
+	atualizado, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if falhas > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEtapasPendentes, strings.Join(atualizado.EtapasPendentes, ", "))
+	}
+
+	return atualizado, nil
+}
+
+// executarEtapas roda as etapas em ordem e grava no sorteio quais falharam e
+// quais passaram, devolvendo quantas falharam.
+func (sds *SorteioDataService) executarEtapas(ctx context.Context, sorteio *model.Sorteio, etapas []etapaSorteio) int {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	falhas := 0
+	for _, etapa := range etapas {
+		operador := "$pull"
+		if err := etapa.executar(ctx, sorteio); err != nil {
+			logger.Error("erro na etapa "+etapa.nome+" do Sorteio "+sorteio.ID.Hex(), err)
+			operador = "$addToSet"
+			falhas++
+		} else if !slices.Contains(sorteio.EtapasPendentes, etapa.nome) {
+			continue
+		}
+
+		filter := bson.D{
+			{Key: "_id", Value: sorteio.ID},
+			{Key: "data_type", Value: "sorteio"},
+		}
+		update := bson.D{{Key: operador, Value: bson.D{{Key: "etapas_pendentes", Value: etapa.nome}}}}
+		if _, err := collection.UpdateOne(ctx, filter, update); err != nil {
+			logger.Error("erro ao gravar etapa pendente "+etapa.nome+" do Sorteio "+sorteio.ID.Hex(), err)
+		}
+	}
+
+	return falhas
+}
+
+func (sds *SorteioDataService) Cancelar(ctx context.Context, ID string, motivo string) (*model.Sorteio, error) {
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sds.transicionar(ctx, sorteio, model.SorteioCancelado, motivo, nil)
+}
+
+// Verificar devolve os dados do commit-reveal e, depois do sorteio, o resultado recalculado.
+func (sds *SorteioDataService) Verificar(ctx context.Context, ID string) (*model.VerificacaoSorteio, error) {
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	verificacao := &model.VerificacaoSorteio{
+		SorteioID:     sorteio.ID,
+		Status:        sorteio.Status,
+		SeedHash:      sorteio.SeedHash,
+		FonteEntropia: sorteio.FonteEntropia,
+		Jogo:          sorteio.Jogo,
+		MerkleRaiz:    sorteio.MerkleRaiz,
+	}
+
+	// A seed só é revelada depois que o resultado foi gerado.
+	if sorteio.Status != model.SorteioSorteado && sorteio.Status != model.SorteioLiquidado {
+		verificacao.Motivo = "seed ainda não revelada"
+		return verificacao, nil
+	}
+
+	verificacao.ServerSeed = sorteio.ServerSeed
+	verificacao.Entropia = sorteio.Entropia
+	verificacao.Resultado = sorteio.Resultado
+
+	game, err := jogo.Obter(sorteio.Jogo)
+	if err != nil {
+		return nil, err
+	}
+
+	recalculado, err := jogo.Verificar(game, sorteio.ServerSeed, sorteio.SeedHash, sorteio.Entropia)
+	if err != nil {
+		verificacao.Motivo = err.Error()
+		return verificacao, nil
+	}
+
+	verificacao.Recalculado = recalculado
+	verificacao.Valido = slices.Equal(recalculado, sorteio.Resultado)
+	if !verificacao.Valido {
+		verificacao.Motivo = "resultado publicado difere do recalculado"
+	}
+
+	return verificacao, nil
+}
+
+func (sds *SorteioDataService) Rateio(ctx context.Context, ID string) (*model.RateioSorteio, error) {
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.RateioSorteio{
+		SorteioID:       sorteio.ID,
+		Status:          sorteio.Status,
+		QtdApostas:      sorteio.QtdApostas,
+		TotalArrecadado: sorteio.TotalArrecadado,
+		ValorPremio:     sorteio.ValorPremio,
+		Acumulado:       sorteio.Acumulado,
+		Faixas:          sorteio.Rateio,
+		Residuo:         sorteio.Residuo,
+		Acumular:        sorteio.AcumuladoProximo,
+	}
+
+	// Antes da liquidação o rateio é uma prévia sem ganhadores.
+	if sorteio.Status != model.SorteioLiquidado {
+		result.ValorPremio = rateio.ValorPremio(sorteio.TotalArrecadado, sorteio.PercentualPremio)
+		res := rateio.Calcular(sorteio.TabelaPremios, result.ValorPremio, sorteio.Acumulado, sorteio.TetoAcumulado, nil)
+		result.Faixas, result.Residuo, result.Acumular = res.Faixas, res.Residuo, res.Acumular
+	}
+
+	return result, nil
+}
+
+// Estimativa calcula a faixa principal do próximo sorteio do jogo com as
+// vendas registradas até agora e o acumulado que ele vai receber.
+func (sds *SorteioDataService) Estimativa(ctx context.Context, nomeJogo string) (*model.EstimativaPremio, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "data_type", Value: "sorteio"},
+		{Key: "jogo", Value: nomeJogo},
+		{Key: "enabled", Value: true},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{model.SorteioAgendado, model.SorteioAberto}}}},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "data_sorteio", Value: 1}})
+
+	proximo := &model.Sorteio{}
+	err := collection.FindOne(ctx, filter, opts).Decode(proximo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrSemProximoSorteio
+		}
+		logger.Error("erro ao consultar proximo Sorteio", err)
+		return nil, err
+	}
+
+	// Ainda agendado, o sorteio não resgatou o acumulado pendente do jogo.
+	acumulado := proximo.Acumulado
+	if proximo.Status == model.SorteioAgendado {
+		pendente, err := sds.acumuladoPendente(ctx, nomeJogo)
+		if err != nil {
+			return nil, err
+		}
+		acumulado += pendente
+	}
+
+	valorPremio := rateio.ValorPremio(proximo.TotalArrecadado, proximo.PercentualPremio)
+	res := rateio.Calcular(proximo.TabelaPremios, valorPremio, acumulado, proximo.TetoAcumulado, nil)
+
+	return &model.EstimativaPremio{
+		SorteioID:   proximo.ID,
+		Jogo:        proximo.Jogo,
+		Status:      proximo.Status,
+		DataSorteio: proximo.DataSorteio,
+		Acumulado:   acumulado,
+		Estimativa:  rateio.ValorPrincipal(proximo.TabelaPremios, res.Faixas),
+	}, nil
+}
+
+// repassarAcumulado soma o valor ao próximo sorteio do mesmo jogo que ainda
+// não foi sorteado. Sem sorteio programado, o valor fica guardado no
+// acumulado do jogo até o próximo sorteio abrir as vendas.
+func (sds *SorteioDataService) repassarAcumulado(ctx context.Context, sorteio *model.Sorteio, valor int64) error {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	naoSorteado := bson.D{{Key: "$in", Value: bson.A{model.SorteioAgendado, model.SorteioAberto, model.SorteioFechado}}}
+
+	filter := bson.D{
+		{Key: "data_type", Value: "sorteio"},
+		{Key: "jogo", Value: sorteio.Jogo},
+		{Key: "_id", Value: bson.D{{Key: "$ne", Value: sorteio.ID}}},
+		{Key: "status", Value: naoSorteado},
+		{Key: "data_sorteio", Value: bson.D{{Key: "$gt", Value: sorteio.DataSorteio}}},
+	}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "data_sorteio", Value: 1}})
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "acumulado", Value: valor}}}}
+
+	err := collection.FindOneAndUpdate(ctx, filter, update, opts).Err()
+	if err == nil {
+		return nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	_, err = collection.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: "acumulado_" + sorteio.Jogo}},
+		bson.D{
+			{Key: "$inc", Value: bson.D{{Key: "valor", Value: valor}}},
+			{Key: "$set", Value: bson.D{{Key: "data_type", Value: "acumulado"}, {Key: "jogo", Value: sorteio.Jogo}}},
+		},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+// resgatarAcumulado zera o acumulado guardado do jogo e o soma ao sorteio.
+// Roda na transação da abertura, para o valor não sumir se a segunda escrita
+// ou a própria abertura falhar.
+func (sds *SorteioDataService) resgatarAcumulado(sessCtx mongo.SessionContext, sorteio *model.Sorteio) (int64, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	guardado := struct {
+		Valor int64 `bson:"valor"`
+	}{}
+
+	filter := bson.D{
+		{Key: "_id", Value: "acumulado_" + sorteio.Jogo},
+		{Key: "valor", Value: bson.D{{Key: "$gt", Value: 0}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "valor", Value: 0}}}}
+
+	err := collection.FindOneAndUpdate(sessCtx, filter, update).Decode(&guardado)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	_, err = collection.UpdateOne(sessCtx,
+		bson.D{{Key: "_id", Value: sorteio.ID}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "acumulado", Value: guardado.Valor}}}})
+	if err != nil {
+		return 0, err
+	}
+
+	return guardado.Valor, nil
+}
+
+func (sds *SorteioDataService) acumuladoPendente(ctx context.Context, nomeJogo string) (int64, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	guardado := struct {
+		Valor int64 `bson:"valor"`
+	}{}
+
+	err := collection.FindOne(ctx, bson.D{{Key: "_id", Value: "acumulado_" + nomeJogo}}).Decode(&guardado)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+
+	return guardado.Valor, nil
+}
+
+type apuracao struct {
+	apostas    int64
+	arrecadado int64
+	ganhadores map[int]int64
+}
+
+// apurar conta os acertos de cada aposta comprometida no fechamento e grava o
+// resultado na aposta. Apostas fora da raiz de Merkle não concorrem. Cada
+// linha de uma aposta desdobrada conta como um ganhador na sua faixa; a
+// aposta guarda o maior acerto e as linhas por quantidade de acertos.
+func (sds *SorteioDataService) apurar(ctx context.Context, sorteio *model.Sorteio, game jogo.GameType) (*apuracao, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "data_type", Value: "aposta"},
+		{Key: "sorteio_id", Value: sorteio.ID},
+		{Key: "status", Value: model.ApostaAceita},
+		{Key: "merkle_indice", Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+
+	curr, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := &apuracao{ganhadores: make(map[int]int64)}
+	lote := make([]mongo.WriteModel, 0, tamanhoLote)
+
+	for curr.Next(ctx) {
+		aposta := &model.Aposta{}
+		if err := curr.Decode(aposta); err != nil {
+			return nil, err
+		}
+
+		result.apostas++
+		result.arrecadado += aposta.Valor
+
+		campos := bson.D{}
+		if len(aposta.Linhas) > 0 {
+			contagem := contarLinhas(game, aposta.Linhas, sorteio.Resultado)
+			for _, c := range contagem {
+				result.ganhadores[c.Acertos] += c.Linhas
+			}
+			campos = append(campos,
+				bson.E{Key: "acertos", Value: contagem[len(contagem)-1].Acertos},
+				bson.E{Key: "acertos_linhas", Value: contagem})
+		} else {
+			acertos := game.ContarAcertos(aposta.Numeros, sorteio.Resultado)
+			result.ganhadores[acertos]++
+			campos = append(campos, bson.E{Key: "acertos", Value: acertos})
+		}
+
+		lote = append(lote, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: aposta.ID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: campos}}))
+
+		if len(lote) == tamanhoLote {
+			if _, err := collection.BulkWrite(ctx, lote); err != nil {
+				return nil, err
+			}
+			lote = lote[:0]
+		}
+	}
+	if err := curr.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(lote) > 0 {
+		if _, err := collection.BulkWrite(ctx, lote); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// gravarPremios grava o prêmio por ganhador em todas as apostas simples de
+// cada faixa; as desdobradas recebem a soma dos prêmios das suas linhas.
+func (sds *SorteioDataService) gravarPremios(ctx context.Context, sorteio *model.Sorteio, faixas []model.RateioFaixa) error {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	for _, f := range faixas {
+		if f.Ganhadores == 0 {
+			continue
+		}
+
+		filter := bson.D{
+			{Key: "data_type", Value: "aposta"},
+			{Key: "sorteio_id", Value: sorteio.ID},
+			{Key: "status", Value: model.ApostaAceita},
+			{Key: "merkle_indice", Value: bson.D{{Key: "$exists", Value: true}}},
+			{Key: "linhas", Value: bson.D{{Key: "$exists", Value: false}}},
+			{Key: "acertos", Value: f.Acertos},
+		}
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "premio", Value: f.PremioPorGanhador}}}}
+
+		if _, err := collection.UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+
+	filter := bson.D{
+		{Key: "data_type", Value: "aposta"},
+		{Key: "sorteio_id", Value: sorteio.ID},
+		{Key: "status", Value: model.ApostaAceita},
+		{Key: "merkle_indice", Value: bson.D{{Key: "$exists", Value: true}}},
+		{Key: "linhas", Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+	opts := options.Find().SetProjection(bson.D{{Key: "acertos_linhas", Value: 1}})
+
+	curr, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer curr.Close(ctx)
+
+	lote := make([]mongo.WriteModel, 0, tamanhoLote)
+	for curr.Next(ctx) {
+		aposta := &model.Aposta{}
+		if err := curr.Decode(aposta); err != nil {
+			return err
+		}
+
+		var premio int64
+		for _, c := range aposta.AcertosLinhas {
+			premio += c.Linhas * rateio.PremioPorAcertos(faixas, c.Acertos)
+		}
+
+		lote = append(lote, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: aposta.ID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: "premio", Value: premio}}}}))
+
+		if len(lote) == tamanhoLote {
+			if _, err := collection.BulkWrite(ctx, lote); err != nil {
+				return err
+			}
+			lote = lote[:0]
+		}
+	}
+	if err := curr.Err(); err != nil {
+		return err
+	}
+
+	if len(lote) > 0 {
+		if _, err := collection.BulkWrite(ctx, lote); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// contarLinhas agrupa as linhas pela quantidade de acertos, em ordem crescente.
+func contarLinhas(game jogo.GameType, linhas [][]int, resultado []int) []model.AcertosLinhas {
+	porAcertos := make(map[int]int64)
+	for _, l := range linhas {
+		porAcertos[game.ContarAcertos(l, resultado)]++
+	}
+
+	result := make([]model.AcertosLinhas, 0, len(porAcertos))
+	for acertos, qtd := range porAcertos {
+		result = append(result, model.AcertosLinhas{Acertos: acertos, Linhas: qtd})
+	}
+	slices.SortFunc(result, func(a, b model.AcertosLinhas) int { return a.Acertos - b.Acertos })
+
+	return result
+}
+
+// ProvaInclusao devolve o caminho de Merkle da aposta até a raiz gravada no
+// fechamento, recalculando as folhas a partir das apostas comprometidas.
+func (sds *SorteioDataService) ProvaInclusao(ctx context.Context, ID string, apostaID string) (*model.ProvaInclusao, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	sorteio, err := sds.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sorteio.MerkleRaiz == "" {
+		return nil, ErrSemCompromisso
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(apostaID)
+	if err != nil {
+		return nil, ErrApostaNaoEncontrada
+	}
+
+	aposta := &model.Aposta{}
+	filter := bson.D{
+		{Key: "_id", Value: objectID},
+		{Key: "data_type", Value: "aposta"},
+		{Key: "sorteio_id", Value: sorteio.ID},
+	}
+	if err := collection.FindOne(ctx, filter).Decode(aposta); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrApostaNaoEncontrada
+		}
+		logger.Error("erro ao consultar Aposta", err)
+		return nil, err
+	}
+
+	if aposta.MerkleIndice == nil {
+		return nil, ErrApostaNaoComprometida
+	}
+
+	folhas, err := sds.folhasComprometidas(ctx, sorteio)
+	if err != nil {
+		logger.Error("erro ao carregar folhas do Sorteio", err)
+		return nil, err
+	}
+
+	raiz, err := hex.DecodeString(sorteio.MerkleRaiz)
+	if err != nil {
+		return nil, err
+	}
+
+	caminho, err := merkle.Prova(folhas, int(*aposta.MerkleIndice))
+	if err != nil {
+		return nil, ErrApostaNaoComprometida
+	}
+
+	dados := aposta.Canonica()
+	folha := merkle.Folha(dados)
+
+	passos := make([]model.PassoProva, len(caminho))
+	for i, p := range caminho {
+		passos[i] = model.PassoProva{Hash: p.Hash, Lado: p.Lado}
+	}
+
+	return &model.ProvaInclusao{
+		SorteioID: sorteio.ID,
+		ApostaID:  aposta.ID,
+		Indice:    *aposta.MerkleIndice,
+		Dados:     string(dados),
+		Folha:     hex.EncodeToString(folha),
+		Caminho:   passos,
+		Raiz:      sorteio.MerkleRaiz,
+		Valida:    merkle.VerificarProva(folha, caminho, raiz),
+	}, nil
+}
+
+// comprometerApostas numera as apostas aceitas do sorteio em ordem de _id,
+// grava o índice de cada uma e registra a raiz de Merkle do conjunto. A raiz
+// só é gravada uma vez; uma segunda chamada não altera o compromisso.
+func (sds *SorteioDataService) comprometerApostas(ctx context.Context, sorteio *model.Sorteio) error {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "data_type", Value: "aposta"},
+		{Key: "sorteio_id", Value: sorteio.ID},
+		{Key: "status", Value: model.ApostaAceita},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	curr, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer curr.Close(ctx)
+
+	folhas := make([][]byte, 0)
+	lote := make([]mongo.WriteModel, 0, tamanhoLote)
+
+	for curr.Next(ctx) {
+		aposta := &model.Aposta{}
+		if err := curr.Decode(aposta); err != nil {
+			return err
+		}
+
+		lote = append(lote, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: aposta.ID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: "merkle_indice", Value: int64(len(folhas))}}}}))
+		folhas = append(folhas, merkle.Folha(aposta.Canonica()))
+
+		if len(lote) == tamanhoLote {
+			if _, err := collection.BulkWrite(ctx, lote); err != nil {
+				return err
+			}
+			lote = lote[:0]
+		}
+	}
+	if err := curr.Err(); err != nil {
+		return err
+	}
+
+	if len(lote) > 0 {
+		if _, err := collection.BulkWrite(ctx, lote); err != nil {
+			return err
+		}
+	}
+
+	raiz := hex.EncodeToString(merkle.Raiz(folhas))
+
+	result, err := collection.UpdateOne(ctx,
+		bson.D{
+			{Key: "_id", Value: sorteio.ID},
+			{Key: "data_type", Value: "sorteio"},
+			{Key: "merkle_raiz", Value: bson.D{{Key: "$in", Value: bson.A{"", nil}}}},
+		},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "merkle_raiz", Value: raiz},
+			{Key: "merkle_folhas", Value: int64(len(folhas))},
+			{Key: "updated_at", Value: time.Now().Format(time.RFC3339)},
+		}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Outra chamada gravou o compromisso antes: vale a raiz que está no banco.
+	if result.MatchedCount != 1 {
+		gravado, err := sds.GetByID(ctx, sorteio.ID.Hex())
+		if err != nil {
+			return err
+		}
+		sorteio.MerkleRaiz = gravado.MerkleRaiz
+		sorteio.MerkleFolhas = gravado.MerkleFolhas
+		return nil
+	}
+
+	sorteio.MerkleRaiz = raiz
+	sorteio.MerkleFolhas = int64(len(folhas))
+
+	return nil
+}
+
+// folhasComprometidas recalcula as folhas das apostas na ordem do compromisso.
+func (sds *SorteioDataService) folhasComprometidas(ctx context.Context, sorteio *model.Sorteio) ([][]byte, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	filter := bson.D{
+		{Key: "data_type", Value: "aposta"},
+		{Key: "sorteio_id", Value: sorteio.ID},
+		{Key: "merkle_indice", Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "merkle_indice", Value: 1}})
+
+	curr, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	folhas := make([][]byte, 0, sorteio.MerkleFolhas)
+	for curr.Next(ctx) {
+		aposta := &model.Aposta{}
+		if err := curr.Decode(aposta); err != nil {
+			return nil, err
+		}
+		folhas = append(folhas, merkle.Folha(aposta.Canonica()))
+	}
+
+	return folhas, curr.Err()
+}
+
+// transicionar grava a mudança de status e executa as etapas de AoTransicionar.
+func (sds *SorteioDataService) transicionar(ctx context.Context, sorteio *model.Sorteio, para, motivo string, campos bson.D) (*model.Sorteio, error) {
+	atualizado, err := sds.gravarTransicao(ctx, sorteio, para, motivo, campos)
+	if err != nil {
+		return nil, err
+	}
+
+	sds.executarEtapas(ctx, atualizado, sds.aoTransicionar)
+
+	return atualizado, nil
+}
+
+// gravarTransicao grava a mudança de status condicionada ao status lido, de
+// modo que duas requisições concorrentes não consigam aplicar a mesma
+// transição. Com o contexto de uma transação, a escrita entra nela.
+func (sds *SorteioDataService) gravarTransicao(ctx context.Context, sorteio *model.Sorteio, para, motivo string, campos bson.D) (*model.Sorteio, error) {
+	collection := sds.mdb.GetCollection("cfStore")
+
+	if !sorteio.PodeTransitar(para) {
+		return nil, ErrTransicaoInvalida
+	}
+
+	agora := time.Now()
+	transicao := model.TransicaoSorteio{
+		De:     sorteio.Status,
+		Para:   para,
+		Motivo: motivo,
+		Data:   agora,
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: sorteio.ID},
+		{Key: "data_type", Value: "sorteio"},
+		{Key: "status", Value: sorteio.Status},
+	}
+
+	values := bson.D{
+		{Key: "status", Value: para},
+		{Key: "updated_at", Value: agora.Format(time.RFC3339)},
+	}
+	values = append(values, campos...)
+
+	update := bson.D{
+		{Key: "$set", Value: values},
+		{Key: "$push", Value: bson.D{{Key: "historico", Value: transicao}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	atualizado := &model.Sorteio{}
+	err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(atualizado)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTransicaoConcorrente
+		}
+		logger.Error("erro ao atualizar status do Sorteio", err)
+		return nil, err
+	}
+
+	return atualizado, nil
+}
+
+const tamanhoLote = 1000
+
+func validarSorteio(sorteio model.Sorteio) error {
+	if _, err := jogo.Obter(sorteio.Jogo); err != nil {
+		return fmt.Errorf("%w: jogo %q não registrado", ErrSorteioInvalido, sorteio.Jogo)
+	}
+	if sorteio.ValorAposta <= 0 {
+		return fmt.Errorf("%w: valor da aposta deve ser informado em centavos", ErrSorteioInvalido)
+	}
+	if sorteio.PercentualPremio < 0 || sorteio.PercentualPremio > model.BaseCalculo {
+		return fmt.Errorf("%w: percentual do prêmio deve estar entre 0 e %d", ErrSorteioInvalido, model.BaseCalculo)
+	}
+	if err := rateio.ValidarTabela(sorteio.TabelaPremios); err != nil {
+		return fmt.Errorf("%w: %s", ErrSorteioInvalido, err.Error())
+	}
+	if !sorteio.DataAbertura.IsZero() && !sorteio.DataFechamento.IsZero() &&
+		!sorteio.DataFechamento.After(sorteio.DataAbertura) {
+		return fmt.Errorf("%w: data de fechamento deve ser posterior à abertura", ErrSorteioInvalido)
+	}
+	return nil
+}
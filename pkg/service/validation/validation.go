@@ -155,3 +155,25 @@ func CareString(word string) string {
 	newWord = caser.String(newWord)
 	return newWord
 }
+
+var (
+	emailRegex    = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}$`)
+	telefoneRegex = regexp.MustCompile(`^\+55[1-9][0-9]9?[0-9]{8}$`)
+	evpRegex      = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+)
+
+// IsEmailValid valida o e-mail no formato aceito como chave Pix (até 77
+// caracteres, minúsculo).
+func IsEmailValid(email string) bool {
+	return len(email) <= 77 && emailRegex.MatchString(email)
+}
+
+// IsTelefoneValid valida o celular no formato da chave Pix: +55, DDD e número.
+func IsTelefoneValid(telefone string) bool {
+	return telefoneRegex.MatchString(telefone)
+}
+
+// IsEVPValid valida a chave aleatória do Pix, um UUID em minúsculas.
+func IsEVPValid(evp string) bool {
+	return evpRegex.MatchString(evp)
+}